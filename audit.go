@@ -0,0 +1,182 @@
+package redmineclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditRecord запись журнала изменений, выполненных клиентом
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// User логин владельца учётных данных клиента
+	User string `json:"user,omitempty"`
+	// OnBehalfOf логин пользователя, от имени которого выполнен запрос через X-Redmine-Switch-User
+	OnBehalfOf string `json:"on_behalf_of,omitempty"`
+	Method     string `json:"method"`
+	// Endpoint путь запроса относительно адреса redmine, включая параметры
+	Endpoint string `json:"endpoint"`
+	// Payload тело запроса, пароли и ключи скрыты
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// Status код ответа, 0 если ответ не получен
+	Status int `json:"status"`
+	// EntityID идентификатор созданной или изменённой сущности
+	EntityID int    `json:"entity_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// AuditSink получатель записей журнала изменений
+type AuditSink interface {
+	Record(record AuditRecord) error
+}
+
+// AuditFile журнал изменений в файле формата JSON Lines, записи только добавляются
+type AuditFile struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenAuditFile открытие журнала для дозаписи, файл создаётся при отсутствии
+func OpenAuditFile(path string) (*AuditFile, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("redmineclient: open audit file: %w", err)
+	}
+
+	return &AuditFile{file: file}, nil
+}
+
+// Record запись одной строкой
+func (audit *AuditFile) Record(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+	_, err = audit.file.Write(append(line, '\n'))
+
+	return err
+}
+
+// Close закрытие файла журнала
+func (audit *AuditFile) Close() error {
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+
+	return audit.file.Close()
+}
+
+// auditLog журнал изменений клиента и логин владельца ключа клиента
+type auditLog struct {
+	sink AuditSink
+	user cachedLogin
+}
+
+// cachedLogin логин владельца учётных данных, запрашивается один раз
+type cachedLogin struct {
+	mu    sync.Mutex
+	login string
+}
+
+// WithAudit запись каждого вызова Create*, Update* и Delete* в sink.
+// Логин владельца учётных данных определяется запросом /users/current.json один раз для клиента
+// и один раз для каждой копии с Auth, при смене пользователя (As) его логин пишется в OnBehalfOf.
+// Ошибки записи передаются обработчику WithErrorHandler
+func WithAudit(sink AuditSink) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.transport.audit = &auditLog{sink: sink}
+		return nil
+	}
+}
+
+// auditCall выполнение изменяющего вызова с записью в журнал
+func (arc *ApiRedmineClient) auditCall(call *Call, next CallHandler) {
+	record := AuditRecord{
+		Method:     call.Method,
+		Endpoint:   arc.callPath(call),
+		User:       arc.authenticatedUser(),
+		OnBehalfOf: arc.scope.switchUser,
+	}
+	if call.Method != http.MethodDelete && call.Body != nil {
+		if body, err := json.Marshal(call.Body); err == nil {
+			record.Payload = redactJSON(body)
+		}
+	}
+
+	stats := callStats(call)
+	next(call)
+
+	record.Time = time.Now()
+	record.Status = stats.statusCode
+	record.EntityID = auditEntityID(call)
+	if call.Err != nil {
+		record.Error = call.Err.Error()
+	}
+	if err := arc.transport.audit.sink.Record(record); err != nil && arc.transport.errorHandler != nil {
+		arc.transport.errorHandler(fmt.Errorf("redmineclient: audit: %w", err))
+	}
+}
+
+// authenticatedUser логин владельца учётных данных клиента или копии с Auth.
+// Запрос выполняется без смены пользователя и параметров копии клиента
+func (arc *ApiRedmineClient) authenticatedUser() string {
+	cache := &arc.transport.audit.user
+	if arc.scope.authenticator != nil && arc.scope.authLogin != nil {
+		cache = arc.scope.authLogin
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.login != "" {
+		return cache.login
+	}
+
+	var err error
+	lookup := *arc
+	lookup.scope = requestScope{ctx: arc.scope.ctx, authenticator: arc.scope.authenticator, err: &err}
+	user := lookup.GetCurrentUser()
+	if err != nil {
+		return ""
+	}
+	cache.login = user.Login
+
+	return user.Login
+}
+
+// auditEntityID идентификатор из ответа на создание или из пути запроса
+func auditEntityID(call *Call) int {
+	if call.Result != nil {
+		if data, err := json.Marshal(call.Result); err == nil {
+			fields := map[string]json.RawMessage{}
+			if json.Unmarshal(data, &fields) == nil {
+				if id := jsonID(fields); id != 0 {
+					return id
+				}
+				for _, value := range fields {
+					nested := map[string]json.RawMessage{}
+					if json.Unmarshal(value, &nested) == nil && jsonID(nested) != 0 {
+						return jsonID(nested)
+					}
+				}
+			}
+		}
+	}
+
+	path := strings.SplitN(call.Path, "?", 2)[0]
+	id, _ := strconv.Atoi(strings.TrimSuffix(path[strings.LastIndex(path, "/")+1:], ".json"))
+
+	return id
+}
+
+func jsonID(fields map[string]json.RawMessage) int {
+	id := 0
+	json.Unmarshal(fields["id"], &id)
+
+	return id
+}
@@ -0,0 +1,126 @@
+package redmineclient_test
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+type memoryAudit struct {
+	mu      sync.Mutex
+	records []redmineclient.AuditRecord
+}
+
+func (audit *memoryAudit) Record(record redmineclient.AuditRecord) error {
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+	audit.records = append(audit.records, record)
+	return nil
+}
+
+// headerAuth несравнимый способ аутентификации: map в значении
+type headerAuth struct {
+	headers map[string]string
+}
+
+func (auth headerAuth) Authenticate(req *http.Request) {
+	for name, value := range auth.headers {
+		req.Header.Set(name, value)
+	}
+}
+
+// newAuditClient клиент с журналом изменений и счётчиком запросов текущего пользователя
+func newAuditClient(t *testing.T) (*redminetest.Server, *redmineclient.ApiRedmineClient, *memoryAudit, *int) {
+	t.Helper()
+	srv := redminetest.NewServer()
+	t.Cleanup(srv.Close)
+
+	lookups := 0
+	countLookups := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/users/current.json" {
+				lookups++
+				if login := req.Header.Get("X-Redmine-Switch-User"); login != "" {
+					t.Errorf("current user requested as %q", login)
+				}
+			}
+			return next.RoundTrip(req)
+		})
+	}
+	audit := &memoryAudit{}
+	client := srv.Client(redmineclient.WithAudit(audit), redmineclient.WithSendMiddleware(countLookups))
+
+	return srv, client, audit, &lookups
+}
+
+func TestAuditRecords(t *testing.T) {
+	_, client, audit, lookups := newAuditClient(t)
+	project := client.CreateProject(&redmineclient.RdProject{Name: "Demo", Identifier: "demo"})
+	client.CreateUser(&redmineclient.RdUser{Login: "jsmith", Firstname: "John", Lastname: "Smith", Mail: "jsmith@example.net", Password: "s3cret-passw0rd"})
+	issue := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "audited"})
+	client.GetIssue(issue.ID)
+	client.DeleteIssue(issue.ID)
+
+	if len(audit.records) != 4 {
+		t.Fatalf("%d records, want 4: %+v", len(audit.records), audit.records)
+	}
+	for _, record := range audit.records {
+		if record.User != redminetest.AdminLogin || record.OnBehalfOf != "" || record.Time.IsZero() {
+			t.Errorf("record %+v, want user %q", record, redminetest.AdminLogin)
+		}
+	}
+	if record := audit.records[1]; record.Method != "POST" || record.Endpoint != "/users.json" || strings.Contains(string(record.Payload), "s3cret") {
+		t.Errorf("user record %+v %s", record, record.Payload)
+	}
+	if record := audit.records[3]; record.Method != "DELETE" || record.EntityID != issue.ID || record.Status != http.StatusNoContent && record.Status != http.StatusOK {
+		t.Errorf("delete record %+v", record)
+	}
+	if *lookups != 1 {
+		t.Errorf("current user requested %d times, want once", *lookups)
+	}
+}
+
+func TestAuditImpersonation(t *testing.T) {
+	srv, client, audit, lookups := newAuditClient(t)
+	srv.AddUser("jsmith", true)
+
+	project := client.As("jsmith").CreateProject(&redmineclient.RdProject{Name: "Demo", Identifier: "demo"})
+	client.As("jsmith").UpdateProject(&redmineclient.RdProject{ID: project.ID, Name: "Renamed"})
+
+	if len(audit.records) != 2 {
+		t.Fatalf("%d records, want 2", len(audit.records))
+	}
+	for _, record := range audit.records {
+		if record.User != redminetest.AdminLogin || record.OnBehalfOf != "jsmith" {
+			t.Errorf("record user %q on behalf of %q, want %q on behalf of jsmith", record.User, record.OnBehalfOf, redminetest.AdminLogin)
+		}
+	}
+	if *lookups != 1 {
+		t.Errorf("current user requested %d times, want once", *lookups)
+	}
+}
+
+func TestAuditCachesNonComparableAuthenticator(t *testing.T) {
+	srv, client, audit, lookups := newAuditClient(t)
+	_, key := srv.AddUser("jsmith", true)
+
+	scoped := client.With(redmineclient.Auth(headerAuth{headers: map[string]string{"X-Redmine-API-Key": key}}))
+	scoped.CreateProject(&redmineclient.RdProject{Name: "One", Identifier: "one"})
+	scoped.CreateProject(&redmineclient.RdProject{Name: "Two", Identifier: "two"})
+	scoped.With(redmineclient.Locale("ru")).CreateProject(&redmineclient.RdProject{Name: "Three", Identifier: "three"})
+	client.CreateProject(&redmineclient.RdProject{Name: "Four", Identifier: "four"})
+
+	want := []string{"jsmith", "jsmith", "jsmith", redminetest.AdminLogin}
+	for i, record := range audit.records {
+		if record.User != want[i] {
+			t.Errorf("record %d user %q, want %q", i, record.User, want[i])
+		}
+	}
+	if *lookups != 2 {
+		t.Errorf("current user requested %d times, want once per credentials", *lookups)
+	}
+}
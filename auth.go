@@ -0,0 +1,80 @@
+package redmineclient
+
+import (
+	"net/http"
+)
+
+// Authenticator способ аутентификации запросов к redmine
+type Authenticator interface {
+	Authenticate(req *http.Request)
+}
+
+// APIKeyHeader аутентификация ключом API в заголовке X-Redmine-API-Key
+func APIKeyHeader(key string) Authenticator {
+	return apiKeyHeader(key)
+}
+
+// APIKeyQuery аутентификация ключом API в параметре запроса key
+func APIKeyQuery(key string) Authenticator {
+	return apiKeyQuery(key)
+}
+
+// BasicAuth аутентификация логином и паролем
+func BasicAuth(login, password string) Authenticator {
+	return &basicAuth{login: login, password: password}
+}
+
+type apiKeyHeader string
+
+func (key apiKeyHeader) Authenticate(req *http.Request) {
+	req.Header.Set("X-Redmine-API-Key", string(key))
+}
+
+func (key apiKeyHeader) String() string {
+	return "APIKeyHeader(***)"
+}
+
+func (key apiKeyHeader) GoString() string {
+	return key.String()
+}
+
+type apiKeyQuery string
+
+func (key apiKeyQuery) Authenticate(req *http.Request) {
+	query := req.URL.Query()
+	query.Set("key", string(key))
+	req.URL.RawQuery = query.Encode()
+}
+
+func (key apiKeyQuery) String() string {
+	return "APIKeyQuery(***)"
+}
+
+func (key apiKeyQuery) GoString() string {
+	return key.String()
+}
+
+type basicAuth struct {
+	login    string
+	password string
+}
+
+func (auth *basicAuth) Authenticate(req *http.Request) {
+	req.SetBasicAuth(auth.login, auth.password)
+}
+
+func (auth *basicAuth) String() string {
+	return "BasicAuth(" + auth.login + ", ***)"
+}
+
+func (auth *basicAuth) GoString() string {
+	return auth.String()
+}
+
+// WithAuthenticator способ аутентификации вместо ключа API из NewApiRedmineClient
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.transport.authenticator = authenticator
+		return nil
+	}
+}
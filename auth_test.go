@@ -0,0 +1,80 @@
+package redmineclient_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+func TestAuthenticators(t *testing.T) {
+	tests := []struct {
+		name          string
+		authenticator redmineclient.Authenticator
+		wantLogin     string
+	}{
+		{"api key header", redmineclient.APIKeyHeader(redminetest.AdminAPIKey), redminetest.AdminLogin},
+		{"api key query", redmineclient.APIKeyQuery(redminetest.AdminAPIKey), redminetest.AdminLogin},
+		{"basic auth with api key", redmineclient.BasicAuth(redminetest.AdminAPIKey, "x"), redminetest.AdminLogin},
+		{"invalid key", redmineclient.APIKeyHeader("wrong"), ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := redminetest.NewServer()
+			defer srv.Close()
+
+			client := redmineclient.NewApiRedmineClient("", srv.URL, redmineclient.WithAuthenticator(test.authenticator))
+			if user := client.GetCurrentUser(); user.Login != test.wantLogin {
+				t.Errorf("current user %q, want %q", user.Login, test.wantLogin)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorOnlyForRedmineHost(t *testing.T) {
+	tests := []struct {
+		name          string
+		authenticator redmineclient.Authenticator
+	}{
+		{"api key header", redmineclient.APIKeyHeader("secret")},
+		{"api key query", redmineclient.APIKeyQuery("secret")},
+		{"basic auth", redmineclient.BasicAuth("admin", "secret")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var leaked []string
+			other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for _, value := range []string{r.Header.Get("X-Redmine-API-Key"), r.Header.Get("Authorization"), r.URL.Query().Get("key")} {
+					if value != "" {
+						leaked = append(leaked, value)
+					}
+				}
+				w.Write([]byte(`{"issue": {"id": 1, "subject": "moved"}}`))
+			}))
+			defer other.Close()
+
+			authenticated := false
+			redmine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _, basic := r.BasicAuth()
+				authenticated = r.Header.Get("X-Redmine-API-Key") != "" || r.URL.Query().Get("key") != "" || basic
+				http.Redirect(w, r, other.URL+r.URL.Path, http.StatusFound)
+			}))
+			defer redmine.Close()
+
+			client := redmineclient.NewApiRedmineClient("", redmine.URL, redmineclient.WithAuthenticator(test.authenticator))
+			if issue := client.GetIssue(1); issue.Subject != "moved" {
+				t.Fatalf("issue %+v, want redirected response", issue)
+			}
+			if !authenticated {
+				t.Error("request to redmine was not authenticated")
+			}
+			if len(leaked) > 0 {
+				t.Errorf("credentials sent to another host: %q", leaked)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package redmineclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CassetteMode режим работы кассеты
+type CassetteMode int
+
+const (
+	// CassetteReplay ответы только из файла, запрос без записи завершается ошибкой
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord запросы отправляются на сервер, ответы записываются в файл
+	CassetteRecord
+)
+
+// CassetteOptions параметры кассеты
+type CassetteOptions struct {
+	Mode CassetteMode
+	// Transport транспорт для записи, по умолчанию http.DefaultTransport
+	Transport http.RoundTripper
+	// OnMismatch вызывается для запроса без записи при воспроизведении, например t.Fatal
+	OnMismatch func(err error)
+}
+
+// CassetteRequest записанный запрос, учётные данные скрыты
+type CassetteRequest struct {
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Query   string      `json:"query,omitempty"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// CassetteResponse записанный ответ, учётные данные скрыты
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// CassetteInteraction запрос и ответ
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+type cassetteFile struct {
+	Interactions []*CassetteInteraction `json:"interactions"`
+}
+
+// CassetteMismatchError запрос, для которого в кассете нет записи или все его записи уже воспроизведены
+type CassetteMismatchError struct {
+	Cassette string
+	Method   string
+	Path     string
+	Query    string
+}
+
+func (err *CassetteMismatchError) Error() string {
+	request := err.Method + " " + err.Path
+	if err.Query != "" {
+		request += "?" + err.Query
+	}
+
+	return fmt.Sprintf("redmineclient: cassette %v has no unplayed recorded response for %v", err.Cassette, request)
+}
+
+// Cassette транспорт записи и воспроизведения запросов для детерминированных тестов.
+// Запросы сопоставляются по методу, пути и нормализованным параметрам (без key),
+// одинаковые запросы воспроизводятся в порядке записи, каждая запись один раз: лишний запрос
+// завершается *CassetteMismatchError. Подключается через WithTransport
+type Cassette struct {
+	path    string
+	options CassetteOptions
+
+	mu           sync.Mutex
+	interactions []*CassetteInteraction
+	replayed     map[*CassetteInteraction]bool
+}
+
+// NewCassette кассета в файле path. При воспроизведении файл должен существовать,
+// при записи он перезаписывается после каждого запроса
+func NewCassette(path string, options *CassetteOptions) (*Cassette, error) {
+	cassette := &Cassette{path: path, replayed: map[*CassetteInteraction]bool{}}
+	if options != nil {
+		cassette.options = *options
+	}
+	if cassette.options.Transport == nil {
+		cassette.options.Transport = http.DefaultTransport
+	}
+	if cassette.options.Mode == CassetteRecord {
+		return cassette, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("redmineclient: read cassette: %w", err)
+	}
+	file := cassetteFile{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("redmineclient: parse cassette %v: %w", path, err)
+	}
+	cassette.interactions = file.Interactions
+
+	return cassette, nil
+}
+
+// Interactions записанные запросы и ответы
+func (cassette *Cassette) Interactions() []CassetteInteraction {
+	cassette.mu.Lock()
+	defer cassette.mu.Unlock()
+
+	interactions := make([]CassetteInteraction, 0, len(cassette.interactions))
+	for _, interaction := range cassette.interactions {
+		interactions = append(interactions, *interaction)
+	}
+
+	return interactions
+}
+
+func (cassette *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	if cassette.options.Mode == CassetteRecord {
+		return cassette.record(req)
+	}
+
+	return cassette.replay(req)
+}
+
+// normalizeQuery параметры запроса в отсортированном виде без ключа API
+func normalizeQuery(query url.Values) string {
+	query = cloneValues(query)
+	query.Del("key")
+	for _, values := range query {
+		sort.Strings(values)
+	}
+
+	return query.Encode()
+}
+
+func cloneValues(values url.Values) url.Values {
+	cloned := url.Values{}
+	for name, items := range values {
+		cloned[name] = append([]string{}, items...)
+	}
+
+	return cloned
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(data))
+
+	return data, err
+}
+
+func (cassette *Cassette) record(req *http.Request) (*http.Response, error) {
+	requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cassette.options.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	interaction := &CassetteInteraction{
+		Request: CassetteRequest{
+			Method:  req.Method,
+			Path:    req.URL.Path,
+			Query:   normalizeQuery(req.URL.Query()),
+			Headers: redactHeaders(req.Header),
+			Body:    string(redactJSON(requestBody)),
+		},
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    redactHeaders(resp.Header),
+			Body:       string(redactJSON(responseBody)),
+		},
+	}
+
+	cassette.mu.Lock()
+	defer cassette.mu.Unlock()
+	cassette.interactions = append(cassette.interactions, interaction)
+	if err := cassette.save(); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (cassette *Cassette) save() error {
+	data, err := json.MarshalIndent(cassetteFile{Interactions: cassette.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cassette.path), 0o755); err != nil {
+		return fmt.Errorf("redmineclient: write cassette: %w", err)
+	}
+	if err := os.WriteFile(cassette.path, data, 0o644); err != nil {
+		return fmt.Errorf("redmineclient: write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// match первая невоспроизведённая запись запроса, nil если записей не осталось
+func (cassette *Cassette) match(method, path, query string) *CassetteInteraction {
+	for _, interaction := range cassette.interactions {
+		request := interaction.Request
+		if request.Method != method || request.Path != path || request.Query != query {
+			continue
+		}
+		if !cassette.replayed[interaction] {
+			cassette.replayed[interaction] = true
+			return interaction
+		}
+	}
+
+	return nil
+}
+
+func (cassette *Cassette) replay(req *http.Request) (*http.Response, error) {
+	query := normalizeQuery(req.URL.Query())
+
+	cassette.mu.Lock()
+	interaction := cassette.match(req.Method, req.URL.Path, query)
+	cassette.mu.Unlock()
+
+	if interaction == nil {
+		err := &CassetteMismatchError{Cassette: cassette.path, Method: req.Method, Path: req.URL.Path, Query: query}
+		if cassette.options.OnMismatch != nil {
+			cassette.options.OnMismatch(err)
+		}
+		return nil, err
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	response := interaction.Response
+	header := response.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+		StatusCode:    response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
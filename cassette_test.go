@@ -0,0 +1,105 @@
+package redmineclient_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "projects.json")
+	srv := redminetest.NewServer()
+	recorder, err := redmineclient.NewCassette(path, &redmineclient.CassetteOptions{Mode: redmineclient.CassetteRecord})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := srv.Client(redmineclient.WithTransport(recorder))
+	project := client.CreateProject(&redmineclient.RdProject{Name: "Demo", Identifier: "demo"})
+	issue := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "recorded"})
+	projects := client.GetProjectList("limit=10", "offset=0")
+	srv.Close()
+
+	if len(recorder.Interactions()) != 3 {
+		t.Fatalf("%d interactions recorded, want 3", len(recorder.Interactions()))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), redminetest.AdminAPIKey) {
+		t.Error("cassette contains the API key")
+	}
+
+	var mismatches []error
+	player, err := redmineclient.NewCassette(path, &redmineclient.CassetteOptions{OnMismatch: func(err error) { mismatches = append(mismatches, err) }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// сервер остановлен: ответы только из кассеты, ключ и порядок параметров не важны
+	replay := redmineclient.NewApiRedmineClient("another key", "http://127.0.0.1:1", redmineclient.WithTransport(player))
+	if replayed := replay.CreateProject(&redmineclient.RdProject{Name: "Demo", Identifier: "demo"}); replayed.ID != project.ID {
+		t.Errorf("replayed project #%d, want #%d", replayed.ID, project.ID)
+	}
+	if replayed := replay.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "recorded"}); replayed.ID != issue.ID {
+		t.Errorf("replayed issue #%d, want #%d", replayed.ID, issue.ID)
+	}
+	if replayed := replay.GetProjectList("offset=0", "limit=10"); len(replayed) != len(projects) {
+		t.Errorf("replayed %d projects, want %d", len(replayed), len(projects))
+	}
+	if len(mismatches) != 0 {
+		t.Fatalf("unexpected mismatches %v", mismatches)
+	}
+
+	tests := []struct {
+		name string
+		call func()
+	}{
+		{"not recorded", func() { replay.GetIssue(issue.ID) }},
+		{"already replayed", func() { replay.CreateProject(&redmineclient.RdProject{Name: "Demo", Identifier: "demo"}) }},
+		{"other query", func() { replay.GetProjectList("offset=10", "limit=10") }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mismatches = nil
+			test.call()
+
+			var mismatch *redmineclient.CassetteMismatchError
+			if len(mismatches) != 1 || !errors.As(mismatches[0], &mismatch) {
+				t.Errorf("mismatches = %v, want one *CassetteMismatchError", mismatches)
+			}
+		})
+	}
+}
+
+func TestCassetteRecordSaveError(t *testing.T) {
+	// родитель кассеты файл, поэтому записать кассету нельзя
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := redminetest.NewServer()
+	defer srv.Close()
+	recorder, err := redmineclient.NewCassette(filepath.Join(blocker, "cassette.json"), &redmineclient.CassetteOptions{Mode: redmineclient.CassetteRecord})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/users/current.json", nil)
+	req.Header.Set("X-Redmine-API-Key", redminetest.AdminAPIKey)
+	resp, err := recorder.RoundTrip(req)
+	if err == nil || !strings.Contains(err.Error(), "write cassette") || resp != nil {
+		t.Fatalf("RoundTrip = %v, %v, want nil response and write error", resp, err)
+	}
+
+	var callErr error
+	client := srv.Client(redmineclient.WithTransport(recorder))
+	if user := client.With(redmineclient.CaptureError(&callErr)).GetCurrentUser(); user.ID != 0 || callErr == nil {
+		t.Errorf("GetCurrentUser = %+v, error %v, want write error", user, callErr)
+	}
+}
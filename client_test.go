@@ -0,0 +1,32 @@
+package redmineclient_test
+
+import (
+	"testing"
+	"time"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+// newClient фейковый сервер, клиент к нему и проект demo, любая ошибка запроса проваливает тест
+func newClient(t *testing.T, options ...redmineclient.Option) (*redminetest.Server, *redmineclient.ApiRedmineClient, *redmineclient.RdProject) {
+	t.Helper()
+	srv := redminetest.NewServer()
+	t.Cleanup(srv.Close)
+
+	options = append(options, redmineclient.WithErrorHandler(func(err error) {
+		t.Errorf("request failed: %v", err)
+	}))
+	client := srv.Client(options...)
+	project := client.CreateProject(&redmineclient.RdProject{Name: "Demo", Identifier: "demo"})
+	if project.ID == 0 {
+		t.Fatal("project was not created")
+	}
+
+	return srv, client, project
+}
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse(redmineclient.DateFormat, value)
+	return parsed
+}
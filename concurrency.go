@@ -0,0 +1,289 @@
+package redmineclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConflictError сущность изменена на сервере после того, как её прочитал клиент, запись не выполнена
+type ConflictError struct {
+	Method string
+	Path   string
+	// ExpectedUpdatedOn и ActualUpdatedOn updated_on, с которым ожидалось обновление, и текущий на сервере
+	ExpectedUpdatedOn time.Time
+	ActualUpdatedOn   time.Time
+	// ExpectedVersion и ActualVersion версии страницы wiki
+	ExpectedVersion int
+	ActualVersion   int
+	// Err ответ 409 Conflict для страниц wiki
+	Err error
+}
+
+func (err *ConflictError) Error() string {
+	if err.ExpectedVersion != 0 {
+		return fmt.Sprintf("redmineclient: %v %v: conflict: expected version %d, server has %d",
+			err.Method, err.Path, err.ExpectedVersion, err.ActualVersion)
+	}
+
+	return fmt.Sprintf("redmineclient: %v %v: conflict: expected updated_on %v, server has %v",
+		err.Method, err.Path, err.ExpectedUpdatedOn.Format(time.RFC3339), err.ActualUpdatedOn.Format(time.RFC3339))
+}
+
+func (err *ConflictError) Unwrap() error {
+	return err.Err
+}
+
+// IfUpdatedOn обновления копии клиента выполняются, только если updated_on сущности
+// на сервере равен updatedOn, иначе запись не выполняется и возвращается *ConflictError.
+// Проверка выполняется отдельным GET перед PUT, поэтому изменение между ними не обнаруживается.
+// Для сущностей без updated_on (например, участников проекта) запись не выполняется и возвращается ошибка
+func IfUpdatedOn(updatedOn time.Time) RequestOption {
+	return func(scope *requestScope) {
+		scope.expectUpdatedOn = updatedOn
+	}
+}
+
+// IfVersion обновление страницы wiki с версии version, проверяется самим redmine (409 Conflict),
+// при конфликте возвращается *ConflictError
+func IfVersion(version int) RequestOption {
+	return func(scope *requestScope) {
+		scope.expectVersion = version
+	}
+}
+
+// TrackVersions копия клиента запоминает updated_on и версии страниц wiki прочитанных сущностей
+// и не записывает их, если на сервере они изменились после чтения:
+//
+//	bot := client.With(redmineclient.TrackVersions())
+//	issue := bot.GetIssue(id)
+//	bot.UpdateIssue(&redmineclient.RdIssue{ID: id, Subject: issue.Subject + "!"})
+func TrackVersions() RequestOption {
+	return func(scope *requestScope) {
+		scope.versions = &versionTracker{marks: map[string]versionMark{}}
+	}
+}
+
+// versionMark updated_on и версия wiki прочитанной сущности
+type versionMark struct {
+	updatedOn time.Time
+	version   int
+}
+
+type versionTracker struct {
+	mu    sync.Mutex
+	marks map[string]versionMark
+}
+
+func (tracker *versionTracker) get(path string) (versionMark, bool) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	mark, ok := tracker.marks[versionKey(path)]
+	return mark, ok
+}
+
+func (tracker *versionTracker) set(path string, mark versionMark) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.marks[versionKey(path)] = mark
+}
+
+// versionKey путь сущности без параметров и расширения
+func versionKey(path string) string {
+	return strings.TrimSuffix(strings.SplitN(path, "?", 2)[0], ".json")
+}
+
+func isWikiPath(path string) bool {
+	return strings.Contains(path, "/wiki/")
+}
+
+// rawResult сохранение тела ответа при декодировании в результат вызова
+type rawResult struct {
+	raw    json.RawMessage
+	result interface{}
+}
+
+func (tee *rawResult) UnmarshalJSON(data []byte) error {
+	tee.raw = append(tee.raw[:0], data...)
+	if tee.result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(data, tee.result)
+}
+
+// parseVersionMark updated_on и версия из ответа вида {"issue": {...}}
+func parseVersionMark(data json.RawMessage) (versionMark, bool) {
+	wrapper := map[string]json.RawMessage{}
+	if json.Unmarshal(data, &wrapper) != nil || len(wrapper) != 1 {
+		return versionMark{}, false
+	}
+	for _, entity := range wrapper {
+		fields := struct {
+			UpdatedOn time.Time `json:"updated_on"`
+			Version   int       `json:"version"`
+		}{}
+		if json.Unmarshal(entity, &fields) == nil {
+			return versionMark{updatedOn: fields.UpdatedOn, version: fields.Version}, true
+		}
+	}
+
+	return versionMark{}, false
+}
+
+// trackedGet чтение сущности с запоминанием её updated_on и версии
+func (arc *ApiRedmineClient) trackedGet(call *Call, next CallHandler) {
+	tee := &rawResult{result: call.Result}
+	call.Result = tee
+	next(call)
+	call.Result = tee.result
+
+	if call.Err == nil {
+		if mark, ok := parseVersionMark(tee.raw); ok {
+			arc.scope.versions.set(call.Path, mark)
+		}
+	}
+}
+
+// fetchVersionMark текущие updated_on и версия сущности на сервере
+func (arc *ApiRedmineClient) fetchVersionMark(path string) (versionMark, error) {
+	var err error
+	tee := &rawResult{}
+	arc.withScope(func(scope *requestScope) {
+		scope.err = &err
+		scope.versions = nil
+	}).get(strings.SplitN(path, "?", 2)[0], tee)
+	if err != nil {
+		return versionMark{}, err
+	}
+	mark, ok := parseVersionMark(tee.raw)
+	if !ok {
+		return versionMark{}, fmt.Errorf("redmineclient: unexpected response for %v", path)
+	}
+
+	return mark, nil
+}
+
+// checkedPut обновление с проверкой, что сущность не изменилась на сервере
+func (arc *ApiRedmineClient) checkedPut(call *Call, next CallHandler) {
+	expected := versionMark{updatedOn: arc.scope.expectUpdatedOn, version: arc.scope.expectVersion}
+	if arc.scope.versions != nil {
+		if mark, ok := arc.scope.versions.get(call.Path); ok {
+			if expected.updatedOn.IsZero() {
+				expected.updatedOn = mark.updatedOn
+			}
+			if expected.version == 0 {
+				expected.version = mark.version
+			}
+		}
+	}
+
+	if isWikiPath(call.Path) {
+		// версию страницы проверяет redmine, updated_on только заданный через IfUpdatedOn
+		if arc.scope.expectUpdatedOn.IsZero() || arc.checkUpdatedOn(call, arc.scope.expectUpdatedOn) {
+			arc.checkedWikiPut(call, expected.version, next)
+		}
+		return
+	}
+	if expected.updatedOn.IsZero() {
+		next(call)
+		return
+	}
+	if !arc.checkUpdatedOn(call, expected.updatedOn) {
+		return
+	}
+
+	stats := callStats(call)
+	next(call)
+	if call.Err == nil && arc.scope.versions != nil {
+		arc.refreshVersionMark(call.Path, func(mark versionMark) bool {
+			// updated_on позже ответа на запись означает чужое изменение после неё
+			return stats.date.IsZero() || !mark.updatedOn.Truncate(time.Second).After(stats.date)
+		})
+	}
+}
+
+// checkUpdatedOn проверка, что updated_on сущности на сервере равен expected, при отличии
+// или отсутствии updated_on у сущности ошибка сохраняется в вызове
+func (arc *ApiRedmineClient) checkUpdatedOn(call *Call, expected time.Time) bool {
+	actual, err := arc.fetchVersionMark(call.Path)
+	if err != nil {
+		call.Err = err
+		return false
+	}
+	if actual.updatedOn.IsZero() {
+		arc.fail(call, fmt.Errorf("redmineclient: %v %v: IfUpdatedOn: resource has no updated_on", call.Method, call.Path))
+		return false
+	}
+	if !actual.updatedOn.Truncate(time.Second).Equal(expected.Truncate(time.Second)) {
+		arc.fail(call, &ConflictError{
+			Method:            call.Method,
+			Path:              call.Path,
+			ExpectedUpdatedOn: expected,
+			ActualUpdatedOn:   actual.updatedOn,
+		})
+		return false
+	}
+
+	return true
+}
+
+// refreshVersionMark запоминание updated_on и версии сущности после собственной записи.
+// Redmine отвечает на обновление без тела, поэтому сущность читается заново; если ours
+// не признаёт прочитанную версию результатом этой записи, прежняя отметка сохраняется
+// и следующая запись завершится конфликтом. Изменение в ту же секунду, что и запись, не отличить
+func (arc *ApiRedmineClient) refreshVersionMark(path string, ours func(mark versionMark) bool) {
+	mark, err := arc.fetchVersionMark(path)
+	if err != nil || !ours(mark) {
+		return
+	}
+	arc.scope.versions.set(path, mark)
+}
+
+// checkedWikiPut обновление страницы wiki с версией в теле запроса, 409 от redmine означает конфликт
+func (arc *ApiRedmineClient) checkedWikiPut(call *Call, version int, next CallHandler) {
+	if page, ok := call.Body.(*RdWikiPage); ok && version != 0 && page.Version == 0 {
+		versioned := *page
+		versioned.Version = version
+		call.Body = &versioned
+	}
+	if page, ok := call.Body.(*RdWikiPage); ok {
+		version = page.Version
+	}
+	next(call)
+
+	apiErr := &APIError{}
+	if version != 0 && errors.As(call.Err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		conflict := &ConflictError{Method: call.Method, Path: call.Path, ExpectedVersion: version, Err: call.Err}
+		if actual, err := arc.fetchVersionMark(call.Path); err == nil {
+			conflict.ActualVersion = actual.version
+		}
+		call.Err = nil
+		arc.fail(call, conflict)
+		return
+	}
+	if call.Err == nil && arc.scope.versions != nil {
+		arc.refreshVersionMark(call.Path, func(mark versionMark) bool {
+			// запись версии version создаёт версию version+1
+			return version == 0 || mark.version == version+1
+		})
+	}
+}
+
+// fail ошибка вызова и обработчик ошибок клиента
+func (arc *ApiRedmineClient) fail(call *Call, err error) {
+	call.Err = err
+	if arc.transport.errorHandler != nil {
+		arc.transport.errorHandler(err)
+	}
+}
+
+func (scope *requestScope) checksVersions() bool {
+	return !scope.expectUpdatedOn.IsZero() || scope.expectVersion != 0 || scope.versions != nil
+}
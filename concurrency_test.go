@@ -0,0 +1,137 @@
+package redmineclient_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+// newClockClient клиент фейкового redmine с управляемыми часами, clock сдвигает их на минуту
+func newClockClient(t *testing.T) (*redminetest.Server, *redmineclient.ApiRedmineClient, *redmineclient.RdProject, func()) {
+	t.Helper()
+	srv, client, project := newClient(t)
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	srv.SetNow(func() time.Time { return now })
+
+	return srv, client, project, func() { now = now.Add(time.Minute) }
+}
+
+func TestIfUpdatedOnConflict(t *testing.T) {
+	srv, client, project, tick := newClockClient(t)
+	created := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "original"})
+	read := client.GetIssue(created.ID)
+
+	tick()
+	client.UpdateIssue(&redmineclient.RdIssue{ID: created.ID, Subject: "changed by someone"})
+
+	var err error
+	stale := srv.Client().With(redmineclient.IfUpdatedOn(read.UpdatedOn), redmineclient.CaptureError(&err))
+	stale.UpdateIssue(&redmineclient.RdIssue{ID: created.ID, Subject: "stale write"})
+	var conflict *redmineclient.ConflictError
+	if !errors.As(err, &conflict) || !conflict.ExpectedUpdatedOn.Equal(read.UpdatedOn) || !conflict.ActualUpdatedOn.After(read.UpdatedOn) {
+		t.Fatalf("error = %v, want *ConflictError", err)
+	}
+	if subject := client.GetIssue(created.ID).Subject; subject != "changed by someone" {
+		t.Errorf("subject %q, stale write must not be applied", subject)
+	}
+
+	fresh := client.GetIssue(created.ID)
+	client.With(redmineclient.IfUpdatedOn(fresh.UpdatedOn)).UpdateIssue(&redmineclient.RdIssue{ID: created.ID, Subject: "fresh write"})
+	if subject := client.GetIssue(created.ID).Subject; subject != "fresh write" {
+		t.Errorf("subject %q, want fresh write", subject)
+	}
+}
+
+func TestIfUpdatedOnWithoutUpdatedOn(t *testing.T) {
+	srv, client, project, _ := newClockClient(t)
+	userID, _ := srv.AddUser("jsmith", false)
+	membership := client.CreateMembership(&redmineclient.RdMembership{Project: project.ID, User: userID, Roles: []int{redminetest.RoleDeveloper}})
+
+	var err error
+	srv.Client().With(redmineclient.IfUpdatedOn(time.Now()), redmineclient.CaptureError(&err)).
+		UpdateMembership(&redmineclient.RdMembership{ID: membership.ID, Roles: []int{redminetest.RoleManager}})
+	var conflict *redmineclient.ConflictError
+	if err == nil || errors.As(err, &conflict) || !strings.Contains(err.Error(), "has no updated_on") {
+		t.Fatalf("error = %v, want unsupported IfUpdatedOn error", err)
+	}
+	if roles := client.GetMembership(membership.ID).Roles; len(roles) != 1 || roles[0] != redminetest.RoleDeveloper {
+		t.Errorf("roles %v, update must not be applied", roles)
+	}
+
+	// без IfUpdatedOn участие обновляется
+	client.UpdateMembership(&redmineclient.RdMembership{ID: membership.ID, Roles: []int{redminetest.RoleManager}})
+	if roles := client.GetMembership(membership.ID).Roles; len(roles) != 1 || roles[0] != redminetest.RoleManager {
+		t.Errorf("roles %v, want manager", roles)
+	}
+}
+
+func TestIfUpdatedOnWikiPage(t *testing.T) {
+	srv, client, _, tick := newClockClient(t)
+	client.CreateWikiPage(&redmineclient.RdWikiPage{Text: "h1. Start"}, "/projects/demo/wiki/Start")
+	read := client.GetWikiPage("/projects/demo/wiki/Start.json")
+
+	tick()
+	client.UpdateWikiPage(&redmineclient.RdWikiPage{Text: "h1. Changed"}, "/projects/demo/wiki/Start")
+
+	var err error
+	srv.Client().With(redmineclient.IfUpdatedOn(read.UpdatedOn), redmineclient.CaptureError(&err)).
+		UpdateWikiPage(&redmineclient.RdWikiPage{Text: "h1. Stale"}, "/projects/demo/wiki/Start")
+	var conflict *redmineclient.ConflictError
+	if !errors.As(err, &conflict) || conflict.ExpectedUpdatedOn.IsZero() {
+		t.Fatalf("error = %v, want updated_on *ConflictError", err)
+	}
+	if page := client.GetWikiPage("/projects/demo/wiki/Start.json"); page.Text != "h1. Changed" {
+		t.Errorf("text %q, stale write must not be applied", page.Text)
+	}
+}
+
+func TestIfVersionConflict(t *testing.T) {
+	srv, client, _, _ := newClockClient(t)
+	client.CreateWikiPage(&redmineclient.RdWikiPage{Text: "v1"}, "/projects/demo/wiki/Start")
+	client.UpdateWikiPage(&redmineclient.RdWikiPage{Text: "v2"}, "/projects/demo/wiki/Start")
+
+	var err error
+	srv.Client().With(redmineclient.IfVersion(1), redmineclient.CaptureError(&err)).
+		UpdateWikiPage(&redmineclient.RdWikiPage{Text: "from v1"}, "/projects/demo/wiki/Start")
+	var conflict *redmineclient.ConflictError
+	if !errors.As(err, &conflict) || conflict.ExpectedVersion != 1 || conflict.ActualVersion != 2 {
+		t.Fatalf("error = %v, want version *ConflictError 1 -> 2", err)
+	}
+	if page := client.GetWikiPage("/projects/demo/wiki/Start.json"); page.Text != "v2" || page.Version != 2 {
+		t.Errorf("page %q version %d, want v2", page.Text, page.Version)
+	}
+}
+
+func TestTrackVersions(t *testing.T) {
+	srv, client, project, tick := newClockClient(t)
+	created := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "tracked"})
+
+	var err error
+	bot := srv.Client().With(redmineclient.TrackVersions(), redmineclient.CaptureError(&err))
+	bot.GetIssue(created.ID)
+
+	// собственные последовательные записи не конфликтуют
+	tick()
+	bot.UpdateIssue(&redmineclient.RdIssue{ID: created.ID, Subject: "bot 1"})
+	tick()
+	bot.UpdateIssue(&redmineclient.RdIssue{ID: created.ID, Subject: "bot 2"})
+	if err != nil {
+		t.Fatalf("own writes: %v", err)
+	}
+
+	tick()
+	client.UpdateIssue(&redmineclient.RdIssue{ID: created.ID, Subject: "someone"})
+	tick()
+	bot.UpdateIssue(&redmineclient.RdIssue{ID: created.ID, Subject: "bot 3"})
+	var conflict *redmineclient.ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("error = %v, want *ConflictError", err)
+	}
+	if subject := client.GetIssue(created.ID).Subject; subject != "someone" {
+		t.Errorf("subject %q, want someone", subject)
+	}
+}
@@ -0,0 +1,120 @@
+package redmineclient
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DedupeOptions параметры идемпотентного создания задач
+type DedupeOptions struct {
+	// CustomFieldID настраиваемое поле задачи для ключа, поле должно быть доступно как фильтр.
+	// Если 0, ключ добавляется в начало темы задачи маркером SubjectMarker
+	CustomFieldID int
+	// SubjectMarker формат маркера ключа в теме задачи, по умолчанию "[%s]"
+	SubjectMarker string
+	// Note комментарий, добавляемый к уже существующей задаче
+	Note string
+}
+
+func (options *DedupeOptions) marker(key string) string {
+	format := options.SubjectMarker
+	if format == "" {
+		format = "[%s]"
+	}
+
+	return fmt.Sprintf(format, key)
+}
+
+// CreateIssueIdempotent создать задачу с ключом key, если открытой задачи с таким ключом ещё нет.
+// Возвращает найденную или созданную задачу и признак создания; при ошибке поиска задача
+// не создаётся. Поиск и создание не атомарны: одновременные вызовы с одним ключом могут создать дубликаты
+func (arc *ApiRedmineClient) CreateIssueIdempotent(issue *RdIssue, key string, options *DedupeOptions) (*RdIssue, bool, error) {
+	if options == nil {
+		options = &DedupeOptions{}
+	}
+
+	existing, err := arc.findIssueByKey(issue.Project, key, options)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		if options.Note != "" {
+			if _, err := arc.updateIssue(&RdIssue{ID: existing.ID, Notes: options.Note}); err != nil {
+				return existing.ToIssue(), false, err
+			}
+		}
+		return existing.ToIssue(), false, nil
+	}
+
+	keyed := options.withKey(issue, key)
+	if err := issues.create(arc, keyed); err != nil {
+		return nil, false, err
+	}
+
+	return keyed, true, nil
+}
+
+// findIssueByKey открытая задача с ключом key, nil если её нет; project 0 поиск во всех проектах
+func (arc *ApiRedmineClient) findIssueByKey(project int, key string, options *DedupeOptions) (*RdIssueData, error) {
+	filter := []string{"status_id=open", "sort=id"}
+	if project != 0 {
+		filter = append(filter, "project_id="+strconv.Itoa(project))
+	}
+	if options.CustomFieldID != 0 {
+		filter = append(filter, fmt.Sprintf("cf_%d=%s", options.CustomFieldID, url.QueryEscape(key)))
+	} else {
+		filter = append(filter, "subject="+url.QueryEscape("~"+options.marker(key)))
+	}
+
+	found, err := issueData.all(arc, filter)
+	if err != nil {
+		return nil, fmt.Errorf("redmineclient: find issue %q: %w", key, err)
+	}
+	for i := range found {
+		if options.matches(&found[i], key) {
+			return &found[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// withKey копия задачи с ключом key в настраиваемом поле или в теме
+func (options *DedupeOptions) withKey(issue *RdIssue, key string) *RdIssue {
+	keyed := *issue
+	if options.CustomFieldID != 0 {
+		keyed.CustomFields = options.withKeyField(issue.CustomFields, key)
+	} else {
+		keyed.Subject = strings.TrimSpace(options.marker(key) + " " + issue.Subject)
+	}
+
+	return &keyed
+}
+
+// withKeyField копия значений настраиваемых полей, где значение поля ключа заменено key
+func (options *DedupeOptions) withKeyField(customFields []RdCustomFieldValue, key string) []RdCustomFieldValue {
+	keyed := make([]RdCustomFieldValue, 0, len(customFields)+1)
+	for _, customField := range customFields {
+		if customField.ID != options.CustomFieldID {
+			keyed = append(keyed, customField)
+		}
+	}
+
+	return append(keyed, RdCustomFieldValue{ID: options.CustomFieldID, Value: key})
+}
+
+// matches задача содержит ключ, фильтр redmine по теме ищет вхождение без учёта регистра
+func (options *DedupeOptions) matches(issueData *RdIssueData, key string) bool {
+	if options.CustomFieldID == 0 {
+		return strings.Contains(issueData.Subject, options.marker(key))
+	}
+	for _, customField := range issueData.CustomFields {
+		if customField.ID == options.CustomFieldID && customField.Value == key {
+			return true
+		}
+	}
+
+	return false
+}
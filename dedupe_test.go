@@ -0,0 +1,46 @@
+package redmineclient_test
+
+import (
+	"strings"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+func TestCreateIssueIdempotent(t *testing.T) {
+	srv, client, project := newClient(t)
+	fieldID := srv.AddCustomField(redmineclient.RdCustomField{Name: "Key", CustomizedType: "issue", FieldFormat: "string", IsFilter: true})
+
+	tests := []struct {
+		name    string
+		options *redmineclient.DedupeOptions
+	}{
+		{"subject marker", nil},
+		{"custom field", &redmineclient.DedupeOptions{CustomFieldID: fieldID, Note: "seen again"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key := strings.ReplaceAll(test.name, " ", "-")
+			created, isNew, err := client.CreateIssueIdempotent(&redmineclient.RdIssue{Project: project.ID, Subject: "alert"}, key, test.options)
+			if err != nil || !isNew || created.ID == 0 {
+				t.Fatalf("first call = #%d %v %v", created.ID, isNew, err)
+			}
+
+			found, isNew, err := client.CreateIssueIdempotent(&redmineclient.RdIssue{Project: project.ID, Subject: "alert"}, key, test.options)
+			if err != nil || isNew || found.ID != created.ID {
+				t.Fatalf("second call = #%d %v %v, want #%d", found.ID, isNew, err, created.ID)
+			}
+
+			if test.options != nil && test.options.Note != "" {
+				tree, err := client.GetIssueTree(created.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				issue := tree.Issue
+				if len(issue.Journals) == 0 || issue.Journals[len(issue.Journals)-1].Notes != test.options.Note {
+					t.Errorf("journals = %+v, want note %q", issue.Journals, test.options.Note)
+				}
+			}
+		})
+	}
+}
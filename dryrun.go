@@ -0,0 +1,108 @@
+package redmineclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// PlannedCall изменяющий запрос, который не был отправлен в режиме dry-run
+type PlannedCall struct {
+	Method string `json:"method"`
+	// Path путь запроса относительно адреса redmine, включая параметры
+	Path string `json:"path"`
+	// SwitchUser пользователь, от имени которого выполнялся бы запрос
+	SwitchUser string `json:"switch_user,omitempty"`
+	// Body тело запроса в том виде, в котором оно было бы отправлено
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+func (call PlannedCall) String() string {
+	line := call.Method + " " + call.Path
+	if call.SwitchUser != "" {
+		line += " as " + call.SwitchUser
+	}
+	if len(call.Body) > 0 {
+		line += " " + string(call.Body)
+	}
+
+	return line
+}
+
+// Plan план изменяющих запросов, накопленный в режиме dry-run
+type Plan struct {
+	mu    sync.Mutex
+	calls []PlannedCall
+}
+
+// Calls запланированные запросы в порядке вызова методов
+func (plan *Plan) Calls() []PlannedCall {
+	plan.mu.Lock()
+	defer plan.mu.Unlock()
+
+	return append([]PlannedCall{}, plan.calls...)
+}
+
+// Reset очистка плана
+func (plan *Plan) Reset() {
+	plan.mu.Lock()
+	defer plan.mu.Unlock()
+
+	plan.calls = nil
+}
+
+// String план построчно: метод, путь, пользователь и тело запроса
+func (plan *Plan) String() string {
+	lines := []string{}
+	for _, call := range plan.Calls() {
+		lines = append(lines, call.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (plan *Plan) add(call PlannedCall) {
+	plan.mu.Lock()
+	defer plan.mu.Unlock()
+
+	plan.calls = append(plan.calls, call)
+}
+
+// WithDryRun режим dry-run: методы Create*, Update* и Delete* не отправляют запросы,
+// а записывают их в plan, GET запросы выполняются как обычно.
+// Ответа сервера нет, поэтому методы создания и изменения возвращают переданную сущность
+// без изменений (у новой сущности ID остаётся 0), а UpdateIssue и UpdateIssueCategory пустые данные
+func WithDryRun(plan *Plan) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.transport.dryRun = plan
+		return nil
+	}
+}
+
+// planCall запись изменяющего вызова в план вместо отправки
+func (arc *ApiRedmineClient) planCall(call *Call) {
+	planned := PlannedCall{Method: call.Method, Path: arc.callPath(call), SwitchUser: arc.scope.switchUser}
+	if call.Method != http.MethodDelete && call.Body != nil {
+		body, err := json.Marshal(call.Body)
+		if err != nil {
+			call.Err = err
+			return
+		}
+		planned.Body = body
+	}
+
+	arc.transport.dryRun.add(planned)
+}
+
+// callPath путь вызова с параметрами копии клиента, как он был бы отправлен
+func (arc *ApiRedmineClient) callPath(call *Call) string {
+	requestURL, err := url.Parse(call.Path)
+	if err != nil {
+		return call.Path
+	}
+	arc.scope.applyQuery(requestURL)
+
+	return requestURL.String()
+}
@@ -0,0 +1,86 @@
+package redmineclient_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+func TestDryRunPlan(t *testing.T) {
+	srv, client, project := newClient(t)
+	srv.AddUser("jsmith", false)
+	existing := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "existing"})
+
+	plan := &redmineclient.Plan{}
+	dryRun := srv.Client(redmineclient.WithDryRun(plan))
+
+	// GET выполняется, изменения только планируются
+	if issue := dryRun.GetIssue(existing.ID); issue.Subject != "existing" {
+		t.Fatalf("GetIssue in dry-run = %q, want existing", issue.Subject)
+	}
+	created := dryRun.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "planned"})
+	if created.ID != 0 || created.Subject != "planned" {
+		t.Errorf("CreateIssue in dry-run = %+v, want input without ID", created)
+	}
+	dryRun.As("jsmith").UpdateIssue(&redmineclient.RdIssue{ID: existing.ID, Subject: "renamed"})
+	dryRun.With(redmineclient.Query("force", "1")).DeleteIssue(existing.ID)
+
+	want := []redmineclient.PlannedCall{
+		{Method: "POST", Path: "/issues.json", Body: []byte(`{"issue":{"project_id":1,"subject":"planned"}}`)},
+		{Method: "PUT", Path: "/issues/1.json", SwitchUser: "jsmith", Body: []byte(`{"issue":{"id":1,"subject":"renamed"}}`)},
+		{Method: "DELETE", Path: "/issues/1.json?force=1"},
+	}
+	calls := plan.Calls()
+	if len(calls) != len(want) {
+		t.Fatalf("plan:\n%v\nwant %d calls", plan, len(want))
+	}
+	for i := range want {
+		if calls[i].String() != want[i].String() {
+			t.Errorf("call %d = %v, want %v", i, calls[i], want[i])
+		}
+	}
+	wantString := `POST /issues.json {"issue":{"project_id":1,"subject":"planned"}}` + "\n" +
+		`PUT /issues/1.json as jsmith {"issue":{"id":1,"subject":"renamed"}}` + "\n" +
+		`DELETE /issues/1.json?force=1`
+	if plan.String() != wantString {
+		t.Errorf("String() =\n%v\nwant\n%v", plan, wantString)
+	}
+
+	// на сервере ничего не изменилось
+	issues := client.GetListIssue("project_id=" + project.Identifier)
+	if len(issues) != 1 || issues[0].Subject != "existing" {
+		t.Errorf("issues after dry-run %+v, want only unchanged existing", issues)
+	}
+
+	plan.Reset()
+	if len(plan.Calls()) != 0 || plan.String() != "" {
+		t.Errorf("plan after Reset:\n%v", plan)
+	}
+}
+
+func TestDryRunDoesNotSendWrites(t *testing.T) {
+	var methods []string
+	srv := redminetest.NewServer()
+	defer srv.Close()
+	plan := &redmineclient.Plan{}
+	client := srv.Client(redmineclient.WithDryRun(plan), redmineclient.WithSendMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			methods = append(methods, req.Method)
+			return next.RoundTrip(req)
+		})
+	}))
+
+	client.CreateProject(&redmineclient.RdProject{Name: "Demo", Identifier: "demo"})
+	client.DeleteProject(1)
+	client.GetProjectList()
+
+	if strings.Join(methods, ",") != "GET" {
+		t.Errorf("sent %q, want only GET", methods)
+	}
+	if len(plan.Calls()) != 2 {
+		t.Errorf("plan:\n%v\nwant 2 calls", plan)
+	}
+}
@@ -25,6 +25,7 @@ type RdUser struct {
 	Firstname    string               `json:"firstname,omitempty"`
 	Lastname     string               `json:"lastname,omitempty"`
 	Mail         string               `json:"mail,omitempty"`
+	Password     string               `json:"password,omitempty"`
 	CreatedOn    time.Time            `json:"-"`
 	LastLoginOn  time.Time            `json:"-"`
 	APIKey       string               `json:"-"`
@@ -35,14 +36,14 @@ type RdUser struct {
 }
 
 func (user *RdUser) UnmarshalJSON(data []byte) error {
-	userData := map[string]*RdUserData{"user": &RdUserData{}}
-	err := json.Unmarshal(data, &userData)
-	*user = *userData["user"].ToUser()
+	userValue, err := unwrapEntity[RdUserData](data, "user")
+	*user = *userValue.ToUser()
 	return err
 }
 
 func (user *RdUser) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdUser{"user": user})
+	type rdUser RdUser
+	return json.Marshal(map[string]*rdUser{"user": (*rdUser)(user)})
 }
 
 type RdUserData struct {
@@ -56,11 +57,16 @@ type RdUserData struct {
 	APIKey       string               `json:"api_key,omitempty"`
 	Status       int                  `json:"status,omitempty"`
 	CustomFields []RdCustomFieldValue `json:"custom_fields,omitempty"`
-	Memberships  []RdMembership       `json:"memberships,omitempty"`
+	Memberships  []RdMembershipData   `json:"memberships,omitempty"`
 	Groups       []RdGroup            `json:"groups,omitempty"`
 }
 
 func (userData *RdUserData) ToUser() *RdUser {
+	memberships := []RdMembership{}
+	for _, membership := range userData.Memberships {
+		memberships = append(memberships, *membership.ToMemberShip())
+	}
+
 	return &RdUser{
 		ID:           userData.ID,
 		Login:        userData.Login,
@@ -72,7 +78,7 @@ func (userData *RdUserData) ToUser() *RdUser {
 		APIKey:       userData.APIKey,
 		Status:       userData.Status,
 		CustomFields: userData.CustomFields,
-		Memberships:  userData.Memberships,
+		Memberships:  memberships,
 		Groups:       userData.Groups,
 	}
 }
@@ -83,7 +89,7 @@ http://www.redmine.org/projects/redmine/wiki/Rest_Groups
 */
 type RdGroup struct {
 	ID   int    `json:"id"`
-	Name string `json:"string"`
+	Name string `json:"name"`
 }
 
 // RdProjectList list project redmine
@@ -98,7 +104,7 @@ http://www.redmine.org/projects/redmine/wiki/Rest_Projects
 */
 type RdProject struct {
 	ID          int       `json:"id,omitempty"`
-	Name        string    `json:"string,omitempty"`
+	Name        string    `json:"name,omitempty"`
 	Identifier  string    `json:"identifier,omitempty"`
 	Homepage    string    `json:"homepage,omitempty"`
 	Description string    `json:"description,omitempty"`
@@ -108,19 +114,19 @@ type RdProject struct {
 }
 
 func (project *RdProject) UnmarshalJSON(data []byte) error {
-	projectData := map[string]*RdProjectData{"project": &RdProjectData{}}
-	err := json.Unmarshal(data, &projectData)
-	*project = *projectData["project"].ToProject()
+	projectValue, err := unwrapEntity[RdProjectData](data, "project")
+	*project = *projectValue.ToProject()
 	return err
 }
 
 func (project *RdProject) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdProject{"project": project})
+	type rdProject RdProject
+	return json.Marshal(map[string]*rdProject{"project": (*rdProject)(project)})
 }
 
 type RdProjectData struct {
 	ID          int       `json:"id"`
-	Name        string    `json:"string"`
+	Name        string    `json:"name"`
 	Identifier  string    `json:"identifier"`
 	Homepage    string    `json:"homepage"`
 	Description string    `json:"description"`
@@ -142,6 +148,29 @@ func (projectData *RdProjectData) ToProject() *RdProject {
 	}
 }
 
+// DateFormat формат дат redmine (start_date, due_date)
+const DateFormat = "2006-01-02"
+
+func parseDate(value string) time.Time {
+	date, err := time.Parse(DateFormat, value)
+	if err != nil {
+		date, _ = time.Parse(time.RFC3339, value)
+	}
+
+	return date
+}
+
+// unwrapEntity сущность из обёртки вида {"issue": {...}}, при null или без key пустая сущность
+func unwrapEntity[T any](data []byte, key string) (*T, error) {
+	wrapped := map[string]*T{key: new(T)}
+	err := json.Unmarshal(data, &wrapped)
+	if wrapped[key] == nil {
+		return new(T), err
+	}
+
+	return wrapped[key], err
+}
+
 type BaseList struct {
 	TotalCount int `json:"total_count"`
 	Offset     int `json:"offset"`
@@ -157,43 +186,75 @@ type RdIssueList struct {
 	*BaseList
 }
 
+// Bool, Int и Float указатели на значения необязательных полей запросов, например
+// RdIssue.IsPrivate: nil поле не отправляется, указатель на false или 0 отправляется как есть
+func Bool(value bool) *bool {
+	return &value
+}
+
+func Int(value int) *int {
+	return &value
+}
+
+func Float(value float64) *float64 {
+	return &value
+}
+
 /*
 RdIssue redmine issue
 http://www.redmine.org/projects/redmine/wiki/Rest_Issues
 */
 type RdIssue struct {
-	ID           int                  `json:"id,omitempty"`
-	Project      int                  `json:"project_id,omitempty"`
-	Tracker      int                  `json:"tracker_id,omitempty"`
-	Status       int                  `json:"status_id,omitempty"`
-	Author       int                  `json:"author_id,omitempty"`
-	AssignedTo   int                  `json:"assigned_to_id,omitempty"`
-	FixedVersion int                  `json:"fixed_version,omitempty"`
-	Parent       int                  `json:"parent_issue_id,omitempty"`
-	Notes        string               `json:"notes,omitempty"`
-	IsPrivate    int                  `json:"is_private,omitempty"`
-	Subject      string               `json:"subject,omitempty"`
-	Description  string               `json:"description,omitempty"`
-	DueDate      time.Time            `json:"-"`
-	StartDate    time.Time            `json:"-"`
-	DoneRatio    int                  `json:"done_ratio,omitempty"`
-	SpentHours   float64              `json:"spent_hours,omitempty"`
-	Priority     int                  `json:"priority_id,omitempty"`
-	CustomFields []RdCustomFieldValue `json:"custom_fields,omitempty"`
-	CreatedOn    time.Time            `json:"-"`
-	UpdatedOn    time.Time            `json:"-"`
-	ClosedOn     time.Time            `json:"-"`
+	ID                  int                  `json:"id,omitempty"`
+	Project             int                  `json:"project_id,omitempty"`
+	Tracker             int                  `json:"tracker_id,omitempty"`
+	Status              int                  `json:"status_id,omitempty"`
+	Priority            int                  `json:"priority_id,omitempty"`
+	Author              int                  `json:"author_id,omitempty"`
+	AssignedTo          int                  `json:"assigned_to_id,omitempty"`
+	Category            int                  `json:"category_id,omitempty"`
+	FixedVersion        int                  `json:"fixed_version_id,omitempty"`
+	Parent              int                  `json:"parent_issue_id,omitempty"`
+	Notes               string               `json:"notes,omitempty"`
+	PrivateNotes        bool                 `json:"private_notes,omitempty"`
+	IsPrivate           *bool                `json:"is_private,omitempty"`
+	Subject             string               `json:"subject,omitempty"`
+	Description         string               `json:"description,omitempty"`
+	DueDate             time.Time            `json:"-"`
+	StartDate           time.Time            `json:"-"`
+	DoneRatio           *int                 `json:"done_ratio,omitempty"`
+	EstimatedHours      *float64             `json:"estimated_hours,omitempty"`
+	TotalEstimatedHours float64              `json:"-"`
+	SpentHours          float64              `json:"-"`
+	TotalSpentHours     float64              `json:"-"`
+	WatcherUserIDs      []int                `json:"watcher_user_ids,omitempty"`
+	CustomFields        []RdCustomFieldValue `json:"custom_fields,omitempty"`
+	CreatedOn           time.Time            `json:"-"`
+	UpdatedOn           time.Time            `json:"-"`
+	ClosedOn            time.Time            `json:"-"`
 }
 
 func (issue *RdIssue) UnmarshalJSON(data []byte) error {
-	issueData := map[string]*RdIssueData{"issue": &RdIssueData{}}
-	err := json.Unmarshal(data, &issueData)
-	*issue = *issueData["issue"].ToIssue()
+	issueValue, err := unwrapEntity[RdIssueData](data, "issue")
+	*issue = *issueValue.ToIssue()
 	return err
 }
 
 func (issue *RdIssue) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdIssue{"issue": issue})
+	type rdIssue RdIssue
+	issueData := struct {
+		*rdIssue
+		StartDate string `json:"start_date,omitempty"`
+		DueDate   string `json:"due_date,omitempty"`
+	}{rdIssue: (*rdIssue)(issue)}
+	if !issue.StartDate.IsZero() {
+		issueData.StartDate = issue.StartDate.Format(DateFormat)
+	}
+	if !issue.DueDate.IsZero() {
+		issueData.DueDate = issue.DueDate.Format(DateFormat)
+	}
+
+	return json.Marshal(map[string]interface{}{"issue": issueData})
 }
 
 func (issue *RdIssue) GetMessage(baseURL string) string {
@@ -202,58 +263,111 @@ func (issue *RdIssue) GetMessage(baseURL string) string {
 }
 
 type RdIssueData struct {
-	ID           int                  `json:"id"`
-	Project      RdLinkObject         `json:"project"`
-	Tracker      RdLinkObject         `json:"tracker"`
-	Status       RdLinkObject         `json:"status"`
-	Author       RdLinkObject         `json:"author"`
-	AssignedTo   RdLinkObject         `json:"assigned_to"`
-	FixedVersion RdLinkObject         `json:"fixed_version"`
-	Parent       RdLinkObject         `json:"parent"`
-	Subject      string               `json:"subject"`
-	Description  string               `json:"description"`
-	StartDate    string               `json:"start_date"`
-	DueDate      string               `json:"due_date"`
-	DoneRatio    int                  `json:"done_ratio"`
-	SpentHours   float64              `json:"spent_hours"`
-	CustomFields []RdCustomFieldValue `json:"custom_fields"`
-	CreatedOn    time.Time            `json:"created_on"`
-	UpdatedOn    time.Time            `json:"updated_on"`
-	Journals     []RdIssueJournal     `json:"journals"`
-	Attachments  []RdAttachment       `json:"attachments"`
-	ClosedOn     time.Time            `json:"closed_on"`
+	ID           int          `json:"id"`
+	Project      RdLinkObject `json:"project"`
+	Tracker      RdLinkObject `json:"tracker"`
+	Status       RdLinkObject `json:"status"`
+	Priority     RdLinkObject `json:"priority"`
+	Author       RdLinkObject `json:"author"`
+	AssignedTo   RdLinkObject `json:"assigned_to"`
+	Category     RdLinkObject `json:"category"`
+	FixedVersion RdLinkObject `json:"fixed_version"`
+	Parent       RdLinkObject `json:"parent"`
+	Subject      string       `json:"subject"`
+	Description  string       `json:"description"`
+	StartDate    string       `json:"start_date"`
+	DueDate      string       `json:"due_date"`
+	// DoneRatio, IsPrivate и EstimatedHours nil, если поле не пришло или равно null,
+	// ToIssue передаёт nil дальше, чтобы UpdateIssue не затирал пустую оценку нулём
+	DoneRatio           *int                  `json:"done_ratio"`
+	IsPrivate           *bool                 `json:"is_private"`
+	EstimatedHours      *float64              `json:"estimated_hours"`
+	TotalEstimatedHours float64               `json:"total_estimated_hours"`
+	SpentHours          float64               `json:"spent_hours"`
+	TotalSpentHours     float64               `json:"total_spent_hours"`
+	CustomFields        []RdCustomFieldValue  `json:"custom_fields"`
+	CreatedOn           time.Time             `json:"created_on"`
+	UpdatedOn           time.Time             `json:"updated_on"`
+	ClosedOn            time.Time             `json:"closed_on"`
+	Journals            []RdIssueJournal      `json:"journals"`
+	Attachments         []RdAttachmentData    `json:"attachments"`
+	Relations           []RdIssueRelationData `json:"relations"`
+	Children            []RdIssueChild        `json:"children"`
+	Changesets          []RdChangeset         `json:"changesets"`
+	Watchers            []RdLinkObject        `json:"watchers"`
+	AllowedStatuses     []RdIssueStatus       `json:"allowed_statuses"`
+}
+
+// GetStartDate дата начала задачи
+func (issueData *RdIssueData) GetStartDate() time.Time {
+	return parseDate(issueData.StartDate)
+}
+
+// GetDueDate срок завершения задачи
+func (issueData *RdIssueData) GetDueDate() time.Time {
+	return parseDate(issueData.DueDate)
 }
 
 func (issueData *RdIssueData) ToIssue() *RdIssue {
-	startDate, _ := time.Parse(time.RFC3339, issueData.StartDate)
-	dueDate, _ := time.Parse(time.RFC3339, issueData.DueDate)
+	startDate := issueData.GetStartDate()
+	dueDate := issueData.GetDueDate()
+	watcherUserIDs := []int{}
+	for _, watcher := range issueData.Watchers {
+		watcherUserIDs = append(watcherUserIDs, watcher.ID)
+	}
+
 	return &RdIssue{
-		ID:           issueData.ID,
-		Project:      issueData.Project.ID,
-		Tracker:      issueData.Tracker.ID,
-		Status:       issueData.Status.ID,
-		Author:       issueData.Author.ID,
-		AssignedTo:   issueData.AssignedTo.ID,
-		FixedVersion: issueData.FixedVersion.ID,
-		Parent:       issueData.Parent.ID,
-		Subject:      issueData.Subject,
-		Description:  issueData.Description,
-		StartDate:    startDate,
-		DueDate:      dueDate,
-		DoneRatio:    issueData.DoneRatio,
-		SpentHours:   issueData.SpentHours,
-		CustomFields: issueData.CustomFields,
-		CreatedOn:    issueData.CreatedOn,
-		UpdatedOn:    issueData.UpdatedOn,
+		ID:                  issueData.ID,
+		Project:             issueData.Project.ID,
+		Tracker:             issueData.Tracker.ID,
+		Status:              issueData.Status.ID,
+		Priority:            issueData.Priority.ID,
+		Author:              issueData.Author.ID,
+		AssignedTo:          issueData.AssignedTo.ID,
+		Category:            issueData.Category.ID,
+		FixedVersion:        issueData.FixedVersion.ID,
+		Parent:              issueData.Parent.ID,
+		IsPrivate:           issueData.IsPrivate,
+		Subject:             issueData.Subject,
+		Description:         issueData.Description,
+		StartDate:           startDate,
+		DueDate:             dueDate,
+		DoneRatio:           issueData.DoneRatio,
+		EstimatedHours:      issueData.EstimatedHours,
+		TotalEstimatedHours: issueData.TotalEstimatedHours,
+		SpentHours:          issueData.SpentHours,
+		TotalSpentHours:     issueData.TotalSpentHours,
+		WatcherUserIDs:      watcherUserIDs,
+		CustomFields:        issueData.CustomFields,
+		CreatedOn:           issueData.CreatedOn,
+		UpdatedOn:           issueData.UpdatedOn,
+		ClosedOn:            issueData.ClosedOn,
 	}
 }
 
+// RdIssueChild подзадача (include=children)
+type RdIssueChild struct {
+	ID       int            `json:"id"`
+	Tracker  RdLinkObject   `json:"tracker"`
+	Subject  string         `json:"subject"`
+	Children []RdIssueChild `json:"children"`
+}
+
+// RdChangeset ревизия репозитория, связанная с задачей (include=changesets)
+type RdChangeset struct {
+	Revision    string       `json:"revision"`
+	User        RdLinkObject `json:"user"`
+	Comments    string       `json:"comments"`
+	CommittedOn time.Time    `json:"committed_on"`
+}
+
 type RdIssueJournal struct {
-	ID        int                     `json:"id"`
-	User      RdLinkObject            `json:"user"`
-	Notes     string                  `json:"notes"`
-	CreatedOn time.Time               `json:"created_on"`
-	Details   []RdJournalDetailChange `json:"details"`
+	ID           int                     `json:"id"`
+	User         RdLinkObject            `json:"user"`
+	Notes        string                  `json:"notes"`
+	PrivateNotes bool                    `json:"private_notes"`
+	CreatedOn    time.Time               `json:"created_on"`
+	Details      []RdJournalDetailChange `json:"details"`
 }
 
 type RdJournalDetailChange struct {
@@ -291,14 +405,14 @@ type RdMembership struct {
 }
 
 func (membership *RdMembership) UnmarshalJSON(data []byte) error {
-	membershipData := map[string]*RdMembershipData{"membership": &RdMembershipData{}}
-	err := json.Unmarshal(data, &membershipData)
-	*membership = *membershipData["membership"].ToMemberShip()
+	membershipValue, err := unwrapEntity[RdMembershipData](data, "membership")
+	*membership = *membershipValue.ToMemberShip()
 	return err
 }
 
 func (membership *RdMembership) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdMembership{"membership": membership})
+	type rdMembership RdMembership
+	return json.Marshal(map[string]*rdMembership{"membership": (*rdMembership)(membership)})
 }
 
 type RdMembershipData struct {
@@ -381,20 +495,28 @@ type RdTimeEntrie struct {
 	Activity  int       `json:"activity_id,omitempty"`
 	Hours     float64   `json:"hours,omitempty"`
 	Comments  string    `json:"comments,omitempty"`
-	SpentOn   time.Time `json:"spent_on,omitempty"`
+	SpentOn   time.Time `json:"-"`
 	CreatedOn time.Time `json:"-"`
 	UpdatedOn time.Time `json:"-"`
 }
 
 func (timeEntrie *RdTimeEntrie) UnmarshalJSON(data []byte) error {
-	timeEntrieData := map[string]*RdTimeEntrieData{"time_entry": &RdTimeEntrieData{}}
-	err := json.Unmarshal(data, &timeEntrieData)
-	*timeEntrie = *timeEntrieData["time_entry"].ToTimeEntrie()
+	timeEntrieValue, err := unwrapEntity[RdTimeEntrieData](data, "time_entry")
+	*timeEntrie = *timeEntrieValue.ToTimeEntrie()
 	return err
 }
 
 func (timeEntrie *RdTimeEntrie) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdTimeEntrie{"time_entry": timeEntrie})
+	type rdTimeEntrie RdTimeEntrie
+	timeEntrieData := struct {
+		*rdTimeEntrie
+		SpentOn string `json:"spent_on,omitempty"`
+	}{rdTimeEntrie: (*rdTimeEntrie)(timeEntrie)}
+	if !timeEntrie.SpentOn.IsZero() {
+		timeEntrieData.SpentOn = timeEntrie.SpentOn.Format(DateFormat)
+	}
+
+	return json.Marshal(map[string]interface{}{"time_entry": timeEntrieData})
 }
 
 type RdTimeEntrieData struct {
@@ -405,11 +527,16 @@ type RdTimeEntrieData struct {
 	Activity  RdLinkObject `json:"activity"`
 	Hours     float64      `json:"hours"`
 	Comments  string       `json:"comments"`
-	SpentOn   time.Time    `json:"spent_on"`
+	SpentOn   string       `json:"spent_on"`
 	CreatedOn time.Time    `json:"created_on"`
 	UpdatedOn time.Time    `json:"updated_on"`
 }
 
+// GetSpentOn дата, на которую списано время
+func (timeEntrieData *RdTimeEntrieData) GetSpentOn() time.Time {
+	return parseDate(timeEntrieData.SpentOn)
+}
+
 func (timeEntrieData *RdTimeEntrieData) ToTimeEntrie() *RdTimeEntrie {
 	return &RdTimeEntrie{
 		ID:        timeEntrieData.ID,
@@ -419,7 +546,7 @@ func (timeEntrieData *RdTimeEntrieData) ToTimeEntrie() *RdTimeEntrie {
 		Activity:  timeEntrieData.Activity.ID,
 		Hours:     timeEntrieData.Hours,
 		Comments:  timeEntrieData.Comments,
-		SpentOn:   timeEntrieData.SpentOn,
+		SpentOn:   timeEntrieData.GetSpentOn(),
 		CreatedOn: timeEntrieData.CreatedOn,
 		UpdatedOn: timeEntrieData.UpdatedOn,
 	}
@@ -470,14 +597,14 @@ type RdNews struct {
 }
 
 func (news *RdNews) UnmarshalJSON(data []byte) error {
-	newsData := map[string]*RdNewsData{"news": &RdNewsData{}}
-	err := json.Unmarshal(data, &newsData)
-	*news = *newsData["news"].ToNews()
+	newsValue, err := unwrapEntity[RdNewsData](data, "news")
+	*news = *newsValue.ToNews()
 	return err
 }
 
 func (news *RdNews) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdNews{"news": news})
+	type rdNews RdNews
+	return json.Marshal(map[string]*rdNews{"news": (*rdNews)(news)})
 }
 
 type RdNewsData struct {
@@ -501,30 +628,132 @@ func (newsData *RdNewsData) ToNews() *RdNews {
 }
 
 type RdIssueRelationList struct {
-	IssueRelations []RdIssueRelation `json:"relations"`
+	IssueRelations []RdIssueRelationData `json:"relations"`
 	*BaseList
 }
 
+// RdRelationType тип связи между задачами
+type RdRelationType string
+
+const (
+	RelationRelates    RdRelationType = "relates"
+	RelationDuplicates RdRelationType = "duplicates"
+	RelationDuplicated RdRelationType = "duplicated"
+	RelationBlocks     RdRelationType = "blocks"
+	RelationBlocked    RdRelationType = "blocked"
+	RelationPrecedes   RdRelationType = "precedes"
+	RelationFollows    RdRelationType = "follows"
+	RelationCopiedTo   RdRelationType = "copied_to"
+	RelationCopiedFrom RdRelationType = "copied_from"
+)
+
+var inverseRelationTypes = map[RdRelationType]RdRelationType{
+	RelationRelates:    RelationRelates,
+	RelationDuplicates: RelationDuplicated,
+	RelationDuplicated: RelationDuplicates,
+	RelationBlocks:     RelationBlocked,
+	RelationBlocked:    RelationBlocks,
+	RelationPrecedes:   RelationFollows,
+	RelationFollows:    RelationPrecedes,
+	RelationCopiedTo:   RelationCopiedFrom,
+	RelationCopiedFrom: RelationCopiedTo,
+}
+
+// IsValid тип связи известен redmine
+func (relationType RdRelationType) IsValid() bool {
+	_, ok := inverseRelationTypes[relationType]
+	return ok
+}
+
+// HasDelay для связи допустима задержка (precedes/follows)
+func (relationType RdRelationType) HasDelay() bool {
+	return relationType == RelationPrecedes || relationType == RelationFollows
+}
+
+// Inverse тип связи с точки зрения второй задачи
+func (relationType RdRelationType) Inverse() RdRelationType {
+	return inverseRelationTypes[relationType]
+}
+
 /*
 RdIssueRelation Issue Relations
 http://www.redmine.org/projects/redmine/wiki/Rest_IssueRelations
 */
 type RdIssueRelation struct {
-	ID          int    `json:"id,omitempty"`
-	IssueID     int    `json:"issue_id,omitempty"`
-	IssueToID   int    `json:"issue_to_id,omitempty"`
-	RelaionType string `json:"relation_type,omitempty"`
+	ID           int            `json:"id,omitempty"`
+	IssueID      int            `json:"issue_id,omitempty"`
+	IssueToID    int            `json:"issue_to_id,omitempty"`
+	RelationType RdRelationType `json:"relation_type,omitempty"`
+	Delay        int            `json:"delay,omitempty"`
 }
 
 func (issueRelation *RdIssueRelation) UnmarshalJSON(data []byte) error {
-	issueRelationData := map[string]*RdIssueRelation{"relation": &RdIssueRelation{}}
-	err := json.Unmarshal(data, &issueRelationData)
-	*issueRelation = *issueRelationData["relation"]
+	issueRelationValue, err := unwrapEntity[RdIssueRelationData](data, "relation")
+	*issueRelation = *issueRelationValue.ToIssueRelation()
 	return err
 }
 
 func (issueRelation *RdIssueRelation) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdIssueRelation{"relation": issueRelation})
+	if err := issueRelation.Validate(); err != nil {
+		return nil, err
+	}
+
+	type rdIssueRelation RdIssueRelation
+	return json.Marshal(map[string]*rdIssueRelation{"relation": (*rdIssueRelation)(issueRelation)})
+}
+
+// Validate проверка типа связи и задержки перед отправкой
+func (issueRelation *RdIssueRelation) Validate() error {
+	if issueRelation.RelationType != "" && !issueRelation.RelationType.IsValid() {
+		return fmt.Errorf("redmineclient: unknown relation type %q", issueRelation.RelationType)
+	}
+	if issueRelation.Delay != 0 && !issueRelation.RelationType.HasDelay() {
+		return fmt.Errorf("redmineclient: delay is not allowed for relation type %q", issueRelation.RelationType)
+	}
+
+	return nil
+}
+
+// Inverse связь с точки зрения второй задачи
+func (issueRelation *RdIssueRelation) Inverse() *RdIssueRelation {
+	return &RdIssueRelation{
+		ID:           issueRelation.ID,
+		IssueID:      issueRelation.IssueToID,
+		IssueToID:    issueRelation.IssueID,
+		RelationType: issueRelation.RelationType.Inverse(),
+		Delay:        issueRelation.Delay,
+	}
+}
+
+// ForIssue связь с точки зрения задачи issueID
+func (issueRelation *RdIssueRelation) ForIssue(issueID int) *RdIssueRelation {
+	if issueRelation.IssueID != issueID && issueRelation.IssueToID == issueID {
+		return issueRelation.Inverse()
+	}
+
+	return issueRelation
+}
+
+type RdIssueRelationData struct {
+	ID           int            `json:"id"`
+	IssueID      int            `json:"issue_id"`
+	IssueToID    int            `json:"issue_to_id"`
+	RelationType RdRelationType `json:"relation_type"`
+	Delay        *int           `json:"delay"`
+}
+
+func (issueRelationData *RdIssueRelationData) ToIssueRelation() *RdIssueRelation {
+	issueRelation := &RdIssueRelation{
+		ID:           issueRelationData.ID,
+		IssueID:      issueRelationData.IssueID,
+		IssueToID:    issueRelationData.IssueToID,
+		RelationType: issueRelationData.RelationType,
+	}
+	if issueRelationData.Delay != nil {
+		issueRelation.Delay = *issueRelationData.Delay
+	}
+
+	return issueRelation
 }
 
 type RdVersionList struct {
@@ -538,24 +767,32 @@ http://www.redmine.org/projects/redmine/wiki/Rest_Versions
 */
 type RdVersion struct {
 	ID          int       `json:"id,omitempty"`
-	Project     int       `json:"project,omitempty"`
+	Project     int       `json:"-"`
 	Name        string    `json:"name,omitempty"`
 	Description string    `json:"description,omitempty"`
-	DueDate     time.Time `json:"-,omitempty"`
+	DueDate     time.Time `json:"-"`
 	Sharing     string    `json:"sharing,omitempty"`
 	CreatedOn   time.Time `json:"-"`
 	UpdatedOn   time.Time `json:"-"`
 }
 
 func (version *RdVersion) UnmarshalJSON(data []byte) error {
-	versionData := map[string]*RdVersionData{"version": &RdVersionData{}}
-	err := json.Unmarshal(data, &versionData)
-	*version = *versionData["version"].ToVersion()
+	versionValue, err := unwrapEntity[RdVersionData](data, "version")
+	*version = *versionValue.ToVersion()
 	return err
 }
 
 func (version *RdVersion) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdVersion{"version": version})
+	type rdVersion RdVersion
+	versionData := struct {
+		*rdVersion
+		DueDate string `json:"due_date,omitempty"`
+	}{rdVersion: (*rdVersion)(version)}
+	if !version.DueDate.IsZero() {
+		versionData.DueDate = version.DueDate.Format(DateFormat)
+	}
+
+	return json.Marshal(map[string]interface{}{"version": versionData})
 }
 
 type RdVersionData struct {
@@ -563,19 +800,24 @@ type RdVersionData struct {
 	Project     RdLinkObject `json:"project"`
 	Name        string       `json:"name"`
 	Description string       `json:"description"`
-	DueDate     time.Time    `json:"due_date"`
+	DueDate     string       `json:"due_date"`
 	Sharing     string       `json:"sharing"`
 	CreatedOn   time.Time    `json:"created_on"`
 	UpdatedOn   time.Time    `json:"updated_on"`
 }
 
+// GetDueDate дата выхода версии
+func (versionData *RdVersionData) GetDueDate() time.Time {
+	return parseDate(versionData.DueDate)
+}
+
 func (versionData *RdVersionData) ToVersion() *RdVersion {
 	return &RdVersion{
 		ID:          versionData.ID,
 		Project:     versionData.Project.ID,
 		Name:        versionData.Name,
 		Description: versionData.Description,
-		DueDate:     versionData.DueDate,
+		DueDate:     versionData.GetDueDate(),
 		Sharing:     versionData.Sharing,
 		CreatedOn:   versionData.CreatedOn,
 		UpdatedOn:   versionData.UpdatedOn,
@@ -598,14 +840,14 @@ type RdWikiPage struct {
 }
 
 func (wikiPage *RdWikiPage) UnmarshalJSON(data []byte) error {
-	wikiPageData := map[string]*RdWikiPageData{"wiki_page": &RdWikiPageData{}}
-	err := json.Unmarshal(data, &wikiPageData)
-	*wikiPage = *wikiPageData["wiki_page"].ToWikiPage()
+	wikiPageValue, err := unwrapEntity[RdWikiPageData](data, "wiki_page")
+	*wikiPage = *wikiPageValue.ToWikiPage()
 	return err
 }
 
 func (wikiPage *RdWikiPage) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdWikiPage{"wiki_page": wikiPage})
+	type rdWikiPage RdWikiPage
+	return json.Marshal(map[string]*rdWikiPage{"wiki_page": (*rdWikiPage)(wikiPage)})
 }
 
 type RdWikiPageData struct {
@@ -646,17 +888,6 @@ type RdQuery struct {
 	ProjectID int    `json:"project_id,omitempty"`
 }
 
-func (query *RdQuery) UnmarshalJSON(data []byte) error {
-	queryData := map[string]*RdQuery{"query": &RdQuery{}}
-	err := json.Unmarshal(data, &queryData)
-	*query = *queryData["query"]
-	return err
-}
-
-func (query *RdQuery) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdQuery{"wiki_page": query})
-}
-
 /*
 RdAttachment redmine attachment
 http://www.redmine.org/projects/redmine/wiki/Rest_Attachments
@@ -674,14 +905,14 @@ type RdAttachment struct {
 }
 
 func (attachment *RdAttachment) UnmarshalJSON(data []byte) error {
-	attachmentData := map[string]*RdAttachmentData{"attachment": &RdAttachmentData{}}
-	err := json.Unmarshal(data, &attachmentData)
-	*attachment = *attachmentData["attachment"].ToAttachment()
+	attachmentValue, err := unwrapEntity[RdAttachmentData](data, "attachment")
+	*attachment = *attachmentValue.ToAttachment()
 	return err
 }
 
 func (attachment *RdAttachment) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdAttachment{"attachment": attachment})
+	type rdAttachment RdAttachment
+	return json.Marshal(map[string]*rdAttachment{"attachment": (*rdAttachment)(attachment)})
 }
 
 type RdAttachmentData struct {
@@ -692,7 +923,7 @@ type RdAttachmentData struct {
 	Description  string       `json:"description"`
 	ContentURL   string       `json:"content_url"`
 	ThumbnailURL string       `json:"thumbnail_url"`
-	Author       RdLinkObject `json:"author_id"`
+	Author       RdLinkObject `json:"author"`
 	CreatedOn    time.Time    `json:"created_on"`
 }
 
@@ -710,6 +941,10 @@ func (attachmentData *RdAttachmentData) ToAttachment() *RdAttachment {
 	}
 }
 
+type RdIssueStatusList struct {
+	IssueStatuses []RdIssueStatus `json:"issue_statuses"`
+}
+
 /*
 RdIssueStatus issue statuses redmine
 http://www.redmine.org/projects/redmine/wiki/Rest_IssueStatuses
@@ -736,17 +971,6 @@ type RdTracker struct {
 	DefaultStatus RdLinkObject `json:"default_status"`
 }
 
-func (tracker *RdTracker) UnmarshalJSON(data []byte) error {
-	trackerData := map[string]*RdTracker{"tracker": &RdTracker{}}
-	err := json.Unmarshal(data, &trackerData)
-	*tracker = *trackerData["tracker"]
-	return err
-}
-
-func (tracker *RdTracker) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdTracker{"tracker": tracker})
-}
-
 /*
 RdEnumeration enumerations redmine
 http://www.redmine.org/projects/redmine/wiki/Rest_Enumerations
@@ -770,14 +994,14 @@ type RdIssueCategory struct {
 }
 
 func (issueCategory *RdIssueCategory) UnmarshalJSON(data []byte) error {
-	issueCategoryData := map[string]*RdIssueCategoryData{"issue_category": &RdIssueCategoryData{}}
-	err := json.Unmarshal(data, &issueCategoryData)
-	*issueCategory = *issueCategoryData["issue_category"].ToIssueCategory()
+	issueCategoryValue, err := unwrapEntity[RdIssueCategoryData](data, "issue_category")
+	*issueCategory = *issueCategoryValue.ToIssueCategory()
 	return err
 }
 
 func (issueCategory *RdIssueCategory) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]*RdIssueCategory{"issue_category": issueCategory})
+	type rdIssueCategory RdIssueCategory
+	return json.Marshal(map[string]*rdIssueCategory{"issue_category": (*rdIssueCategory)(issueCategory)})
 }
 
 type RdIssueCategoryData struct {
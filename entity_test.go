@@ -0,0 +1,129 @@
+package redmineclient_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+func TestRelationTypeInverse(t *testing.T) {
+	tests := []struct {
+		relationType redmineclient.RdRelationType
+		inverse      redmineclient.RdRelationType
+		hasDelay     bool
+	}{
+		{redmineclient.RelationRelates, redmineclient.RelationRelates, false},
+		{redmineclient.RelationDuplicates, redmineclient.RelationDuplicated, false},
+		{redmineclient.RelationDuplicated, redmineclient.RelationDuplicates, false},
+		{redmineclient.RelationBlocks, redmineclient.RelationBlocked, false},
+		{redmineclient.RelationBlocked, redmineclient.RelationBlocks, false},
+		{redmineclient.RelationPrecedes, redmineclient.RelationFollows, true},
+		{redmineclient.RelationFollows, redmineclient.RelationPrecedes, true},
+		{redmineclient.RelationCopiedTo, redmineclient.RelationCopiedFrom, false},
+		{redmineclient.RelationCopiedFrom, redmineclient.RelationCopiedTo, false},
+		{"unknown", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.relationType), func(t *testing.T) {
+			if inverse := test.relationType.Inverse(); inverse != test.inverse {
+				t.Errorf("Inverse() = %q, want %q", inverse, test.inverse)
+			}
+			if valid := test.relationType.IsValid(); valid != (test.inverse != "") {
+				t.Errorf("IsValid() = %v", valid)
+			}
+			if hasDelay := test.relationType.HasDelay(); hasDelay != test.hasDelay {
+				t.Errorf("HasDelay() = %v, want %v", hasDelay, test.hasDelay)
+			}
+		})
+	}
+}
+
+func TestIssueRelationValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		relation redmineclient.RdIssueRelation
+		wantErr  bool
+	}{
+		{"relates", redmineclient.RdIssueRelation{RelationType: redmineclient.RelationRelates}, false},
+		{"default type", redmineclient.RdIssueRelation{}, false},
+		{"precedes with delay", redmineclient.RdIssueRelation{RelationType: redmineclient.RelationPrecedes, Delay: 2}, false},
+		{"follows with delay", redmineclient.RdIssueRelation{RelationType: redmineclient.RelationFollows, Delay: 1}, false},
+		{"blocks with delay", redmineclient.RdIssueRelation{RelationType: redmineclient.RelationBlocks, Delay: 1}, true},
+		{"unknown type", redmineclient.RdIssueRelation{RelationType: "depends"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.relation.Validate(); (err != nil) != test.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestIssueRelationForIssue(t *testing.T) {
+	relation := &redmineclient.RdIssueRelation{ID: 7, IssueID: 1, IssueToID: 2, RelationType: redmineclient.RelationPrecedes, Delay: 3}
+	tests := []struct {
+		name    string
+		issueID int
+		want    redmineclient.RdIssueRelation
+	}{
+		{"source issue", 1, *relation},
+		{"target issue", 2, redmineclient.RdIssueRelation{ID: 7, IssueID: 2, IssueToID: 1, RelationType: redmineclient.RelationFollows, Delay: 3}},
+		{"other issue", 5, *relation},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := relation.ForIssue(test.issueID); *got != test.want {
+				t.Errorf("ForIssue(%d) = %+v, want %+v", test.issueID, *got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalNullEntity(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"null entity", `{"issue": null}`, false},
+		{"missing entity", `{}`, false},
+		{"invalid entity", `{"issue": 5}`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issue := &redmineclient.RdIssue{ID: 3, Subject: "stale"}
+			err := json.Unmarshal([]byte(test.data), issue)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Unmarshal() = %v, want error %v", err, test.wantErr)
+			}
+			if issue.ID != 0 || issue.Subject != "" {
+				t.Errorf("issue = %+v, want empty", issue)
+			}
+		})
+	}
+}
+
+func TestIssueRelationRoundTrip(t *testing.T) {
+	_, client, project := newClient(t)
+	first := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "first"})
+	second := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "second"})
+
+	created := client.CreateIssueRelation(&redmineclient.RdIssueRelation{IssueID: first.ID, IssueToID: second.ID, RelationType: redmineclient.RelationPrecedes, Delay: 2})
+	if created.ID == 0 || created.RelationType != redmineclient.RelationPrecedes || created.Delay != 2 {
+		t.Fatalf("created relation %+v", created)
+	}
+
+	relations := client.GetIssueRelationList(second.ID)
+	if len(relations) != 1 || relations[0].ID != created.ID || relations[0].Delay == nil || *relations[0].Delay != 2 {
+		t.Fatalf("relations of #%d = %+v", second.ID, relations)
+	}
+	if relation := relations[0].ToIssueRelation().ForIssue(second.ID); relation.RelationType != redmineclient.RelationFollows || relation.IssueToID != first.ID {
+		t.Errorf("relation for #%d = %+v", second.ID, relation)
+	}
+}
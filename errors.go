@@ -0,0 +1,69 @@
+package redmineclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// APIError ответ redmine с кодом ошибки
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// Errors сообщения об ошибках из ответа, например ошибки валидации (422)
+	Errors []string
+}
+
+func (err *APIError) Error() string {
+	message := fmt.Sprintf("redmineclient: %v %v: %d %v", err.Method, err.Path, err.StatusCode, http.StatusText(err.StatusCode))
+	if len(err.Errors) > 0 {
+		message += ": " + strings.Join(err.Errors, "; ")
+	}
+
+	return message
+}
+
+// newAPIError ошибка по ответу, тело ответа остаётся доступным для чтения
+func newAPIError(req *http.Request, resp *http.Response) *APIError {
+	apiError := &APIError{
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		StatusCode: resp.StatusCode,
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err == nil {
+		errorList := struct {
+			Errors []string `json:"errors"`
+		}{}
+		json.Unmarshal(data, &errorList)
+		apiError.Errors = errorList.Errors
+	}
+
+	return apiError
+}
+
+// ImpersonationError redmine отклонил запрос от имени пользователя (X-Redmine-Switch-User):
+// пользователь не найден или заблокирован
+type ImpersonationError struct {
+	Login string
+}
+
+func (err *ImpersonationError) Error() string {
+	return fmt.Sprintf("redmineclient: impersonation of %q rejected: user not found or locked", err.Login)
+}
+
+// WithErrorHandler обработчик ошибок запросов: сетевых ошибок, ответов с кодом 4xx/5xx
+// (*APIError) и отказа в смене пользователя (*ImpersonationError)
+func WithErrorHandler(handler func(err error)) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.transport.errorHandler = handler
+		return nil
+	}
+}
@@ -0,0 +1,509 @@
+package redmineclient
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultIssueGraphDepth  = 10
+	DefaultIssueGraphIssues = 500
+
+	issueGraphBatchSize = 100
+)
+
+// IssueGraphOptions ограничения обхода связей задач
+type IssueGraphOptions struct {
+	// MaxDepth глубина обхода от стартовых задач, по умолчанию DefaultIssueGraphDepth
+	MaxDepth int
+	// MaxIssues максимальное количество задач в графе, по умолчанию DefaultIssueGraphIssues
+	MaxIssues int
+	// RelationTypes типы связей для обхода, по умолчанию blocks/blocked/precedes/follows
+	RelationTypes []RdRelationType
+}
+
+func (options *IssueGraphOptions) maxDepth() int {
+	if options == nil || options.MaxDepth <= 0 {
+		return DefaultIssueGraphDepth
+	}
+
+	return options.MaxDepth
+}
+
+func (options *IssueGraphOptions) maxIssues() int {
+	if options == nil || options.MaxIssues <= 0 {
+		return DefaultIssueGraphIssues
+	}
+
+	return options.MaxIssues
+}
+
+func (options *IssueGraphOptions) follows(relationType RdRelationType) bool {
+	if options == nil || len(options.RelationTypes) == 0 {
+		return relationType.IsOrdering()
+	}
+	for _, item := range options.RelationTypes {
+		if item == relationType || item == relationType.Inverse() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsOrdering связь задаёт порядок выполнения задач (blocks/blocked/precedes/follows)
+func (relationType RdRelationType) IsOrdering() bool {
+	switch relationType {
+	case RelationBlocks, RelationBlocked, RelationPrecedes, RelationFollows:
+		return true
+	}
+
+	return false
+}
+
+// IssueGraphEdge связь в графе, для blocks/precedes задача From выполняется раньше To
+type IssueGraphEdge struct {
+	RelationID int
+	From       int
+	To         int
+	Type       RdRelationType
+	Delay      int
+}
+
+// IsOrdering связь задаёт порядок выполнения задач
+func (edge IssueGraphEdge) IsOrdering() bool {
+	return edge.Type.IsOrdering()
+}
+
+func newIssueGraphEdge(relation *RdIssueRelationData) IssueGraphEdge {
+	edge := IssueGraphEdge{
+		RelationID: relation.ID,
+		From:       relation.IssueID,
+		To:         relation.IssueToID,
+		Type:       relation.RelationType,
+	}
+	if relation.Delay != nil {
+		edge.Delay = *relation.Delay
+	}
+
+	switch relation.RelationType {
+	case RelationBlocked, RelationFollows, RelationDuplicated, RelationCopiedFrom:
+		edge.From, edge.To = edge.To, edge.From
+		edge.Type = relation.RelationType.Inverse()
+	}
+
+	return edge
+}
+
+// IssueGraph граф зависимостей задач
+type IssueGraph struct {
+	Issues map[int]*RdIssueData
+	Edges  []IssueGraphEdge
+	// Truncated обход остановлен по ограничениям глубины или количества задач
+	Truncated bool
+}
+
+// CycleError в графе есть цикл зависимостей
+type CycleError struct {
+	Cycle []int
+}
+
+func (err *CycleError) Error() string {
+	ids := make([]string, 0, len(err.Cycle))
+	for _, id := range err.Cycle {
+		ids = append(ids, "#"+strconv.Itoa(id))
+	}
+
+	return "redmineclient: dependency cycle " + strings.Join(ids, " -> ")
+}
+
+// GetIssueGraph граф зависимостей, начиная с задач issueIDs.
+// Задачи читаются списками по 100 вместе со связями (include=relations).
+// При ошибке возвращается прочитанная часть графа с Truncated и ошибка
+func (arc *ApiRedmineClient) GetIssueGraph(issueIDs []int, options *IssueGraphOptions) (*IssueGraph, error) {
+	graph := &IssueGraph{Issues: map[int]*RdIssueData{}}
+	err := arc.crawlIssueGraph(graph, issueIDs, options)
+
+	return graph, err
+}
+
+// GetIssueGraphByVersion граф зависимостей задач версии.
+// Если задач версии больше MaxIssues, граф строится по первым из них и помечается Truncated
+func (arc *ApiRedmineClient) GetIssueGraphByVersion(versionID int, options *IssueGraphOptions) (*IssueGraph, error) {
+	graph := &IssueGraph{Issues: map[int]*RdIssueData{}}
+	issueIDs := []int{}
+	for offset := 0; len(issueIDs) < options.maxIssues(); offset += issueGraphBatchSize {
+		page, err := issueData.list(arc, []string{
+			"fixed_version_id=" + strconv.Itoa(versionID),
+			"status_id=*",
+			"include=relations",
+			"sort=id",
+			"limit=" + strconv.Itoa(issueGraphBatchSize),
+			"offset=" + strconv.Itoa(offset),
+		})
+		if err != nil {
+			graph.Truncated = true
+			return graph, fmt.Errorf("redmineclient: issues of version %d: %w", versionID, err)
+		}
+		for i := range page.Items {
+			if len(issueIDs) >= options.maxIssues() {
+				break
+			}
+			graph.Issues[page.Items[i].ID] = &page.Items[i]
+			issueIDs = append(issueIDs, page.Items[i].ID)
+		}
+		if page.TotalCount > options.maxIssues() {
+			graph.Truncated = true
+		}
+		if len(page.Items) < issueGraphBatchSize || offset+len(page.Items) >= page.TotalCount {
+			break
+		}
+	}
+
+	return graph, arc.crawlIssueGraph(graph, issueIDs, options)
+}
+
+func (arc *ApiRedmineClient) crawlIssueGraph(graph *IssueGraph, issueIDs []int, options *IssueGraphOptions) error {
+	maxDepth := options.maxDepth()
+	maxIssues := options.maxIssues()
+	visited := map[int]bool{}
+	seenRelations := map[int]bool{}
+
+	level := []int{}
+	for _, id := range issueIDs {
+		if visited[id] {
+			continue
+		}
+		if len(visited) >= maxIssues {
+			graph.Truncated = true
+			break
+		}
+		visited[id] = true
+		level = append(level, id)
+	}
+
+	for depth := 0; len(level) > 0; depth++ {
+		if err := arc.fetchGraphIssues(graph, level); err != nil {
+			graph.Truncated = true
+			graph.dropDanglingEdges()
+			return err
+		}
+
+		next := []int{}
+		for _, id := range level {
+			issue := graph.Issues[id]
+			if issue == nil {
+				// задача удалена или недоступна: её связи неизвестны
+				graph.Truncated = true
+				continue
+			}
+			for i := range issue.Relations {
+				relation := &issue.Relations[i]
+				if seenRelations[relation.ID] || !options.follows(relation.RelationType) {
+					continue
+				}
+				seenRelations[relation.ID] = true
+				graph.Edges = append(graph.Edges, newIssueGraphEdge(relation))
+
+				other := relation.IssueToID
+				if other == id {
+					other = relation.IssueID
+				}
+				if visited[other] {
+					continue
+				}
+				if depth+1 > maxDepth || len(visited) >= maxIssues {
+					graph.Truncated = true
+					continue
+				}
+				visited[other] = true
+				next = append(next, other)
+			}
+		}
+		level = next
+	}
+	graph.dropDanglingEdges()
+
+	return nil
+}
+
+// dropDanglingEdges удаляет связи с задачами, не попавшими в граф
+func (graph *IssueGraph) dropDanglingEdges() {
+	edges := graph.Edges[:0]
+	for _, edge := range graph.Edges {
+		if graph.Issues[edge.From] != nil && graph.Issues[edge.To] != nil {
+			edges = append(edges, edge)
+		}
+	}
+	graph.Edges = edges
+}
+
+// fetchGraphIssues читает недостающие задачи графа со связями
+func (arc *ApiRedmineClient) fetchGraphIssues(graph *IssueGraph, issueIDs []int) error {
+	missing := []string{}
+	for _, id := range issueIDs {
+		if graph.Issues[id] == nil {
+			missing = append(missing, strconv.Itoa(id))
+		}
+	}
+
+	for start := 0; start < len(missing); start += issueGraphBatchSize {
+		end := start + issueGraphBatchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		issues, err := issueData.all(arc, []string{
+			"issue_id=" + strings.Join(missing[start:end], ","),
+			"status_id=*",
+			"include=relations",
+		})
+		if err != nil {
+			return fmt.Errorf("redmineclient: fetch issue graph: %w", err)
+		}
+		for i := range issues {
+			graph.Issues[issues[i].ID] = &issues[i]
+		}
+	}
+
+	return nil
+}
+
+func (graph *IssueGraph) sortedIssueIDs() []int {
+	ids := make([]int, 0, len(graph.Issues))
+	for id := range graph.Issues {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+func (graph *IssueGraph) orderingEdges() map[int][]IssueGraphEdge {
+	adjacency := map[int][]IssueGraphEdge{}
+	for _, edge := range graph.Edges {
+		if edge.IsOrdering() {
+			adjacency[edge.From] = append(adjacency[edge.From], edge)
+		}
+	}
+
+	return adjacency
+}
+
+// Cycles циклы зависимостей (компоненты сильной связности по blocks/precedes)
+func (graph *IssueGraph) Cycles() [][]int {
+	adjacency := graph.orderingEdges()
+	index := map[int]int{}
+	lowLink := map[int]int{}
+	onStack := map[int]bool{}
+	stack := []int{}
+	cycles := [][]int{}
+	counter := 0
+
+	var connect func(id int)
+	connect = func(id int) {
+		index[id] = counter
+		lowLink[id] = counter
+		counter++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		selfLoop := false
+		for _, edge := range adjacency[id] {
+			if edge.To == id {
+				selfLoop = true
+			}
+			if _, ok := index[edge.To]; !ok {
+				connect(edge.To)
+				if lowLink[edge.To] < lowLink[id] {
+					lowLink[id] = lowLink[edge.To]
+				}
+			} else if onStack[edge.To] && index[edge.To] < lowLink[id] {
+				lowLink[id] = index[edge.To]
+			}
+		}
+
+		if lowLink[id] != index[id] {
+			return
+		}
+		component := []int{}
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == id {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			sort.Ints(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, id := range graph.sortedIssueIDs() {
+		if _, ok := index[id]; !ok {
+			connect(id)
+		}
+	}
+
+	return cycles
+}
+
+// TopologicalOrder задачи в порядке выполнения, *CycleError если есть цикл
+func (graph *IssueGraph) TopologicalOrder() ([]int, error) {
+	adjacency := graph.orderingEdges()
+	inDegree := map[int]int{}
+	for _, edges := range adjacency {
+		for _, edge := range edges {
+			inDegree[edge.To]++
+		}
+	}
+
+	queue := []int{}
+	for _, id := range graph.sortedIssueIDs() {
+		if inDegree[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+
+	order := make([]int, 0, len(graph.Issues))
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		order = append(order, id)
+
+		ready := []int{}
+		for _, edge := range adjacency[id] {
+			inDegree[edge.To]--
+			if inDegree[edge.To] == 0 {
+				ready = append(ready, edge.To)
+			}
+		}
+		sort.Ints(ready)
+		queue = append(queue, ready...)
+	}
+
+	if len(order) < len(graph.Issues) {
+		cycles := graph.Cycles()
+		if len(cycles) > 0 {
+			return order, &CycleError{Cycle: cycles[0]}
+		}
+	}
+
+	return order, nil
+}
+
+// issueDuration длительность задачи в днях по датам начала и срока
+func issueDuration(issue *RdIssueData) int {
+	startDate := issue.GetStartDate()
+	dueDate := issue.GetDueDate()
+	if startDate.IsZero() || dueDate.IsZero() || dueDate.Before(startDate) {
+		return 0
+	}
+
+	return int(dueDate.Sub(startDate).Hours()/24) + 1
+}
+
+// CriticalPath самая длинная по датам цепочка зависимых задач и её длительность в днях
+func (graph *IssueGraph) CriticalPath() ([]int, int, error) {
+	order, err := graph.TopologicalOrder()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	adjacency := graph.orderingEdges()
+	length := map[int]int{}
+	previous := map[int]int{}
+	for _, id := range order {
+		length[id] += issueDuration(graph.Issues[id])
+	}
+	for _, id := range order {
+		for _, edge := range adjacency[id] {
+			candidate := length[id] + edge.Delay + issueDuration(graph.Issues[edge.To])
+			if candidate > length[edge.To] {
+				length[edge.To] = candidate
+				previous[edge.To] = id
+			}
+		}
+	}
+
+	last, total := 0, -1
+	for _, id := range order {
+		if length[id] > total {
+			last, total = id, length[id]
+		}
+	}
+	if total < 0 {
+		return nil, 0, nil
+	}
+
+	path := []int{last}
+	for {
+		id, ok := previous[path[0]]
+		if !ok {
+			break
+		}
+		path = append([]int{id}, path...)
+	}
+
+	return path, total, nil
+}
+
+func (graph *IssueGraph) issueLabel(id int) string {
+	label := "#" + strconv.Itoa(id)
+	if issue := graph.Issues[id]; issue != nil && issue.Subject != "" {
+		label += " " + issue.Subject
+	}
+
+	return label
+}
+
+func (edge IssueGraphEdge) label() string {
+	if edge.Delay != 0 {
+		return fmt.Sprintf("%v %+dd", edge.Type, edge.Delay)
+	}
+
+	return string(edge.Type)
+}
+
+// DOT граф в формате Graphviz
+func (graph *IssueGraph) DOT() string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ")
+	builder := &strings.Builder{}
+	builder.WriteString("digraph issues {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	for _, id := range graph.sortedIssueIDs() {
+		fmt.Fprintf(builder, "\t\"%d\" [label=\"%v\"];\n", id, escape.Replace(graph.issueLabel(id)))
+	}
+	for _, edge := range graph.Edges {
+		style := ""
+		if !edge.IsOrdering() {
+			style = ", style=dashed, dir=none"
+		}
+		fmt.Fprintf(builder, "\t\"%d\" -> \"%d\" [label=\"%v\"%v];\n", edge.From, edge.To, edge.label(), style)
+	}
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+// Mermaid граф в формате Mermaid flowchart
+func (graph *IssueGraph) Mermaid() string {
+	escape := strings.NewReplacer(`"`, "#quot;", "\n", " ")
+	builder := &strings.Builder{}
+	builder.WriteString("flowchart LR\n")
+	for _, id := range graph.sortedIssueIDs() {
+		fmt.Fprintf(builder, "\ti%d[\"%v\"]\n", id, escape.Replace(graph.issueLabel(id)))
+	}
+	for _, edge := range graph.Edges {
+		arrow := "-->"
+		if !edge.IsOrdering() {
+			arrow = "-.-"
+		}
+		fmt.Fprintf(builder, "\ti%d %v|%v| i%d\n", edge.From, arrow, edge.label(), edge.To)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,271 @@
+package redmineclient_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+// createIssues задачи проекта с датами начала и срока, пустая строка без даты
+func createIssues(client *redmineclient.ApiRedmineClient, project *redmineclient.RdProject, dates ...[2]string) []int {
+	ids := []int{}
+	for i, issueDates := range dates {
+		issue := client.CreateIssue(&redmineclient.RdIssue{
+			Project:   project.ID,
+			Subject:   "task " + strconv.Itoa(i+1),
+			StartDate: date(issueDates[0]),
+			DueDate:   date(issueDates[1]),
+		})
+		ids = append(ids, issue.ID)
+	}
+
+	return ids
+}
+
+func relate(client *redmineclient.ApiRedmineClient, from int, relationType redmineclient.RdRelationType, to int, delay int) {
+	client.CreateIssueRelation(&redmineclient.RdIssueRelation{IssueID: from, IssueToID: to, RelationType: relationType, Delay: delay})
+}
+
+func TestGetIssueGraph(t *testing.T) {
+	_, client, project := newClient(t)
+	ids := createIssues(client, project,
+		[2]string{"2024-03-01", "2024-03-02"},
+		[2]string{"2024-03-04", "2024-03-10"},
+		[2]string{"2024-03-11", "2024-03-11"},
+		[2]string{"", ""},
+		[2]string{"", ""},
+	)
+	relate(client, ids[0], redmineclient.RelationPrecedes, ids[1], 1)
+	relate(client, ids[2], redmineclient.RelationBlocked, ids[1], 0)
+	relate(client, ids[0], redmineclient.RelationRelates, ids[3], 0)
+	relate(client, ids[3], redmineclient.RelationBlocks, ids[4], 0)
+
+	tests := []struct {
+		name          string
+		options       *redmineclient.IssueGraphOptions
+		wantIssues    []int
+		wantEdges     []redmineclient.IssueGraphEdge
+		wantTruncated bool
+	}{
+		{
+			"ordering relations",
+			nil,
+			[]int{ids[0], ids[1], ids[2]},
+			[]redmineclient.IssueGraphEdge{
+				{From: ids[0], To: ids[1], Type: redmineclient.RelationPrecedes, Delay: 1},
+				{From: ids[1], To: ids[2], Type: redmineclient.RelationBlocks},
+			},
+			false,
+		},
+		{
+			"max depth",
+			&redmineclient.IssueGraphOptions{MaxDepth: 1},
+			[]int{ids[0], ids[1]},
+			[]redmineclient.IssueGraphEdge{{From: ids[0], To: ids[1], Type: redmineclient.RelationPrecedes, Delay: 1}},
+			true,
+		},
+		{
+			"max issues",
+			&redmineclient.IssueGraphOptions{MaxIssues: 2},
+			[]int{ids[0], ids[1]},
+			[]redmineclient.IssueGraphEdge{{From: ids[0], To: ids[1], Type: redmineclient.RelationPrecedes, Delay: 1}},
+			true,
+		},
+		{
+			"relation types",
+			&redmineclient.IssueGraphOptions{RelationTypes: []redmineclient.RdRelationType{redmineclient.RelationRelates, redmineclient.RelationBlocks}},
+			[]int{ids[0], ids[3], ids[4]},
+			[]redmineclient.IssueGraphEdge{
+				{From: ids[0], To: ids[3], Type: redmineclient.RelationRelates},
+				{From: ids[3], To: ids[4], Type: redmineclient.RelationBlocks},
+			},
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			graph, err := client.GetIssueGraph([]int{ids[0]}, test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			issues := []int{}
+			for _, id := range ids {
+				if issue := graph.Issues[id]; issue != nil {
+					if issue.ID != id || issue.Subject == "" {
+						t.Errorf("issue #%d = %+v", id, issue)
+					}
+					issues = append(issues, id)
+				}
+			}
+			if !reflect.DeepEqual(issues, test.wantIssues) || len(graph.Issues) != len(test.wantIssues) {
+				t.Errorf("issues = %v, want %v", issues, test.wantIssues)
+			}
+
+			edges := []redmineclient.IssueGraphEdge{}
+			for _, edge := range graph.Edges {
+				if edge.RelationID == 0 {
+					t.Errorf("edge %+v without relation id", edge)
+				}
+				edge.RelationID = 0
+				edges = append(edges, edge)
+			}
+			if !reflect.DeepEqual(edges, test.wantEdges) {
+				t.Errorf("edges = %+v, want %+v", edges, test.wantEdges)
+			}
+			if graph.Truncated != test.wantTruncated {
+				t.Errorf("truncated = %v, want %v", graph.Truncated, test.wantTruncated)
+			}
+		})
+	}
+
+	graph, err := client.GetIssueGraph([]int{ids[2]}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, days, err := graph.CriticalPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{ids[0], ids[1], ids[2]}; !reflect.DeepEqual(path, want) || days != 11 {
+		t.Errorf("CriticalPath() = %v %d, want %v 11", path, days, want)
+	}
+}
+
+func TestGetIssueGraphByVersion(t *testing.T) {
+	_, client, project := newClient(t)
+	version := client.CreateVersion(&redmineclient.RdVersion{Project: project.ID, Name: "1.0"})
+	ids := createIssues(client, project, [2]string{"", ""}, [2]string{"", ""}, [2]string{"", ""})
+	client.UpdateIssue(&redmineclient.RdIssue{ID: ids[0], FixedVersion: version.ID})
+	relate(client, ids[0], redmineclient.RelationBlocks, ids[1], 0)
+
+	graph, err := client.GetIssueGraphByVersion(version.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Issues) != 2 || graph.Issues[ids[0]] == nil || graph.Issues[ids[1]] == nil {
+		t.Errorf("issues = %v, want #%d and #%d", graph.Issues, ids[0], ids[1])
+	}
+	if len(graph.Edges) != 1 {
+		t.Errorf("edges = %+v, want one", graph.Edges)
+	}
+}
+
+func TestGetIssueGraphCycle(t *testing.T) {
+	_, client, project := newClient(t)
+	ids := createIssues(client, project, [2]string{"", ""}, [2]string{"", ""}, [2]string{"", ""}, [2]string{"", ""})
+	relate(client, ids[0], redmineclient.RelationBlocks, ids[1], 0)
+	relate(client, ids[1], redmineclient.RelationBlocks, ids[2], 0)
+	relate(client, ids[2], redmineclient.RelationBlocks, ids[3], 0)
+	relate(client, ids[3], redmineclient.RelationBlocks, ids[1], 0)
+
+	graph, err := client.GetIssueGraph([]int{ids[0]}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cycles := graph.Cycles(); !reflect.DeepEqual(cycles, [][]int{{ids[1], ids[2], ids[3]}}) {
+		t.Errorf("Cycles() = %v", cycles)
+	}
+
+	var cycleErr *redmineclient.CycleError
+	if _, err := graph.TopologicalOrder(); !errors.As(err, &cycleErr) {
+		t.Errorf("TopologicalOrder() error = %v, want *CycleError", err)
+	}
+}
+
+func TestGetIssueGraphByVersionTruncated(t *testing.T) {
+	_, client, project := newClient(t)
+	version := client.CreateVersion(&redmineclient.RdVersion{Project: project.ID, Name: "1.0"})
+	ids := createIssues(client, project, [2]string{"", ""}, [2]string{"", ""}, [2]string{"", ""})
+	for _, id := range ids {
+		client.UpdateIssue(&redmineclient.RdIssue{ID: id, FixedVersion: version.ID})
+	}
+
+	graph, err := client.GetIssueGraphByVersion(version.ID, &redmineclient.IssueGraphOptions{MaxIssues: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Issues) != 2 || !graph.Truncated {
+		t.Errorf("%d issues, truncated %v, want 2 and truncated", len(graph.Issues), graph.Truncated)
+	}
+}
+
+// countingTransport считает запросы по путям
+type countingTransport struct {
+	paths map[string]int
+}
+
+func (transport *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport.paths[req.URL.Path]++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGetIssueGraphReadsRelationsWithIssues(t *testing.T) {
+	srv, client, project := newClient(t)
+	ids := createIssues(client, project, [2]string{"", ""}, [2]string{"", ""}, [2]string{"", ""}, [2]string{"", ""})
+	relate(client, ids[0], redmineclient.RelationBlocks, ids[1], 0)
+	relate(client, ids[0], redmineclient.RelationBlocks, ids[2], 0)
+	relate(client, ids[2], redmineclient.RelationPrecedes, ids[3], 0)
+
+	transport := &countingTransport{paths: map[string]int{}}
+	graph, err := srv.Client(redmineclient.WithTransport(transport)).GetIssueGraph([]int{ids[0]}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Issues) != 4 || len(graph.Edges) != 3 {
+		t.Errorf("%d issues, %d edges, want 4 and 3", len(graph.Issues), len(graph.Edges))
+	}
+	// одна выборка задач на каждый уровень обхода, отдельных запросов связей нет
+	if want := map[string]int{"/issues.json": 3}; !reflect.DeepEqual(transport.paths, want) {
+		t.Errorf("requests = %v, want %v", transport.paths, want)
+	}
+}
+
+func TestGetIssueGraphError(t *testing.T) {
+	srv, client, project := newClient(t)
+	ids := createIssues(client, project, [2]string{"", ""}, [2]string{"", ""})
+	relate(client, ids[0], redmineclient.RelationBlocks, ids[1], 0)
+
+	// второй уровень обхода завершается ошибкой
+	failing := &failingTransport{failAfter: 1}
+	graph, err := srv.Client(redmineclient.WithTransport(failing)).GetIssueGraph([]int{ids[0]}, nil)
+
+	var apiErr *redmineclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("error = %v, want 503", err)
+	}
+	if !graph.Truncated || len(graph.Issues) != 1 || len(graph.Edges) != 0 {
+		t.Errorf("partial graph: %d issues, %d edges, truncated %v", len(graph.Issues), len(graph.Edges), graph.Truncated)
+	}
+
+	if _, err := srv.Client(redmineclient.WithTransport(&failingTransport{})).GetIssueGraphByVersion(1, nil); !errors.As(err, &apiErr) {
+		t.Errorf("GetIssueGraphByVersion() error = %v, want *APIError", err)
+	}
+}
+
+// failingTransport отвечает 503 на запросы после первых failAfter
+type failingTransport struct {
+	failAfter int
+}
+
+func (transport *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if transport.failAfter > 0 {
+		transport.failAfter--
+		return http.DefaultTransport.RoundTrip(req)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Status:     "503 Service Unavailable",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"errors":["maintenance"]}`)),
+		Request:    req,
+	}, nil
+}
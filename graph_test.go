@@ -0,0 +1,216 @@
+package redmineclient
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testIssue задача графа с датами начала и срока
+type testIssue struct {
+	id    int
+	start string
+	due   string
+}
+
+// testGraph граф из задач и связей, From выполняется раньше To
+func testGraph(issues []testIssue, edges ...IssueGraphEdge) *IssueGraph {
+	graph := &IssueGraph{Issues: map[int]*RdIssueData{}, Edges: edges}
+	for _, issue := range issues {
+		graph.Issues[issue.id] = &RdIssueData{ID: issue.id, StartDate: issue.start, DueDate: issue.due}
+	}
+
+	return graph
+}
+
+// ids задачи без дат
+func ids(values ...int) []testIssue {
+	issues := make([]testIssue, 0, len(values))
+	for _, id := range values {
+		issues = append(issues, testIssue{id: id})
+	}
+
+	return issues
+}
+
+func precedes(from, to, delay int) IssueGraphEdge {
+	return IssueGraphEdge{From: from, To: to, Type: RelationPrecedes, Delay: delay}
+}
+
+func blocks(from, to int) IssueGraphEdge {
+	return IssueGraphEdge{From: from, To: to, Type: RelationBlocks}
+}
+
+func relates(from, to int) IssueGraphEdge {
+	return IssueGraphEdge{From: from, To: to, Type: RelationRelates}
+}
+
+func TestNewIssueGraphEdge(t *testing.T) {
+	delay := 2
+	tests := []struct {
+		relationType RdRelationType
+		want         IssueGraphEdge
+	}{
+		{RelationBlocks, IssueGraphEdge{RelationID: 7, From: 1, To: 2, Type: RelationBlocks, Delay: 2}},
+		{RelationBlocked, IssueGraphEdge{RelationID: 7, From: 2, To: 1, Type: RelationBlocks, Delay: 2}},
+		{RelationPrecedes, IssueGraphEdge{RelationID: 7, From: 1, To: 2, Type: RelationPrecedes, Delay: 2}},
+		{RelationFollows, IssueGraphEdge{RelationID: 7, From: 2, To: 1, Type: RelationPrecedes, Delay: 2}},
+		{RelationRelates, IssueGraphEdge{RelationID: 7, From: 1, To: 2, Type: RelationRelates, Delay: 2}},
+		{RelationDuplicated, IssueGraphEdge{RelationID: 7, From: 2, To: 1, Type: RelationDuplicates, Delay: 2}},
+		{RelationCopiedFrom, IssueGraphEdge{RelationID: 7, From: 2, To: 1, Type: RelationCopiedTo, Delay: 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.relationType), func(t *testing.T) {
+			edge := newIssueGraphEdge(&RdIssueRelationData{ID: 7, IssueID: 1, IssueToID: 2, RelationType: test.relationType, Delay: &delay})
+			if edge != test.want {
+				t.Errorf("edge = %+v, want %+v", edge, test.want)
+			}
+		})
+	}
+}
+
+func TestIssueGraphCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph *IssueGraph
+		want  [][]int
+	}{
+		{"empty", testGraph(nil), [][]int{}},
+		{"chain", testGraph(ids(1, 2, 3), precedes(1, 2, 0), blocks(2, 3)), [][]int{}},
+		{"two issues", testGraph(ids(1, 2), precedes(1, 2, 0), blocks(2, 1)), [][]int{{1, 2}}},
+		{"self loop", testGraph(ids(1, 2), blocks(1, 1), precedes(1, 2, 0)), [][]int{{1}}},
+		{"relates ignored", testGraph(ids(1, 2), precedes(1, 2, 0), relates(2, 1)), [][]int{}},
+		{
+			"two cycles",
+			testGraph(ids(1, 2, 3, 4, 5), precedes(3, 1, 0), precedes(1, 3, 0), precedes(3, 4, 0), blocks(4, 5), blocks(5, 4)),
+			[][]int{{4, 5}, {1, 3}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if cycles := test.graph.Cycles(); !reflect.DeepEqual(cycles, test.want) {
+				t.Errorf("Cycles() = %v, want %v", cycles, test.want)
+			}
+		})
+	}
+}
+
+func TestIssueGraphTopologicalOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		graph     *IssueGraph
+		want      []int
+		wantCycle []int
+	}{
+		{"independent", testGraph(ids(3, 1, 2)), []int{1, 2, 3}, nil},
+		{"chain", testGraph(ids(1, 2, 3), precedes(3, 2, 0), blocks(2, 1)), []int{3, 2, 1}, nil},
+		{"diamond", testGraph(ids(1, 2, 3, 4), precedes(1, 3, 0), precedes(1, 2, 0), blocks(2, 4), blocks(3, 4)), []int{1, 2, 3, 4}, nil},
+		{"relates ignored", testGraph(ids(1, 2), relates(2, 1)), []int{1, 2}, nil},
+		{"cycle", testGraph(ids(1, 2, 3, 4), precedes(1, 2, 0), precedes(2, 3, 0), blocks(3, 2), blocks(3, 4)), []int{1}, []int{2, 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order, err := test.graph.TopologicalOrder()
+			if !reflect.DeepEqual(order, test.want) {
+				t.Errorf("TopologicalOrder() = %v, want %v", order, test.want)
+			}
+
+			var cycleErr *CycleError
+			switch {
+			case test.wantCycle == nil && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case test.wantCycle != nil && !errors.As(err, &cycleErr):
+				t.Errorf("error = %v, want *CycleError", err)
+			case test.wantCycle != nil && !reflect.DeepEqual(cycleErr.Cycle, test.wantCycle):
+				t.Errorf("cycle = %v, want %v", cycleErr.Cycle, test.wantCycle)
+			}
+		})
+	}
+}
+
+func TestIssueGraphCriticalPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		graph    *IssueGraph
+		wantPath []int
+		wantDays int
+	}{
+		{"empty", testGraph(nil), nil, 0},
+		{"single issue", testGraph([]testIssue{{1, "2024-03-04", "2024-03-08"}}), []int{1}, 5},
+		{"issue without dates", testGraph(ids(1)), []int{1}, 0},
+		{
+			"longest branch",
+			testGraph(
+				[]testIssue{{1, "2024-03-01", "2024-03-02"}, {2, "2024-03-03", "2024-03-03"}, {3, "2024-03-03", "2024-03-12"}, {4, "2024-03-13", "2024-03-13"}},
+				precedes(1, 2, 0), precedes(1, 3, 0), precedes(2, 4, 0), precedes(3, 4, 0),
+			),
+			[]int{1, 3, 4}, 13,
+		},
+		{
+			"delay counts",
+			testGraph(
+				[]testIssue{{1, "2024-03-01", "2024-03-01"}, {2, "2024-03-05", "2024-03-06"}, {3, "2024-03-02", "2024-03-04"}},
+				precedes(1, 2, 5), precedes(1, 3, 0),
+			),
+			[]int{1, 2}, 8,
+		},
+		{
+			"relates ignored",
+			testGraph([]testIssue{{1, "2024-03-01", "2024-03-01"}, {2, "2024-03-01", "2024-03-03"}}, relates(1, 2)),
+			[]int{2}, 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, days, err := test.graph.CriticalPath()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(path, test.wantPath) || days != test.wantDays {
+				t.Errorf("CriticalPath() = %v %d, want %v %d", path, days, test.wantPath, test.wantDays)
+			}
+		})
+	}
+}
+
+func TestIssueGraphCriticalPathCycle(t *testing.T) {
+	graph := testGraph(ids(1, 2), blocks(1, 2), blocks(2, 1))
+
+	var cycleErr *CycleError
+	if _, _, err := graph.CriticalPath(); !errors.As(err, &cycleErr) {
+		t.Errorf("error = %v, want *CycleError", err)
+	}
+}
+
+func TestIssueGraphRender(t *testing.T) {
+	graph := testGraph(ids(1, 2), precedes(1, 2, 3), relates(2, 1))
+	graph.Issues[1].Subject = `say "hi"`
+
+	dot := graph.DOT()
+	for _, want := range []string{
+		`"1" [label="#1 say \"hi\""];`,
+		`"2" [label="#2"];`,
+		`"1" -> "2" [label="precedes +3d"];`,
+		`"2" -> "1" [label="relates", style=dashed, dir=none];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT() has no %s:\n%s", want, dot)
+		}
+	}
+
+	mermaid := graph.Mermaid()
+	for _, want := range []string{
+		`i1["#1 say #quot;hi#quot;"]`,
+		"i1 -->|precedes +3d| i2",
+		"i2 -.-|relates| i1",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid() has no %s:\n%s", want, mermaid)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package redmineclient
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// rateLimiter ограничение частоты запросов по алгоритму token bucket
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve резервирует токен и возвращает время ожидания до его появления
+func (limiter *rateLimiter) reserve() time.Duration {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+	limiter.tokens = math.Min(limiter.burst, limiter.tokens+now.Sub(limiter.last).Seconds()*limiter.rate)
+	limiter.last = now
+	limiter.tokens--
+	if limiter.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-limiter.tokens / limiter.rate * float64(time.Second))
+}
+
+func (limiter *rateLimiter) cancel() {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limiter.tokens = math.Min(limiter.burst, limiter.tokens+1)
+}
+
+// Wait ожидание разрешения на запрос с учётом отмены контекста
+func (limiter *rateLimiter) Wait(ctx context.Context) error {
+	wait := limiter.reserve()
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		limiter.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// inFlightLimiter ограничение количества одновременных запросов
+type inFlightLimiter chan struct{}
+
+func (limiter inFlightLimiter) Acquire(ctx context.Context) error {
+	select {
+	case limiter <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (limiter inFlightLimiter) Release() {
+	<-limiter
+}
@@ -0,0 +1,70 @@
+package redmineclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		// waits ожидаемые задержки последовательных запросов
+		waits []time.Duration
+	}{
+		{"within burst", 10, 3, []time.Duration{0, 0, 0}},
+		{"over burst", 10, 2, []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond}},
+		{"burst below one", 4, 0, []time.Duration{0, 250 * time.Millisecond}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			limiter := newRateLimiter(test.rate, test.burst)
+			for i, want := range test.waits {
+				// допуск на время между вызовами reserve
+				if wait := limiter.reserve(); wait > want || wait < want-10*time.Millisecond {
+					t.Errorf("request %d wait = %v, want %v", i+1, wait, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	limiter := newRateLimiter(1, 1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() = %v, want context.DeadlineExceeded", err)
+	}
+
+	// отменённое ожидание возвращает токен: следующий запрос ждёт не больше секунды
+	if wait := limiter.reserve(); wait > time.Second {
+		t.Errorf("wait after cancel = %v, want at most 1s", wait)
+	}
+}
+
+func TestInFlightLimiter(t *testing.T) {
+	limiter := make(inFlightLimiter, 1)
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire() over limit = %v, want context.DeadlineExceeded", err)
+	}
+
+	limiter.Release()
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Errorf("Acquire() after Release = %v", err)
+	}
+}
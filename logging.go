@@ -0,0 +1,159 @@
+package redmineclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	redacted         = "***"
+	maxLoggedBodyLen = 64 << 10
+)
+
+// sensitiveHeaders заголовки с учётными данными
+var sensitiveHeaders = []string{"X-Redmine-API-Key", "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// sensitiveFields поля JSON с учётными данными
+var sensitiveFields = map[string]bool{
+	"password":              true,
+	"password_confirmation": true,
+	"api_key":               true,
+}
+
+// LoggerOptions параметры журналирования запросов
+type LoggerOptions struct {
+	// Bodies журналировать заголовки и тела запросов и ответов
+	Bodies bool
+	// Level уровень записей об успешных запросах, по умолчанию slog.LevelInfo.
+	// Ответы 4xx/5xx пишутся с уровнем Warn, сетевые ошибки с уровнем Error
+	Level slog.Level
+}
+
+// WithLogger журналирование запросов: метод, путь, код ответа, длительность.
+// Ключи API, пароли и параметр key скрываются
+func WithLogger(logger *slog.Logger, options *LoggerOptions) Option {
+	if options == nil {
+		options = &LoggerOptions{}
+	}
+
+	return WithSendMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return logRoundTrip(logger, options, next, req)
+		})
+	})
+}
+
+func logRoundTrip(logger *slog.Logger, options *LoggerOptions, next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+	}
+	if options.Bodies {
+		attrs = append(attrs, slog.Any("request_headers", redactHeaders(req.Header)))
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				data, _ := io.ReadAll(io.LimitReader(body, maxLoggedBodyLen))
+				body.Close()
+				attrs = append(attrs, slog.String("request_body", string(redactJSON(data))))
+			}
+		}
+	}
+
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		logger.LogAttrs(req.Context(), slog.LevelError, "redmine request failed", attrs...)
+		return resp, err
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if options.Bodies {
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		if readErr == nil {
+			if len(data) > maxLoggedBodyLen {
+				data = data[:maxLoggedBodyLen]
+			}
+			attrs = append(attrs,
+				slog.Any("response_headers", redactHeaders(resp.Header)),
+				slog.String("response_body", string(redactJSON(data))),
+			)
+		}
+	}
+
+	level := options.Level
+	if resp.StatusCode >= http.StatusBadRequest {
+		level = slog.LevelWarn
+	}
+	logger.LogAttrs(req.Context(), level, "redmine request", attrs...)
+
+	return resp, err
+}
+
+// redactURL адрес запроса со скрытым параметром key
+func redactURL(requestURL *url.URL) string {
+	redactedURL := *requestURL
+	redactedURL.User = nil
+	query := redactedURL.Query()
+	if query.Has("key") {
+		query.Set("key", redacted)
+		redactedURL.RawQuery = strings.ReplaceAll(query.Encode(), url.QueryEscape(redacted), redacted)
+	}
+
+	return redactedURL.String()
+}
+
+// redactHeaders копия заголовков со скрытыми учётными данными
+func redactHeaders(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range sensitiveHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, redacted)
+		}
+	}
+
+	return header
+}
+
+// redactJSON тело со скрытыми паролями и ключами API, не-JSON данные возвращаются как есть
+func redactJSON(data []byte) []byte {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return data
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return data
+	}
+
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for name, item := range value {
+			if sensitiveFields[name] {
+				value[name] = redacted
+			} else {
+				value[name] = redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	}
+
+	return value
+}
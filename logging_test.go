@@ -0,0 +1,89 @@
+package redmineclient_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"log/slog"
+	"strings"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+func TestLoggerRedactsSecrets(t *testing.T) {
+	const password = "s3cret-passw0rd"
+	basic := base64.StdEncoding.EncodeToString([]byte(redminetest.AdminAPIKey + ":x"))
+
+	tests := []struct {
+		name          string
+		authenticator redmineclient.Authenticator
+		call          func(client *redmineclient.ApiRedmineClient)
+		secrets       []string
+		want          []string
+	}{
+		{
+			"api key header and key in response body",
+			redmineclient.APIKeyHeader(redminetest.AdminAPIKey),
+			func(client *redmineclient.ApiRedmineClient) { client.GetCurrentUser() },
+			[]string{redminetest.AdminAPIKey},
+			[]string{`"X-Redmine-Api-Key":["***"]`, `"api_key":"***"`},
+		},
+		{
+			"api key query",
+			redmineclient.APIKeyQuery(redminetest.AdminAPIKey),
+			func(client *redmineclient.ApiRedmineClient) { client.GetCurrentUser() },
+			[]string{redminetest.AdminAPIKey},
+			[]string{"key=***"},
+		},
+		{
+			"basic auth",
+			redmineclient.BasicAuth(redminetest.AdminAPIKey, "x"),
+			func(client *redmineclient.ApiRedmineClient) { client.GetCurrentUser() },
+			[]string{redminetest.AdminAPIKey, basic},
+			[]string{`"Authorization":["***"]`},
+		},
+		{
+			"password in nested request body",
+			redmineclient.APIKeyHeader(redminetest.AdminAPIKey),
+			func(client *redmineclient.ApiRedmineClient) {
+				client.CreateUser(&redmineclient.RdUser{Login: "jsmith", Firstname: "John", Lastname: "Smith", Mail: "jsmith@example.net", Password: password})
+			},
+			[]string{redminetest.AdminAPIKey, password},
+			[]string{`"password":"***"`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			srv := redminetest.NewServer()
+			defer srv.Close()
+
+			var log bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&log, nil))
+			client := redmineclient.NewApiRedmineClient("", srv.URL,
+				redmineclient.WithAuthenticator(test.authenticator),
+				redmineclient.WithLogger(logger, &redmineclient.LoggerOptions{Bodies: true}),
+				redmineclient.WithErrorHandler(func(err error) { t.Errorf("request failed: %v", err) }),
+			)
+			test.call(client)
+
+			output := log.String()
+			if output == "" {
+				t.Fatal("nothing logged")
+			}
+			for _, secret := range test.secrets {
+				if strings.Contains(output, secret) {
+					t.Errorf("secret %q leaked to log:\n%s", secret, output)
+				}
+			}
+			// JSON обработчик экранирует кавычки во вложенном теле
+			unescaped := strings.ReplaceAll(output, `\"`, `"`)
+			for _, want := range test.want {
+				if !strings.Contains(unescaped, want) {
+					t.Errorf("log does not contain %q:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package redmineclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequestObservation результат запроса для метрик
+type RequestObservation struct {
+	Method string
+	// Route шаблон пути без идентификаторов, например /issues/{id}.json
+	Route string
+	// StatusCode код ответа, 0 при сетевой ошибке
+	StatusCode int
+	// StatusClass класс ответа: 2xx, 3xx, 4xx, 5xx или error
+	StatusClass string
+	// ErrorKind вид ошибки: timeout, canceled, network, http или пусто
+	ErrorKind string
+	Duration  time.Duration
+}
+
+// Metrics приёмник метрик запросов клиента
+type Metrics interface {
+	ObserveRequest(observation RequestObservation)
+}
+
+// WithMetrics сбор метрик по каждому запросу
+func WithMetrics(metrics Metrics) Option {
+	return WithSendMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			metrics.ObserveRequest(newRequestObservation(req, resp, err, time.Since(start)))
+			return resp, err
+		})
+	})
+}
+
+func newRequestObservation(req *http.Request, resp *http.Response, err error, duration time.Duration) RequestObservation {
+	observation := RequestObservation{
+		Method:      req.Method,
+		Route:       RouteTemplate(req.URL.Path),
+		StatusClass: "error",
+		ErrorKind:   errorKind(err),
+		Duration:    duration,
+	}
+	if err == nil && resp != nil {
+		observation.StatusCode = resp.StatusCode
+		observation.StatusClass = strconv.Itoa(resp.StatusCode/100) + "xx"
+		if resp.StatusCode >= http.StatusBadRequest {
+			observation.ErrorKind = "http"
+		}
+	}
+
+	return observation
+}
+
+// errorKind вид ошибки запроса для метрик и трассировки
+func errorKind(err error) string {
+	var netErr net.Error
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &apiErr):
+		return "http"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	}
+
+	return "network"
+}
+
+var numericSegment = regexp.MustCompile(`^\d+(\.\w+)?$`)
+
+// RouteTemplate шаблон пути запроса: идентификаторы задач, проектов и страниц вики
+// заменяются на {id} и {title}, например /projects/{id}/wiki/{title}.json
+func RouteTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		placeholder := ""
+		switch {
+		case i > 0 && segments[i-1] == "projects" && segment != "":
+			placeholder = "{id}"
+		case i > 0 && segments[i-1] == "wiki" && segment != "" && segment != "index.json":
+			placeholder = "{title}"
+		case numericSegment.MatchString(segment):
+			placeholder = "{id}"
+		}
+		if placeholder == "" {
+			continue
+		}
+		if dot := strings.LastIndex(segment, "."); dot >= 0 {
+			placeholder += segment[dot:]
+		}
+		segments[i] = placeholder
+	}
+
+	return strings.Join(segments, "/")
+}
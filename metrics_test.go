@@ -0,0 +1,93 @@
+package redmineclient_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+func TestRouteTemplate(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/issues.json", "/issues.json"},
+		{"/issues/42.json", "/issues/{id}.json"},
+		{"/issues/42/relations.json", "/issues/{id}/relations.json"},
+		{"/projects/demo.json", "/projects/{id}.json"},
+		{"/projects/7/versions.json", "/projects/{id}/versions.json"},
+		{"/projects/demo/wiki/index.json", "/projects/{id}/wiki/index.json"},
+		{"/projects/demo/wiki/Start_page.json", "/projects/{id}/wiki/{title}.json"},
+		{"/projects/demo/wiki/Start_page/3.json", "/projects/{id}/wiki/{title}/{id}.json"},
+		{"/users/current.json", "/users/current.json"},
+		{"/time_entries/5", "/time_entries/{id}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			if got := redmineclient.RouteTemplate(test.path); got != test.want {
+				t.Errorf("RouteTemplate(%q) = %q, want %q", test.path, got, test.want)
+			}
+		})
+	}
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	srv := redminetest.NewServer()
+	defer srv.Close()
+
+	metrics := redmineclient.NewPrometheusMetrics("")
+	client := srv.Client(redmineclient.WithMetrics(metrics))
+	project := client.CreateProject(&redmineclient.RdProject{Name: "Demo", Identifier: "demo"})
+	client.GetProject(project.ID)
+	client.GetProject(project.ID)
+	client.GetIssue(999)
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+
+	output := recorder.Body.String()
+	for _, want := range []string{
+		"# TYPE redmine_client_requests_total counter\n",
+		`redmine_client_requests_total{method="POST",route="/projects.json",status_class="2xx",error_kind=""} 1` + "\n",
+		`redmine_client_requests_total{method="GET",route="/projects/{id}.json",status_class="2xx",error_kind=""} 2` + "\n",
+		`redmine_client_requests_total{method="GET",route="/issues/{id}.json",status_class="4xx",error_kind="http"} 1` + "\n",
+		"# TYPE redmine_client_request_duration_seconds histogram\n",
+		`redmine_client_request_duration_seconds_bucket{method="GET",route="/projects/{id}.json",le="+Inf"} 2` + "\n",
+		`redmine_client_request_duration_seconds_count{method="GET",route="/projects/{id}.json"} 2` + "\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestPrometheusMetricsHistogram(t *testing.T) {
+	metrics := redmineclient.NewPrometheusMetrics("test")
+	for _, duration := range []time.Duration{3 * time.Millisecond, 30 * time.Millisecond, 3 * time.Second} {
+		metrics.ObserveRequest(redmineclient.RequestObservation{Method: "GET", Route: `/say "hi"`, StatusClass: "2xx", Duration: duration})
+	}
+
+	var output strings.Builder
+	metrics.WriteTo(&output)
+	labels := `method="GET",route="/say \"hi\""`
+	for _, want := range []string{
+		`test_request_duration_seconds_bucket{` + labels + `,le="0.005"} 1` + "\n",
+		`test_request_duration_seconds_bucket{` + labels + `,le="0.05"} 2` + "\n",
+		`test_request_duration_seconds_bucket{` + labels + `,le="2.5"} 2` + "\n",
+		`test_request_duration_seconds_bucket{` + labels + `,le="5"} 3` + "\n",
+		`test_request_duration_seconds_sum{` + labels + `} 3.033` + "\n",
+		`test_request_duration_seconds_count{` + labels + `} 3` + "\n",
+	} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, output.String())
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package redmineclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Call вызов метода API клиента
+type Call struct {
+	Context context.Context
+	Method  string
+	// Path путь запроса относительно адреса redmine, включая параметры
+	Path string
+	// Body тело запроса POST/PUT, сериализуется в JSON
+	Body interface{}
+	// Result структура для декодирования ответа, nil если ответ не нужен
+	Result interface{}
+	// Err первая ошибка запроса, заполняется после выполнения вызова
+	Err error
+}
+
+// CallHandler выполнение вызова: формирование запроса, отправка и декодирование ответа
+type CallHandler func(call *Call)
+
+// CallMiddleware обёртка вызова метода API: до next можно изменить путь и тело запроса,
+// после next доступны декодированный ответ и ошибка
+type CallMiddleware func(next CallHandler) CallHandler
+
+// SendMiddleware обёртка отправки сформированного http запроса (с учётом повторов)
+type SendMiddleware func(next http.RoundTripper) http.RoundTripper
+
+// WithMiddleware обёртки вызовов методов API, первая в списке выполняется первой
+func WithMiddleware(middleware ...CallMiddleware) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.transport.callMiddleware = append(arc.transport.callMiddleware, middleware...)
+		return nil
+	}
+}
+
+// WithSendMiddleware обёртки отправки http запросов, первая в списке выполняется первой
+func WithSendMiddleware(middleware ...SendMiddleware) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.transport.sendMiddleware = append(arc.transport.sendMiddleware, middleware...)
+		return nil
+	}
+}
+
+func (arc *ApiRedmineClient) get(path string, result interface{}) error {
+	return arc.do(&Call{Method: http.MethodGet, Path: path, Result: result})
+}
+
+func (arc *ApiRedmineClient) post(path string, body interface{}, result interface{}) error {
+	return arc.do(&Call{Method: http.MethodPost, Path: path, Body: body, Result: result})
+}
+
+func (arc *ApiRedmineClient) put(path string, body interface{}, result interface{}) error {
+	return arc.do(&Call{Method: http.MethodPut, Path: path, Body: body, Result: result})
+}
+
+func (arc *ApiRedmineClient) delete(path string) error {
+	return arc.do(&Call{Method: http.MethodDelete, Path: path})
+}
+
+// do выполнение вызова через цепочку обёрток, возвращает ошибку вызова
+func (arc *ApiRedmineClient) do(call *Call) error {
+	call.Context = arc.scope.ctx
+	if call.Context == nil {
+		call.Context = context.Background()
+	}
+
+	handler := arc.execute
+	middleware := arc.transport.callMiddleware
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	handler(call)
+	if call.Err != nil {
+		arc.scope.captureError(call.Err)
+	}
+
+	return call.Err
+}
+
+func (arc *ApiRedmineClient) execute(call *Call) {
+	if arc.transport.dryRun != nil && call.Method != http.MethodGet {
+		arc.planCall(call)
+		return
+	}
+	handler := arc.send
+	if arc.transport.audit != nil && call.Method != http.MethodGet {
+		handler = func(call *Call) {
+			arc.auditCall(call, arc.send)
+		}
+	}
+	if arc.transport.undo != nil && !arc.scope.skipUndo && isUndoable(call) {
+		write := handler
+		handler = func(call *Call) {
+			arc.undoCall(call, undoResource(call.Path), write)
+		}
+	}
+	if arc.scope.checksVersions() {
+		switch call.Method {
+		case http.MethodGet:
+			if arc.scope.versions != nil {
+				arc.trackedGet(call, handler)
+				return
+			}
+		case http.MethodPut:
+			arc.checkedPut(call, handler)
+			return
+		}
+	}
+
+	handler(call)
+}
+
+// send отправка запроса вызова и декодирование ответа.
+// Ответ, который не удалось декодировать, становится ошибкой вызова
+func (arc *ApiRedmineClient) send(call *Call) {
+	stats := callStats(call)
+	scope := arc.scope
+	scope.err = &call.Err
+
+	data, err := arc.roundTrip(context.WithValue(call.Context, requestScopeKey{}, &scope), call)
+	// ошибки сети и ответы 4xx/5xx транспорт уже сохранил в call.Err и передал обработчику
+	if call.Err != nil {
+		return
+	}
+	if err == nil {
+		// 204 No Content на обновление и удаление приходит без тела
+		if call.Result == nil || len(data) == 0 || stats.statusCode == http.StatusNoContent {
+			return
+		}
+		if err = json.Unmarshal(data, call.Result); err == nil {
+			return
+		}
+		err = fmt.Errorf("redmineclient: %v %v: decode response: %w", call.Method, call.Path, err)
+	}
+	call.Err = err
+	if arc.transport.errorHandler != nil {
+		arc.transport.errorHandler(call.Err)
+	}
+}
+
+// roundTrip запрос вызова через http клиент, параметры копии клиента передаются в контексте ctx
+func (arc *ApiRedmineClient) roundTrip(ctx context.Context, call *Call) ([]byte, error) {
+	var body io.Reader
+	if call.Body != nil {
+		data, err := json.Marshal(call.Body)
+		if err != nil {
+			return nil, fmt.Errorf("redmineclient: %v %v: encode request: %w", call.Method, call.Path, err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, call.Method, arc.baseURL+call.Path, body)
+	if err != nil {
+		return nil, fmt.Errorf("redmineclient: %v %v: %w", call.Method, call.Path, err)
+	}
+	for name, value := range arc.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := arc.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("redmineclient: %v %v: read response: %w", call.Method, call.Path, err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,114 @@
+package redmineclient_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	var order []string
+	call := func(name string) redmineclient.CallMiddleware {
+		return func(next redmineclient.CallHandler) redmineclient.CallHandler {
+			return func(call *redmineclient.Call) {
+				order = append(order, name+" "+call.Method)
+				next(call)
+				order = append(order, name+" done")
+			}
+		}
+	}
+	send := func(name string) redmineclient.SendMiddleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" "+req.Method)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	_, client, project := newClient(t,
+		redmineclient.WithMiddleware(call("call 1"), call("call 2")),
+		redmineclient.WithSendMiddleware(send("send 1"), send("send 2")),
+	)
+
+	order = nil
+	client.GetProject(project.ID)
+	want := []string{"call 1 GET", "call 2 GET", "send 1 GET", "send 2 GET", "call 2 done", "call 1 done"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("order %q, want %q", order, want)
+	}
+}
+
+func TestCallMiddlewareChangesCall(t *testing.T) {
+	// обёртка меняет сортировку в пути запроса списка задач
+	var paths []string
+	sortDesc := func(next redmineclient.CallHandler) redmineclient.CallHandler {
+		return func(call *redmineclient.Call) {
+			if call.Method == http.MethodGet && strings.HasPrefix(call.Path, "/issues.json?") {
+				call.Path += "&sort=id:desc"
+			}
+			next(call)
+			paths = append(paths, call.Path)
+		}
+	}
+	_, client, project := newClient(t, redmineclient.WithMiddleware(sortDesc))
+	client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "first"})
+	client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "second"})
+
+	paths = nil
+	issues := client.GetListIssue("project_id=" + project.Identifier)
+	if len(issues) != 2 || issues[0].Subject != "second" || issues[1].Subject != "first" {
+		t.Errorf("issues %+v, want second and first", issues)
+	}
+	if len(paths) != 1 || !strings.HasSuffix(paths[0], "&sort=id:desc") {
+		t.Errorf("paths %q", paths)
+	}
+}
+
+func TestDecodeErrorSurfaced(t *testing.T) {
+	brokenBody := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err == nil && req.Method == http.MethodGet {
+				resp.Body.Close()
+				resp.Body = io.NopCloser(strings.NewReader(`{"project": `))
+			}
+			return resp, err
+		})
+	}
+	var callErr error
+	captureCall := func(next redmineclient.CallHandler) redmineclient.CallHandler {
+		return func(call *redmineclient.Call) {
+			next(call)
+			callErr = call.Err
+		}
+	}
+	var handled error
+	srv, _, project := newClient(t)
+	client := srv.Client(
+		redmineclient.WithSendMiddleware(brokenBody),
+		redmineclient.WithMiddleware(captureCall),
+		redmineclient.WithErrorHandler(func(err error) { handled = err }),
+	)
+
+	var err error
+	got := client.With(redmineclient.CaptureError(&err)).GetProject(project.ID)
+	if err == nil || !strings.Contains(err.Error(), "decode response") {
+		t.Fatalf("error = %v, want decode error", err)
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("error = %v, want wrapped json error", err)
+	}
+	if handled != err || callErr != err {
+		t.Errorf("error handler got %v, middleware got %v, want %v", handled, callErr, err)
+	}
+	if got.ID != 0 {
+		t.Errorf("project = %+v, want empty", got)
+	}
+}
@@ -0,0 +1,212 @@
+package redmineclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Option настройка клиента при создании
+type Option func(arc *ApiRedmineClient) error
+
+// clientConfig настройки http клиента, собираемые опциями
+type clientConfig struct {
+	httpClient   *http.Client
+	roundTripper http.RoundTripper
+	timeout      time.Duration
+	proxyURL     *url.URL
+	tlsConfig    *tls.Config
+}
+
+func (config *clientConfig) tls() *tls.Config {
+	if config.tlsConfig == nil {
+		config.tlsConfig = &tls.Config{}
+	}
+
+	return config.tlsConfig
+}
+
+// baseTransport транспорт для отправки запросов с учётом настроек прокси и TLS
+func (config *clientConfig) baseTransport() (http.RoundTripper, error) {
+	base := config.roundTripper
+	if base == nil && config.httpClient != nil {
+		base = config.httpClient.Transport
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if config.proxyURL == nil && config.tlsConfig == nil {
+		return base, nil
+	}
+
+	httpTransport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, errors.New("redmineclient: proxy and TLS options require *http.Transport")
+	}
+	httpTransport = httpTransport.Clone()
+	if config.proxyURL != nil {
+		httpTransport.Proxy = http.ProxyURL(config.proxyURL)
+	}
+	if config.tlsConfig != nil {
+		httpTransport.TLSClientConfig = config.tlsConfig
+	}
+
+	return httpTransport, nil
+}
+
+func (config *clientConfig) apply(arc *ApiRedmineClient) error {
+	base, err := config.baseTransport()
+	if err != nil {
+		return err
+	}
+	arc.transport.base = base
+
+	httpClient := &http.Client{}
+	if config.httpClient != nil {
+		*httpClient = *config.httpClient
+	}
+	httpClient.Transport = arc.transport
+	if config.timeout > 0 {
+		httpClient.Timeout = config.timeout
+	}
+	arc.httpClient = httpClient
+
+	return nil
+}
+
+// WithHTTPClient http клиент, его транспорт используется для отправки запросов.
+// Клиент копируется и не изменяется
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.config.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTransport транспорт для отправки запросов
+func WithTransport(roundTripper http.RoundTripper) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.config.roundTripper = roundTripper
+		return nil
+	}
+}
+
+// WithTimeout ограничение времени запроса, включая повторы
+func WithTimeout(timeout time.Duration) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.config.timeout = timeout
+		return nil
+	}
+}
+
+// WithProxy запросы через прокси
+func WithProxy(proxyURL string) Option {
+	return func(arc *ApiRedmineClient) error {
+		parsedURL, err := url.Parse(proxyURL)
+		if err != nil {
+			return err
+		}
+		arc.config.proxyURL = parsedURL
+		return nil
+	}
+}
+
+// WithCABundle дополнительные корневые сертификаты из PEM файла
+func WithCABundle(path string) Option {
+	return func(arc *ApiRedmineClient) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return WithCACertificates(data)(arc)
+	}
+}
+
+// WithCACertificates дополнительные корневые сертификаты в формате PEM
+func WithCACertificates(pemCerts []byte) Option {
+	return func(arc *ApiRedmineClient) error {
+		tlsConfig := arc.config.tls()
+		if tlsConfig.RootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			tlsConfig.RootCAs = pool
+		}
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pemCerts) {
+			return errors.New("redmineclient: no certificates found in CA bundle")
+		}
+		return nil
+	}
+}
+
+// WithClientCertificate клиентский сертификат для взаимной TLS аутентификации
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(arc *ApiRedmineClient) error {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		tlsConfig := arc.config.tls()
+		tlsConfig.Certificates = append(tlsConfig.Certificates, certificate)
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify отключение проверки сертификата сервера, только для отладки
+func WithInsecureSkipVerify() Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.config.tls().InsecureSkipVerify = true
+		return nil
+	}
+}
+
+// WithUserAgent заголовок User-Agent запросов
+func WithUserAgent(userAgent string) Option {
+	return WithHeaders(map[string]string{"User-Agent": userAgent})
+}
+
+// WithHeaders дополнительные заголовки всех запросов
+func WithHeaders(headers map[string]string) Option {
+	return func(arc *ApiRedmineClient) error {
+		for name, value := range headers {
+			arc.headers[name] = value
+		}
+		return nil
+	}
+}
+
+// WithRetryPolicy повтор неудачных запросов
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.transport.retry = policy
+		return nil
+	}
+}
+
+// WithRateLimit не более requestsPerSecond запросов в секунду с допустимым всплеском burst.
+// Ограничение общее для всех горутин, использующих клиент
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(arc *ApiRedmineClient) error {
+		if requestsPerSecond <= 0 {
+			return errors.New("redmineclient: rate limit must be positive")
+		}
+		arc.transport.rateLimiter = newRateLimiter(requestsPerSecond, burst)
+		return nil
+	}
+}
+
+// WithMaxInFlight не более limit одновременно выполняемых запросов
+func WithMaxInFlight(limit int) Option {
+	return func(arc *ApiRedmineClient) error {
+		if limit <= 0 {
+			return errors.New("redmineclient: in-flight limit must be positive")
+		}
+		arc.transport.inFlight = make(inFlightLimiter, limit)
+		return nil
+	}
+}
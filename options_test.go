@@ -0,0 +1,55 @@
+package redmineclient_test
+
+import (
+	"net/http"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestInvalidOptions(t *testing.T) {
+	custom := roundTripperFunc(http.DefaultTransport.RoundTrip)
+	tests := []struct {
+		name    string
+		options []redmineclient.Option
+	}{
+		{"zero rate limit", []redmineclient.Option{redmineclient.WithRateLimit(0, 1)}},
+		{"invalid proxy", []redmineclient.Option{redmineclient.WithProxy("http://proxy:port")}},
+		{"invalid certificates", []redmineclient.Option{redmineclient.WithCACertificates([]byte("not a certificate"))}},
+		{"missing CA bundle", []redmineclient.Option{redmineclient.WithCABundle("testdata/missing.pem")}},
+		{"proxy with custom transport", []redmineclient.Option{redmineclient.WithTransport(custom), redmineclient.WithProxy("http://proxy:3128")}},
+		{"insecure with custom transport", []redmineclient.Option{redmineclient.WithTransport(custom), redmineclient.WithInsecureSkipVerify()}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if client, err := redmineclient.NewApiRedmineClientWithOptions("key", "http://redmine", test.options...); err == nil || client != nil {
+				t.Errorf("NewApiRedmineClientWithOptions = %v, %v, want error", client, err)
+			}
+
+			defer func() {
+				if recover() == nil {
+					t.Error("NewApiRedmineClient did not panic")
+				}
+			}()
+			redmineclient.NewApiRedmineClient("key", "http://redmine", test.options...)
+		})
+	}
+}
+
+func TestValidOptions(t *testing.T) {
+	client := redmineclient.NewApiRedmineClient("key", "http://redmine",
+		redmineclient.WithRateLimit(10, 1),
+		redmineclient.WithProxy("http://proxy:3128"),
+		redmineclient.WithInsecureSkipVerify(),
+	)
+	if client == nil {
+		t.Fatal("client is nil")
+	}
+}
@@ -0,0 +1,137 @@
+package redmineclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets границы гистограммы длительности запросов в секундах
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics метрики запросов в текстовом формате Prometheus,
+// отдаются как http.Handler без внешних зависимостей
+type PrometheusMetrics struct {
+	namespace string
+	buckets   []float64
+
+	mu        sync.Mutex
+	requests  map[requestsKey]uint64
+	durations map[durationKey]*histogram
+}
+
+type requestsKey struct {
+	method, route, statusClass, errorKind string
+}
+
+type durationKey struct {
+	method, route string
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewPrometheusMetrics метрики с префиксом namespace, по умолчанию redmine_client
+func NewPrometheusMetrics(namespace string) *PrometheusMetrics {
+	if namespace == "" {
+		namespace = "redmine_client"
+	}
+
+	return &PrometheusMetrics{
+		namespace: namespace,
+		buckets:   DefaultDurationBuckets,
+		requests:  map[requestsKey]uint64{},
+		durations: map[durationKey]*histogram{},
+	}
+}
+
+func (metrics *PrometheusMetrics) ObserveRequest(observation RequestObservation) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.requests[requestsKey{
+		method:      observation.Method,
+		route:       observation.Route,
+		statusClass: observation.StatusClass,
+		errorKind:   observation.ErrorKind,
+	}]++
+
+	key := durationKey{method: observation.Method, route: observation.Route}
+	hist := metrics.durations[key]
+	if hist == nil {
+		hist = &histogram{counts: make([]uint64, len(metrics.buckets))}
+		metrics.durations[key] = hist
+	}
+	seconds := observation.Duration.Seconds()
+	for i, bound := range metrics.buckets {
+		if seconds <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.sum += seconds
+	hist.count++
+}
+
+func (metrics *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	metrics.WriteTo(w)
+}
+
+// WriteTo запись метрик в текстовом формате Prometheus
+func (metrics *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	builder := &strings.Builder{}
+	requestsName := metrics.namespace + "_requests_total"
+	fmt.Fprintf(builder, "# HELP %v Total number of Redmine API requests.\n# TYPE %v counter\n", requestsName, requestsName)
+	requestKeys := make([]requestsKey, 0, len(metrics.requests))
+	for key := range metrics.requests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		return fmt.Sprint(requestKeys[i]) < fmt.Sprint(requestKeys[j])
+	})
+	for _, key := range requestKeys {
+		fmt.Fprintf(builder, "%v{method=%v,route=%v,status_class=%v,error_kind=%v} %d\n",
+			requestsName, quoteLabel(key.method), quoteLabel(key.route),
+			quoteLabel(key.statusClass), quoteLabel(key.errorKind), metrics.requests[key])
+	}
+
+	durationName := metrics.namespace + "_request_duration_seconds"
+	fmt.Fprintf(builder, "# HELP %v Redmine API request duration in seconds.\n# TYPE %v histogram\n", durationName, durationName)
+	durationKeys := make([]durationKey, 0, len(metrics.durations))
+	for key := range metrics.durations {
+		durationKeys = append(durationKeys, key)
+	}
+	sort.Slice(durationKeys, func(i, j int) bool {
+		return fmt.Sprint(durationKeys[i]) < fmt.Sprint(durationKeys[j])
+	})
+	for _, key := range durationKeys {
+		hist := metrics.durations[key]
+		labels := fmt.Sprintf("method=%v,route=%v", quoteLabel(key.method), quoteLabel(key.route))
+		for i, bound := range metrics.buckets {
+			fmt.Fprintf(builder, "%v_bucket{%v,le=\"%v\"} %d\n",
+				durationName, labels, strconv.FormatFloat(bound, 'g', -1, 64), hist.counts[i])
+		}
+		fmt.Fprintf(builder, "%v_bucket{%v,le=\"+Inf\"} %d\n", durationName, labels, hist.count)
+		fmt.Fprintf(builder, "%v_sum{%v} %v\n", durationName, labels, strconv.FormatFloat(hist.sum, 'g', -1, 64))
+		fmt.Fprintf(builder, "%v_count{%v} %d\n", durationName, labels, hist.count)
+	}
+
+	written, err := io.WriteString(w, builder.String())
+	return int64(written), err
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,401 @@
+package redmineclient
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// queueIssueFields поля тела запроса со ссылкой на задачу, временные id в них заменяются настоящими
+var queueIssueFields = []string{"id", "issue_id", "parent_issue_id"}
+
+// errNoCreatedID ответ на создание задачи без её id, операция считается отклонённой
+var errNoCreatedID = errors.New("no issue id in response")
+
+// QueuedOperation изменение, ожидающее отправки в redmine
+type QueuedOperation struct {
+	ID int `json:"id"`
+	// Method и Path запрос, Path может содержать временный id задачи
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Body тело запроса в том виде, в котором оно будет отправлено
+	Body json.RawMessage `json:"body"`
+	// TempID временный id задачи, которую создаёт операция
+	TempID int `json:"temp_id,omitempty"`
+	// Key ключ создаваемой задачи, по нему повторное воспроизведение находит уже созданную задачу
+	Key string `json:"key,omitempty"`
+	// IssueID задача, к которой относится операция: настоящий или временный id, 0 если без задачи
+	IssueID  int       `json:"issue_id,omitempty"`
+	QueuedAt time.Time `json:"queued_at"`
+	Attempts int       `json:"attempts,omitempty"`
+	// Error ошибка последней попытки
+	Error string `json:"error,omitempty"`
+}
+
+// Queue очередь изменений в файле для отправки, когда redmine станет доступен.
+// Создаваемые задачи получают временные отрицательные id, которые можно использовать
+// в последующих операциях очереди: при воспроизведении они заменяются настоящими.
+// Если задан способ пометки (см. OpenQueue), создаваемая задача помечается ключом, и если ответ
+// на создание потерян, следующее воспроизведение находит задачу по ключу вместо повторного создания.
+// Без пометки, как и для списаний трудозатрат, потерянный ответ приводит к дубликату при повторном воспроизведении
+type Queue struct {
+	mu sync.Mutex
+	// replay не даёт воспроизводить очередь одновременно, mu на время запросов не удерживается
+	replay sync.Mutex
+	path   string
+	dedupe *DedupeOptions
+	file   queueFile
+}
+
+type queueFile struct {
+	NextID     int                `json:"next_id"`
+	NextTempID int                `json:"next_temp_id"`
+	TempIDs    map[string]int     `json:"temp_ids"`
+	Operations []*QueuedOperation `json:"operations"`
+}
+
+// OpenQueue очередь в файле path, файл создаётся при первой операции.
+// dedupe способ пометки создаваемых задач ключом: CustomFieldID или явно заданный SubjectMarker;
+// nil или пустые параметры отключают пометку, и тема задачи отправляется без изменений.
+// При каждом открытии очереди передаются одни и те же параметры
+func OpenQueue(path string, dedupe *DedupeOptions) (*Queue, error) {
+	if dedupe != nil && dedupe.CustomFieldID == 0 && dedupe.SubjectMarker == "" {
+		dedupe = nil
+	}
+	queue := &Queue{path: path, dedupe: dedupe, file: queueFile{NextID: 1, NextTempID: -1, TempIDs: map[string]int{}}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return queue, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redmineclient: read queue: %w", err)
+	}
+	if err := json.Unmarshal(data, &queue.file); err != nil {
+		return nil, fmt.Errorf("redmineclient: parse queue %v: %w", path, err)
+	}
+	if queue.file.TempIDs == nil {
+		queue.file.TempIDs = map[string]int{}
+	}
+
+	return queue, nil
+}
+
+// CreateIssue создание задачи, возвращает временный id
+func (queue *Queue) CreateIssue(issue *RdIssue) (int, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	operation := &QueuedOperation{Method: http.MethodPost, Path: "/issues.json"}
+	if queue.dedupe != nil {
+		key, err := newQueueKey()
+		if err != nil {
+			return 0, err
+		}
+		operation.Key = key
+		issue = queue.dedupe.withKey(issue, key)
+	}
+	tempID := queue.file.NextTempID
+	queue.file.NextTempID--
+	operation.TempID, operation.IssueID = tempID, tempID
+	err := queue.add(operation, issue)
+
+	return tempID, err
+}
+
+// UpdateIssue обновление задачи, id может быть временным
+func (queue *Queue) UpdateIssue(issue *RdIssue) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	return queue.add(&QueuedOperation{Method: http.MethodPut, Path: fmt.Sprintf("/issues/%d.json", issue.ID), IssueID: issue.ID}, issue)
+}
+
+// CreateTimeEntrie списание трудозатрат, id задачи может быть временным
+func (queue *Queue) CreateTimeEntrie(timeEntrie *RdTimeEntrie) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	return queue.add(&QueuedOperation{Method: http.MethodPost, Path: "/time_entries.json", IssueID: timeEntrie.Issue}, timeEntrie)
+}
+
+// Pending операции, ожидающие отправки, в порядке добавления
+func (queue *Queue) Pending() []QueuedOperation {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	operations := make([]QueuedOperation, 0, len(queue.file.Operations))
+	for _, operation := range queue.file.Operations {
+		operations = append(operations, *operation)
+	}
+
+	return operations
+}
+
+// RealID настоящий id задачи, созданной с временным id, 0 если задача ещё не создана
+func (queue *Queue) RealID(tempID int) int {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	return queue.file.TempIDs[strconv.Itoa(tempID)]
+}
+
+// Drop удаление операции из очереди, например после неустранимой ошибки
+func (queue *Queue) Drop(id int) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	operations := []*QueuedOperation{}
+	for _, operation := range queue.file.Operations {
+		if operation.ID != id {
+			operations = append(operations, operation)
+		}
+	}
+	queue.file.Operations = operations
+
+	return queue.save()
+}
+
+// newQueueKey случайный ключ создаваемой задачи
+func newQueueKey() (string, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("redmineclient: queue key: %w", err)
+	}
+
+	return "queue-" + hex.EncodeToString(random), nil
+}
+
+func (queue *Queue) add(operation *QueuedOperation, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	operation.ID = queue.file.NextID
+	operation.Body = data
+	operation.QueuedAt = time.Now()
+	queue.file.NextID++
+	queue.file.Operations = append(queue.file.Operations, operation)
+
+	return queue.save()
+}
+
+// save запись очереди во временный файл и замена им прежнего
+func (queue *Queue) save() error {
+	data, err := json.MarshalIndent(queue.file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(queue.path), 0o755); err != nil {
+		return fmt.Errorf("redmineclient: write queue: %w", err)
+	}
+	tmp := queue.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("redmineclient: write queue: %w", err)
+	}
+	if err := os.Rename(tmp, queue.path); err != nil {
+		return fmt.Errorf("redmineclient: write queue: %w", err)
+	}
+
+	return nil
+}
+
+// resolve настоящий id для временного, ok=false если задача ещё не создана
+func resolveTempID(tempIDs map[string]int, id int) (int, bool) {
+	if id >= 0 {
+		return id, true
+	}
+	realID, ok := tempIDs[strconv.Itoa(id)]
+
+	return realID, ok
+}
+
+// queueRequest путь и тело операции с настоящими id задач
+func queueRequest(operation *QueuedOperation, tempIDs map[string]int) (string, json.RawMessage, bool) {
+	path := operation.Path
+	if operation.TempID == 0 && operation.IssueID < 0 && strings.HasPrefix(path, "/issues/") {
+		realID, ok := resolveTempID(tempIDs, operation.IssueID)
+		if !ok {
+			return "", nil, false
+		}
+		path = fmt.Sprintf("/issues/%d.json", realID)
+	}
+
+	body := map[string]map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(operation.Body))
+	decoder.UseNumber()
+	if decoder.Decode(&body) != nil {
+		return path, operation.Body, true
+	}
+	for _, fields := range body {
+		for _, name := range queueIssueFields {
+			number, isNumber := fields[name].(json.Number)
+			if !isNumber {
+				continue
+			}
+			id, _ := strconv.Atoi(number.String())
+			realID, ok := resolveTempID(tempIDs, id)
+			if !ok {
+				return "", nil, false
+			}
+			fields[name] = realID
+		}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return path, operation.Body, true
+	}
+
+	return path, data, true
+}
+
+// QueueReport результат воспроизведения очереди
+type QueueReport struct {
+	// Applied выполненные и удалённые из очереди операции
+	Applied []QueuedOperation
+	// Failed операции, отклонённые redmine, остаются в очереди с текстом ошибки
+	Failed []QueuedOperation
+	// Skipped операции, не выполненные из-за ошибки предыдущей операции той же задачи
+	Skipped []QueuedOperation
+	// Err ошибка соединения, из-за которой воспроизведение остановлено, остальные операции остались в очереди
+	Err error
+}
+
+// ReplayQueue отправка операций очереди в порядке добавления. Ошибка операции задачи
+// откладывает её последующие операции до следующего воспроизведения, операции других задач выполняются.
+// Ошибка соединения останавливает воспроизведение. Операции, добавленные во время воспроизведения,
+// отправляются при следующем
+func (arc *ApiRedmineClient) ReplayQueue(queue *Queue) *QueueReport {
+	queue.replay.Lock()
+	defer queue.replay.Unlock()
+
+	queue.mu.Lock()
+	tempIDs := map[string]int{}
+	for tempID, realID := range queue.file.TempIDs {
+		tempIDs[tempID] = realID
+	}
+	operations := make([]QueuedOperation, 0, len(queue.file.Operations))
+	for _, operation := range queue.file.Operations {
+		operations = append(operations, *operation)
+	}
+	queue.mu.Unlock()
+
+	// ошибки операций попадают в отчёт, а не в CaptureError вызывающего
+	var captured error
+	client := arc.With(CaptureError(&captured))
+	report := &QueueReport{}
+	blocked := map[int]bool{}
+	replayed := map[int]QueuedOperation{}
+	for _, operation := range operations {
+		path, body, ready := queueRequest(&operation, tempIDs)
+		if blocked[operation.IssueID] || !ready {
+			if operation.IssueID != 0 {
+				blocked[operation.IssueID] = true
+			}
+			report.Skipped = append(report.Skipped, operation)
+			continue
+		}
+
+		createdID, err := client.replayOperation(queue, &operation, path, body)
+		if arc.transport.dryRun == nil {
+			operation.Attempts++
+		}
+		if err != nil && !isRejection(err) {
+			operation.Error = err.Error()
+			replayed[operation.ID] = operation
+			report.Err = err
+			break
+		}
+		if err != nil {
+			operation.Error = err.Error()
+			replayed[operation.ID] = operation
+			if operation.IssueID != 0 {
+				blocked[operation.IssueID] = true
+			}
+			report.Failed = append(report.Failed, operation)
+			continue
+		}
+
+		if operation.TempID != 0 {
+			tempIDs[strconv.Itoa(operation.TempID)] = createdID
+		}
+		operation.Error = ""
+		replayed[operation.ID] = operation
+		report.Applied = append(report.Applied, operation)
+	}
+
+	// в режиме dry-run запросы не отправлялись, очередь остаётся прежней
+	if arc.transport.dryRun != nil {
+		return report
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	queue.file.TempIDs = tempIDs
+	remaining := []*QueuedOperation{}
+	for _, operation := range queue.file.Operations {
+		result, ok := replayed[operation.ID]
+		if ok && result.Error == "" {
+			continue
+		}
+		if ok {
+			operation.Attempts, operation.Error = result.Attempts, result.Error
+		}
+		remaining = append(remaining, operation)
+	}
+	queue.file.Operations = remaining
+	if err := queue.save(); err != nil && report.Err == nil {
+		report.Err = err
+	}
+
+	return report
+}
+
+// replayOperation отправка операции, для создания задачи возвращает её id.
+// Задача, уже созданная прошлым воспроизведением с потерянным ответом, находится по ключу
+func (arc *ApiRedmineClient) replayOperation(queue *Queue, operation *QueuedOperation, path string, body json.RawMessage) (int, error) {
+	if operation.Method == http.MethodPut {
+		return 0, arc.put(path, body, nil)
+	}
+	if operation.TempID != 0 && operation.Key != "" && queue.dedupe != nil {
+		existing, err := arc.findIssueByKey(0, operation.Key, queue.dedupe)
+		if err != nil {
+			return 0, err
+		}
+		if existing != nil {
+			return existing.ID, nil
+		}
+	}
+
+	result := map[string]struct {
+		ID int `json:"id"`
+	}{}
+	if err := arc.post(path, body, &result); err != nil || operation.TempID == 0 || arc.transport.dryRun != nil {
+		return 0, err
+	}
+	for _, created := range result {
+		if created.ID != 0 {
+			return created.ID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("redmineclient: %v %v: %w", operation.Method, path, errNoCreatedID)
+}
+
+// isRejection ответ redmine с отказом, в отличие от ошибки соединения
+func isRejection(err error) bool {
+	apiErr := &APIError{}
+	impersonationErr := &ImpersonationError{}
+
+	return errors.As(err, &apiErr) || errors.As(err, &impersonationErr) || errors.Is(err, errNoCreatedID)
+}
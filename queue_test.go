@@ -0,0 +1,142 @@
+package redmineclient_test
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+// lossyTransport теряет ответы на первые lost POST запросов, запросы при этом выполняются
+type lossyTransport struct {
+	lost int
+}
+
+func (transport *lossyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil && req.Method == http.MethodPost && transport.lost > 0 {
+		transport.lost--
+		resp.Body.Close()
+		return nil, errors.New("connection reset")
+	}
+
+	return resp, err
+}
+
+func TestReplayQueue(t *testing.T) {
+	srv, client, project := newClient(t)
+	existing := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "existing"})
+
+	path := filepath.Join(t.TempDir(), "queue", "queue.json")
+	queue, err := redmineclient.OpenQueue(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, _ := queue.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "parent"})
+	child, _ := queue.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "child", Parent: parent})
+	queue.UpdateIssue(&redmineclient.RdIssue{ID: parent, DoneRatio: redmineclient.Int(40)})
+	queue.CreateTimeEntrie(&redmineclient.RdTimeEntrie{Issue: child, Hours: 1.5, Activity: redminetest.ActivityDevelopment})
+	rejected, _ := queue.CreateIssue(&redmineclient.RdIssue{Subject: "without project"})
+	queue.UpdateIssue(&redmineclient.RdIssue{ID: rejected, Subject: "never sent"})
+	queue.UpdateIssue(&redmineclient.RdIssue{ID: existing.ID, Subject: "edited"})
+	if parent >= 0 || child >= 0 || parent == child {
+		t.Fatalf("temporary ids %d %d", parent, child)
+	}
+
+	// redmine недоступен: воспроизведение останавливается, очередь не меняется
+	offline := redmineclient.NewApiRedmineClient(redminetest.AdminAPIKey, "http://127.0.0.1:1")
+	if report := offline.ReplayQueue(queue); report.Err == nil || len(report.Applied) != 0 || len(queue.Pending()) != 7 {
+		t.Fatalf("offline replay: %+v, %d pending", report, len(queue.Pending()))
+	}
+
+	reopened, err := redmineclient.OpenQueue(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// отклонённая операция ожидается, воспроизведение без обработчика ошибок теста
+	report := srv.Client().ReplayQueue(reopened)
+	if report.Err != nil || len(report.Applied) != 5 || len(report.Failed) != 1 || len(report.Skipped) != 1 {
+		t.Fatalf("report: applied %d, failed %d, skipped %d, err %v", len(report.Applied), len(report.Failed), len(report.Skipped), report.Err)
+	}
+	if report.Failed[0].Error == "" || report.Failed[0].TempID != rejected || report.Skipped[0].IssueID != rejected {
+		t.Errorf("failed %+v, skipped %+v", report.Failed[0], report.Skipped[0])
+	}
+
+	parentID, childID := reopened.RealID(parent), reopened.RealID(child)
+	if parentID <= 0 || childID <= 0 {
+		t.Fatalf("real ids %d %d", parentID, childID)
+	}
+	if issue := client.GetIssue(childID); issue.Parent.ID != parentID || !strings.Contains(issue.Subject, "child") {
+		t.Errorf("child %q parent #%d, want #%d", issue.Subject, issue.Parent.ID, parentID)
+	}
+	if issue := client.GetIssue(parentID); *issue.DoneRatio != 40 {
+		t.Errorf("parent done ratio %d, want 40", *issue.DoneRatio)
+	}
+	if entries := client.GetListTimeEntrie("issue_id=" + strconv.Itoa(childID)); len(entries) != 1 || entries[0].Hours != 1.5 {
+		t.Errorf("time entries of #%d = %+v", childID, entries)
+	}
+	if subject := client.GetIssue(existing.ID).Subject; subject != "edited" {
+		t.Errorf("existing subject %q", subject)
+	}
+
+	persisted, err := redmineclient.OpenQueue(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending := persisted.Pending(); len(pending) != 2 || persisted.RealID(parent) != parentID {
+		t.Errorf("persisted queue: %d pending, parent #%d", len(pending), persisted.RealID(parent))
+	}
+}
+
+func TestReplayQueueLostResponse(t *testing.T) {
+	srv, client, project := newClient(t)
+	queue, err := redmineclient.OpenQueue(filepath.Join(t.TempDir(), "queue.json"), &redmineclient.DedupeOptions{SubjectMarker: "[%s]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tempID, _ := queue.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "lost"})
+	queue.UpdateIssue(&redmineclient.RdIssue{ID: tempID, DoneRatio: redmineclient.Int(20)})
+
+	// задача создана, но ответ потерян: операция остаётся в очереди
+	lossy := srv.Client(redmineclient.WithTransport(&lossyTransport{lost: 1}))
+	if report := lossy.ReplayQueue(queue); report.Err == nil || len(queue.Pending()) != 2 {
+		t.Fatalf("lossy replay: err %v, %d pending", report.Err, len(queue.Pending()))
+	}
+
+	// повторное воспроизведение находит задачу по ключу вместо создания дубликата
+	if report := client.ReplayQueue(queue); report.Err != nil || len(report.Applied) != 2 {
+		t.Fatalf("replay: applied %d, err %v", len(report.Applied), report.Err)
+	}
+	issues := client.GetListIssue("project_id=" + strconv.Itoa(project.ID))
+	if len(issues) != 1 || issues[0].ID != queue.RealID(tempID) || *issues[0].DoneRatio != 20 {
+		t.Errorf("issues = %+v, real id #%d", issues, queue.RealID(tempID))
+	}
+	if len(queue.Pending()) != 0 {
+		t.Errorf("%d operations pending", len(queue.Pending()))
+	}
+}
+
+func TestQueueWithoutDedupeKeepsSubject(t *testing.T) {
+	_, client, project := newClient(t)
+	for _, dedupe := range []*redmineclient.DedupeOptions{nil, {}} {
+		queue, err := redmineclient.OpenQueue(filepath.Join(t.TempDir(), "queue.json"), dedupe)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tempID, _ := queue.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "plain"})
+		if pending := queue.Pending(); len(pending) != 1 || pending[0].Key != "" {
+			t.Errorf("pending %+v, want operation without key", pending)
+		}
+		if report := client.ReplayQueue(queue); report.Err != nil {
+			t.Fatal(report.Err)
+		}
+		if subject := client.GetIssue(queue.RealID(tempID)).Subject; subject != "plain" {
+			t.Errorf("subject %q, want plain", subject)
+		}
+	}
+}
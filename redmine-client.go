@@ -1,7 +1,6 @@
 package redmineclient
 
 import (
-	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -9,107 +8,134 @@ import (
 	apihttpclient "github.com/alex19pov31/api-http-client"
 )
 
-func NewApiRedmineClient(token string, baseURL string) *ApiRedmineClient {
-	return &ApiRedmineClient{
-		ApiHTTPClient: apihttpclient.NewApiHTTPClient(
-			baseURL,
-			&http.Client{},
-		).SetHeaders(map[string]string{
-			"X-Redmine-API-Key": token,
-			"Content-Type":      "application/json",
-		}),
+// NewApiRedmineClient клиент redmine. Паникует, если опцию не удалось применить
+// (например, неверный лимит частоты запросов, прокси или TLS), чтобы клиент не работал
+// молча без них. Чтобы получить ошибку опций, используйте NewApiRedmineClientWithOptions
+func NewApiRedmineClient(token string, baseURL string, options ...Option) *ApiRedmineClient {
+	arc, err := NewApiRedmineClientWithOptions(token, baseURL, options...)
+	if err != nil {
+		panic(err)
 	}
+
+	return arc
+}
+
+// NewApiRedmineClientWithOptions клиент redmine, ошибки в опциях возвращаются.
+// Пустой token допустим при аутентификации через WithAuthenticator
+func NewApiRedmineClientWithOptions(token string, baseURL string, options ...Option) (*ApiRedmineClient, error) {
+	arc := newApiRedmineClient(token, baseURL)
+	for _, option := range options {
+		if err := option(arc); err != nil {
+			return nil, err
+		}
+	}
+	if err := arc.config.apply(arc); err != nil {
+		return nil, err
+	}
+	arc.ApiHTTPClient = apihttpclient.NewApiHTTPClient(baseURL, arc.httpClient).SetHeaders(arc.headers)
+
+	return arc, nil
+}
+
+func newApiRedmineClient(token string, baseURL string) *ApiRedmineClient {
+	arc := &ApiRedmineClient{
+		baseURL: baseURL,
+		headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		transport: newRedmineTransport(),
+		config:    &clientConfig{},
+	}
+	if token != "" {
+		arc.transport.authenticator = APIKeyHeader(token)
+	}
+	if parsedURL, err := url.Parse(baseURL); err == nil {
+		arc.transport.host = parsedURL.Host
+	}
+
+	return arc
 }
 
 type ApiRedmineClient struct {
 	*apihttpclient.ApiHTTPClient
+	baseURL    string
+	headers    map[string]string
+	httpClient *http.Client
+	transport  *redmineTransport
+	config     *clientConfig
+	scope      requestScope
+}
+
+// SetRetryPolicy повтор неудачных запросов, nil отключает повторы.
+// Настраивается до начала работы с клиентом
+func (arc *ApiRedmineClient) SetRetryPolicy(policy *RetryPolicy) *ApiRedmineClient {
+	arc.transport.retry = policy
+	return arc
 }
 
 // GetCurrentUser текущий пользователь
 func (arc *ApiRedmineClient) GetCurrentUser() *RdUser {
-	user := &RdUser{}
-	arc.GetRequest("/users/current.json").JSONUnmarshal(user)
-
-	return user
+	return users.Get(arc, "current")
 }
 
 // GetUser возвращает пользователя по id
 func (arc *ApiRedmineClient) GetUser(id int) *RdUser {
-	user := &RdUser{}
-	url := fmt.Sprintf("/users/%d.json", id)
-	arc.GetRequest(url).JSONUnmarshal(user)
-
-	return user
+	return users.Get(arc, id)
 }
 
 // CreateUser новый пользователь
 func (arc *ApiRedmineClient) CreateUser(user *RdUser) *RdUser {
-	arc.PostJSONRequest("/users.json", user).JSONUnmarshal(user)
-
-	return user
+	return users.Create(arc, user)
 }
 
 // UpdateUser обновление данных пользователя
 func (arc *ApiRedmineClient) UpdateUser(user *RdUser) *RdUser {
-	url := fmt.Sprintf("/users/%d.json", user.ID)
-	arc.PutJSONRequest(url, user).JSONUnmarshal(user)
-
-	return user
+	return users.Update(arc, user.ID, user)
 }
 
 // DeleteUser удаление пользователя по id
 func (arc *ApiRedmineClient) DeleteUser(id int) {
-	url := fmt.Sprintf("/users/%d.json", id)
-	arc.DeleteRequest(url)
+	users.Delete(arc, id)
 }
 
 // GetUserList список пользователей
 func (arc *ApiRedmineClient) GetUserList(filter ...string) []RdUserData {
-	userList := RdUserList{}
-	url := "/users.json" + arc.CompileGetParams(filter...)
-	arc.GetRequest(url).JSONUnmarshal(&userList)
-
-	return userList.Users
+	return userList.List(arc, filter...)
 }
 
 // GetIssue получить задачу
 func (arc *ApiRedmineClient) GetIssue(id int) *RdIssueData {
-	issue := &RdIssueData{}
-	url := fmt.Sprintf("/issues/%d.json?include=journals,attachments", id)
-	arc.GetRequest(url).JSONUnmarshal(issue)
-
-	return issue
+	return issueData.Get(arc, id, "include=journals,attachments")
 }
 
 // CreateIssue создать задачу
 func (arc *ApiRedmineClient) CreateIssue(issue *RdIssue) *RdIssue {
-	arc.PostJSONRequest("/issues.json", issue).JSONUnmarshal(issue)
-
-	return issue
+	return issues.Create(arc, issue)
 }
 
-// UpdateIssue Обновить задачу
+// UpdateIssue Обновить задачу, возвращает задачу после обновления (пустую при ошибке и в режиме dry-run)
 func (arc *ApiRedmineClient) UpdateIssue(issue *RdIssue) *RdIssueData {
-	url := fmt.Sprintf("/issues/%d.json", issue.ID)
-	dataIssue := &RdIssueData{}
-	arc.PutJSONRequest(url, issue).JSONUnmarshal(dataIssue)
+	issueData, _ := arc.updateIssue(issue)
+	return issueData
+}
+
+func (arc *ApiRedmineClient) updateIssue(issue *RdIssue) (*RdIssueData, error) {
+	// redmine отвечает на обновление 204 No Content, задача читается заново
+	if err := issues.update(arc, issue.ID, issue); err != nil || arc.transport.dryRun != nil {
+		return &RdIssueData{}, err
+	}
 
-	return dataIssue
+	return arc.GetIssue(issue.ID), nil
 }
 
 // DeleteIssue удалить задачу
 func (arc *ApiRedmineClient) DeleteIssue(id int) {
-	url := fmt.Sprintf("/issues/%d.json", id)
-	arc.DeleteRequest(url)
+	issues.Delete(arc, id)
 }
 
 // GetListIssue список задач
 func (arc *ApiRedmineClient) GetListIssue(filter ...string) []RdIssueData {
-	issueList := RdIssueList{}
-	url := "/issues.json" + arc.CompileGetParams(filter...)
-	arc.GetRequest(url).JSONUnmarshal(&issueList)
-
-	return issueList.Issues
+	return issueData.List(arc, filter...)
 }
 
 // GetListIssueByProject список задач проекта
@@ -131,353 +157,239 @@ func (arc *ApiRedmineClient) GetMyListIssueByProject(projectID, statusID int) []
 
 // GetProject получить проект
 func (arc *ApiRedmineClient) GetProject(id int) *RdProject {
-	project := &RdProject{}
-	url := fmt.Sprintf("/projects/%d.json", id)
-	arc.GetRequest(url).JSONUnmarshal(project)
-	return project
+	return projects.Get(arc, id)
 }
 
 // GetProjectByCode получить проект по коду
 func (arc *ApiRedmineClient) GetProjectByCode(code string) *RdProject {
-	project := &RdProject{}
-	url := "/projects/" + code + ".json"
-	arc.GetRequest(url).JSONUnmarshal(project)
-	return project
+	return projects.Get(arc, code)
 }
 
 // CreateProject создать проект
 func (arc *ApiRedmineClient) CreateProject(project *RdProject) *RdProject {
-	arc.PostJSONRequest("/projects.json", project).JSONUnmarshal(project)
-	return project
+	return projects.Create(arc, project)
 }
 
 // UpdateProject обновить проект
 func (arc *ApiRedmineClient) UpdateProject(project *RdProject) *RdProject {
-	url := fmt.Sprintf("/projects/%d.json", project.ID)
-	arc.PutJSONRequest(url, project).JSONUnmarshal(project)
-	return project
+	return projects.Update(arc, project.ID, project)
 }
 
 // DeleteProject удалить проект
 func (arc *ApiRedmineClient) DeleteProject(id int) {
-	url := fmt.Sprintf("/projects/%d.json", id)
-	arc.DeleteRequest(url)
+	projects.Delete(arc, id)
 }
 
 // GetProjectList список проектов
 func (arc *ApiRedmineClient) GetProjectList(filter ...string) []RdProjectData {
-	projectList := RdProjectList{}
-	url := "/projects.json" + arc.CompileGetParams(filter...)
-	arc.GetRequest(url).JSONUnmarshal(&projectList)
-
-	return projectList.Projects
+	return projectList.List(arc, filter...)
 }
 
 func (arc *ApiRedmineClient) GetMembership(id int) *RdMembership {
-	membership := &RdMembership{}
-	url := fmt.Sprintf("/memberships/%d.json", id)
-	arc.GetRequest(url).JSONUnmarshal(membership)
-
-	return membership
+	return memberships.Get(arc, id)
 }
 
 func (arc *ApiRedmineClient) CreateMembership(membership *RdMembership) *RdMembership {
-	url := fmt.Sprintf("/memberships/%d.json", membership.ID)
-	arc.PostJSONRequest(url, membership).JSONUnmarshal(membership)
-
-	return membership
+	return memberships.In(membership.Project).Create(arc, membership)
 }
 
 func (arc *ApiRedmineClient) UpdateMembership(membership *RdMembership) *RdMembership {
-	url := fmt.Sprintf("/memberships/%d.json", membership.ID)
-	arc.PutJSONRequest(url, membership).JSONUnmarshal(membership)
-
-	return membership
+	return memberships.Update(arc, membership.ID, membership)
 }
 
 func (arc *ApiRedmineClient) DeleteMembership(id int) {
-	url := fmt.Sprintf("/memberships/%d.json", id)
-	arc.DeleteRequest(url)
+	memberships.Delete(arc, id)
 }
 
 func (arc *ApiRedmineClient) GetMembershipList(projectID int) []RdMembershipData {
-	list := RdMembershipList{}
-	url := fmt.Sprintf("/projects/%d/memberships.json", projectID)
-	arc.GetRequest(url).JSONUnmarshal(&list)
-
-	return list.Memberships
+	return membershipList.In(projectID).List(arc)
 }
 
 func (arc *ApiRedmineClient) GetMembershipListByCode(projectCode string) []RdMembershipData {
-	list := RdMembershipList{}
-	url := fmt.Sprintf("/projects/%v/memberships.json", projectCode)
-	arc.GetRequest(url).JSONUnmarshal(&list)
-
-	return list.Memberships
+	return membershipList.In(projectCode).List(arc)
 }
 
 func (arc *ApiRedmineClient) GetIssueRelation(id int) *RdIssueRelation {
-	relation := &RdIssueRelation{}
-	url := fmt.Sprintf("/relations/%d.json", id)
-	arc.GetRequest(url).JSONUnmarshal(relation)
-
-	return relation
+	return issueRelations.Get(arc, id)
 }
 
 func (arc *ApiRedmineClient) CreateIssueRelation(relation *RdIssueRelation) *RdIssueRelation {
-	arc.PostJSONRequest("/relations.json", relation).JSONUnmarshal(relation)
-	return relation
+	return issueRelations.In(relation.IssueID).Create(arc, relation)
 }
 
 func (arc *ApiRedmineClient) UpdateIssueRelation(relation *RdIssueRelation) *RdIssueRelation {
-	url := fmt.Sprintf("/relations/%d.json", relation.ID)
-	arc.PutJSONRequest(url, relation).JSONUnmarshal(relation)
-	return relation
+	return issueRelations.Update(arc, relation.ID, relation)
 }
 
 func (arc *ApiRedmineClient) DeleteIssueRelation(id int) {
-	url := fmt.Sprintf("/relations/%d.json", id)
-	arc.DeleteRequest(url)
+	issueRelations.Delete(arc, id)
 }
 
-func (arc *ApiRedmineClient) GetIssueRelationList(id int) []RdIssueRelation {
-	relationList := RdIssueRelationList{}
-	url := fmt.Sprintf("/issues/%d/relations.json", id)
-	arc.GetRequest(url).JSONUnmarshal(&relationList)
-
-	return relationList.IssueRelations
+// GetIssueRelationList связи задачи.
+// Возвращает []RdIssueRelationData вместо прежнего []RdIssueRelation: элементы списка redmine
+// не обёрнуты в "relation" и в RdIssueRelation не декодировались, код вызова нужно обновить
+func (arc *ApiRedmineClient) GetIssueRelationList(id int) []RdIssueRelationData {
+	return issueRelationList.In(id).List(arc)
 }
 
 func (arc *ApiRedmineClient) GetVersion(id int) *RdVersion {
-	versionData := &RdVersionData{}
-	url := fmt.Sprintf("/versions/%d.json", id)
-	arc.GetRequest(url).JSONUnmarshal(versionData)
-
-	return versionData.ToVersion()
+	return versions.Get(arc, id)
 }
 
 func (arc *ApiRedmineClient) CreateVersion(version *RdVersion) *RdVersion {
-	arc.PostJSONRequest("/versions.json", version).JSONUnmarshal(version)
-	return version
+	return versions.In(version.Project).Create(arc, version)
 }
 
 func (arc *ApiRedmineClient) UpdateVersion(version *RdVersion) *RdVersion {
-	url := fmt.Sprintf("/versions/%d.json", version.ID)
-	arc.PutJSONRequest(url, version).JSONUnmarshal(version)
-	return version
+	return versions.Update(arc, version.ID, version)
 }
 
 func (arc *ApiRedmineClient) DeleteVersion(id int) {
-	url := fmt.Sprintf("/versions/%d.json", id)
-	arc.DeleteRequest(url)
+	versions.Delete(arc, id)
 }
 
 func (arc *ApiRedmineClient) GetVersionList(projectID int) []RdVersionData {
-	versionList := RdVersionList{}
-	url := fmt.Sprintf("/projects/%d/versions.json", projectID)
-	arc.GetRequest(url).JSONUnmarshal(&versionList)
-
-	return versionList.Versions
+	return versionList.In(projectID).List(arc)
 }
 
 func (arc *ApiRedmineClient) GetVersionByProjectList(projectCode string) []RdVersionData {
-	versionList := RdVersionList{}
-	url := fmt.Sprintf("/projects/%v/versions.json", projectCode)
-	arc.GetRequest(url).JSONUnmarshal(&versionList)
-
-	return versionList.Versions
+	return versionList.In(projectCode).List(arc)
 }
 
 func (arc *ApiRedmineClient) GetWikiPage(url string) *RdWikiPage {
-	wikiPage := &RdWikiPage{}
-	arc.GetRequest(url).JSONUnmarshal(wikiPage)
-
-	return wikiPage
+	return wikiPages.Get(arc, url)
 }
 
 func (arc *ApiRedmineClient) CreateWikiPage(wikiPage *RdWikiPage, url string) *RdWikiPage {
-	arc.PostJSONRequest(url+".json", wikiPage).JSONUnmarshal(wikiPage)
-	return wikiPage
+	return wikiPages.Update(arc, url+".json", wikiPage)
 }
 
 func (arc *ApiRedmineClient) UpdateWikiPage(wikiPage *RdWikiPage, url string) *RdWikiPage {
-	arc.PutJSONRequest(url+".json", wikiPage).JSONUnmarshal(wikiPage)
-	return wikiPage
+	return wikiPages.Update(arc, url+".json", wikiPage)
 }
 
-func (arc *ApiRedmineClient) DeleteWikiPage(id int) {
-	url := fmt.Sprintf("/wikiPages/%d.json", id)
-	arc.DeleteRequest(url)
+// DeleteWikiPage удалить страницу wiki title проекта с идентификатором или кодом project
+func (arc *ApiRedmineClient) DeleteWikiPage(project interface{}, title string) {
+	projectWikiPages.In(project).Delete(arc, url.PathEscape(title))
 }
 
 // GetListQueries список
 func (arc *ApiRedmineClient) GetListQueries() []RdQuery {
-	queryList := []RdQuery{}
-	arc.GetRequest("/queries.json").JSONUnmarshal(&queryList)
-
-	return queryList
+	return queries.List(arc)
 }
 
 func (arc *ApiRedmineClient) GetAttachment(id int) *RdAttachment {
-	attachment := &RdAttachment{}
-	url := fmt.Sprintf("/attachments/%v.json", id)
-	arc.GetRequest(url).JSONUnmarshal(attachment)
-
-	return attachment
+	return attachments.Get(arc, id)
 }
 
 // GetListStatusIssue список статусов
 func (arc *ApiRedmineClient) GetListStatusIssue() []RdIssueStatus {
-	statusList := []RdIssueStatus{}
-	arc.GetRequest("/issue_statuses.json").JSONUnmarshal(&statusList)
-
-	return statusList
+	return issueStatuses.List(arc)
 }
 
 // GetListTracker список трекеров
 func (arc *ApiRedmineClient) GetListTracker() []RdTracker {
-	statusList := RdTrackerList{}
-	arc.GetRequest("/trackers.json").JSONUnmarshal(&statusList)
-
-	return statusList.Trackers
+	return trackers.List(arc)
 }
 
 // GetListEnumeration список перечислений
 func (arc *ApiRedmineClient) GetListEnumeration(listName string) []RdEnumeration {
-	dataEnumeration := map[string][]RdEnumeration{listName: []RdEnumeration{}}
-	arc.GetRequest("/enumerations/" + listName + ".json").JSONUnmarshal(&dataEnumeration)
-
-	return dataEnumeration[listName]
+	return enumerationList(listName).List(arc)
 }
 
 func (arc *ApiRedmineClient) GetIssueCategory(id int) *RdIssueCategoryData {
-	issueCategory := &RdIssueCategoryData{}
-	url := fmt.Sprintf("/issue_categories/%d.json", id)
-	arc.GetRequest(url).JSONUnmarshal(issueCategory)
-
-	return issueCategory
+	return issueCategoryData.Get(arc, id)
 }
 
 func (arc *ApiRedmineClient) CreateIssueCategory(issueCategory *RdIssueCategory) *RdIssueCategory {
-	arc.PostJSONRequest("/issue_categories.json", issueCategory).JSONUnmarshal(issueCategory)
-
-	return issueCategory
+	return issueCategories.In(issueCategory.Project).Create(arc, issueCategory)
 }
 
+// UpdateIssueCategory обновить категорию, возвращает категорию после обновления (пустую при ошибке и в режиме dry-run)
 func (arc *ApiRedmineClient) UpdateIssueCategory(issueCategory *RdIssueCategory) *RdIssueCategoryData {
-	url := fmt.Sprintf("/issue_categories/%d.json", issueCategory.ID)
-	dataIssue := &RdIssueCategoryData{}
-	arc.PutJSONRequest(url, issueCategory).JSONUnmarshal(dataIssue)
+	// redmine отвечает на обновление 204 No Content, категория читается заново
+	if err := issueCategories.update(arc, issueCategory.ID, issueCategory); err != nil || arc.transport.dryRun != nil {
+		return &RdIssueCategoryData{}
+	}
 
-	return dataIssue
+	return arc.GetIssueCategory(issueCategory.ID)
 }
 
 func (arc *ApiRedmineClient) DeleteIssueCategory(id int) {
-	url := fmt.Sprintf("/issue_categories/%d.json", id)
-	arc.DeleteRequest(url)
+	issueCategories.Delete(arc, id)
 }
 
 func (arc *ApiRedmineClient) GetListIssueCategory(projectID int) []RdIssueCategoryData {
-	issueCategoryList := RdIssueCategoryList{}
-	url := fmt.Sprintf("/projects/%d/issue_categories.json", projectID)
-	arc.GetRequest(url).JSONUnmarshal(&issueCategoryList)
-
-	return issueCategoryList.IssueCategories
+	return issueCategoryData.In(projectID).List(arc)
 }
 
 func (arc *ApiRedmineClient) GetListIssueCategoryByProjectCode(projectCode string) []RdIssueCategoryData {
-	issueCategoryList := RdIssueCategoryList{}
-	url := fmt.Sprintf("/projects/%v/issue_categories.json", projectCode)
-	arc.GetRequest(url).JSONUnmarshal(&issueCategoryList)
-
-	return issueCategoryList.IssueCategories
+	return issueCategoryData.In(projectCode).List(arc)
 }
 
 func (arc *ApiRedmineClient) GetRole(id int) *RdRole {
-	roleData := map[string]*RdRole{"role": &RdRole{}}
-	url := fmt.Sprintf("/roles/%d.json", id)
-	arc.GetRequest(url).JSONUnmarshal(roleData)
-
-	return roleData["role"]
+	return roles.Get(arc, id)
 }
 
 func (arc *ApiRedmineClient) GetListRole() []RdRole {
-	roleList := RdRoleList{}
-	arc.GetRequest("/roles.json").JSONUnmarshal(&roleList)
-
-	return roleList.Roles
+	return roles.List(arc)
 }
 
 func (arc *ApiRedmineClient) GetListCustomField() []RdCustomField {
-	customFieldList := RdCustomFieldList{}
-	arc.GetRequest("/custom_fields.json").JSONUnmarshal(&customFieldList)
-
-	return customFieldList.CustomFields
+	return customFields.List(arc)
 }
 
 func (arc *ApiRedmineClient) Search(query string, filter ...string) []RdSearchResult {
-	searchResultList := RdSearchResultList{}
-	query = url.QueryEscape(query)
-	filter = append([]string{"q=" + query}, filter...)
-	arc.GetRequest("/search.json" + arc.CompileGetParams(filter...)).JSONUnmarshal(&searchResultList)
-
-	return searchResultList.SearchResults
+	filter = append([]string{"q=" + url.QueryEscape(query)}, filter...)
+	return searchResults.List(arc, filter...)
 }
 
 func (arc *ApiRedmineClient) SearchByProject(projectID int, query string, filter ...string) []RdSearchResult {
-	searchResultList := RdSearchResultList{}
-	query = url.QueryEscape(query)
-	filter = append([]string{"q=" + query}, filter...)
-	url := fmt.Sprintf("/projects/%d/search.json%v", projectID, arc.CompileGetParams(filter...))
-	arc.GetRequest(url).JSONUnmarshal(&searchResultList)
-
-	return searchResultList.SearchResults
+	filter = append([]string{"q=" + url.QueryEscape(query)}, filter...)
+	return projectSearchResults.In(projectID).List(arc, filter...)
 }
 
 func (arc *ApiRedmineClient) SearchByProjectCode(projectCode string, query string, filter ...string) []RdSearchResult {
-	searchResultList := RdSearchResultList{}
-	query = url.QueryEscape(query)
-	filter = append([]string{"q=" + query}, filter...)
-	url := fmt.Sprintf("/projects/%v/search.json%v", projectCode, arc.CompileGetParams(filter...))
-	arc.GetRequest(url).JSONUnmarshal(&searchResultList)
-
-	return searchResultList.SearchResults
+	filter = append([]string{"q=" + url.QueryEscape(query)}, filter...)
+	return projectSearchResults.In(projectCode).List(arc, filter...)
 }
 
 func (arc *ApiRedmineClient) GetListFile(projectID int) []RdFileData {
-	fileList := RdFileList{}
-	url := fmt.Sprintf("/projects/%d/files.json", projectID)
-	arc.GetRequest(url).JSONUnmarshal(&fileList)
-
-	return fileList.Files
+	return files.In(projectID).List(arc)
 }
-func (arc *ApiRedmineClient) GetListFileByProjectCode(projectCode string) []RdFileData {
-	fileList := RdFileList{}
-	url := fmt.Sprintf("/projects/%v/files.json", projectCode)
-	arc.GetRequest(url).JSONUnmarshal(&fileList)
 
-	return fileList.Files
+func (arc *ApiRedmineClient) GetListFileByProjectCode(projectCode string) []RdFileData {
+	return files.In(projectCode).List(arc)
 }
 
 func (arc *ApiRedmineClient) GetListTimeEntrie(filter ...string) []RdTimeEntrieData {
-	timeEntrieList := RdTimeEntrieList{}
-	arc.GetRequest("/time_entries.json" + arc.CompileGetParams(filter...)).JSONUnmarshal(&timeEntrieList)
-
-	return timeEntrieList.TimeEntries
+	return timeEntryList.List(arc, filter...)
 }
 
 func (arc *ApiRedmineClient) GetListTimeEntrieByProject(projectID int, filter ...string) []RdTimeEntrieData {
-	timeEntrieList := RdTimeEntrieList{}
-	url := fmt.Sprintf("/projects/%d/time_entries.json", projectID, arc.CompileGetParams(filter...))
-	arc.GetRequest(url).JSONUnmarshal(&timeEntrieList)
-
-	return timeEntrieList.TimeEntries
+	return projectTimeEntryList.In(projectID).List(arc, filter...)
 }
 
 func (arc *ApiRedmineClient) GetListTimeEntrieByProjectCode(projectCode string, filter ...string) []RdTimeEntrieData {
-	timeEntrieList := RdTimeEntrieList{}
-	url := fmt.Sprintf("/projects/%v/time_entries.json", projectCode, arc.CompileGetParams(filter...))
-	arc.GetRequest(url).JSONUnmarshal(&timeEntrieList)
+	return projectTimeEntryList.In(projectCode).List(arc, filter...)
+}
+
+// GetTimeEntrie трудозатраты по id
+func (arc *ApiRedmineClient) GetTimeEntrie(id int) *RdTimeEntrie {
+	return timeEntries.Get(arc, id)
+}
+
+// CreateTimeEntrie списать время
+func (arc *ApiRedmineClient) CreateTimeEntrie(timeEntrie *RdTimeEntrie) *RdTimeEntrie {
+	return timeEntries.Create(arc, timeEntrie)
+}
+
+// UpdateTimeEntrie обновить трудозатраты
+func (arc *ApiRedmineClient) UpdateTimeEntrie(timeEntrie *RdTimeEntrie) *RdTimeEntrie {
+	return timeEntries.Update(arc, timeEntrie.ID, timeEntrie)
+}
 
-	return timeEntrieList.TimeEntries
+// DeleteTimeEntrie удалить трудозатраты
+func (arc *ApiRedmineClient) DeleteTimeEntrie(id int) {
+	timeEntries.Delete(arc, id)
 }
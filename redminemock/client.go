@@ -0,0 +1,758 @@
+// Package redminemock мок клиента redmine для модульных тестов.
+//
+// Client реализует redmineclient.Client, записывает вызовы и возвращает ответы,
+// заданные полями <Метод>Func:
+//
+//	mock := &redminemock.Client{
+//		GetIssueFunc: func(id int) *redmineclient.RdIssueData {
+//			return &redmineclient.RdIssueData{ID: id, Subject: "Test"}
+//		},
+//	}
+//	service := NewService(mock)
+//	...
+//	calls := mock.Calls("GetIssue")
+package redminemock
+
+import (
+	"sync"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+// Call вызов метода мока
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Client мок redmineclient.Client.
+// Без заданной функции методы Create* и Update* возвращают переданную сущность,
+// остальные методы пустое значение, как ApiRedmineClient при ошибке запроса
+type Client struct {
+	GetCurrentUserFunc                    func() *redmineclient.RdUser
+	GetUserFunc                           func(id int) *redmineclient.RdUser
+	CreateUserFunc                        func(user *redmineclient.RdUser) *redmineclient.RdUser
+	UpdateUserFunc                        func(user *redmineclient.RdUser) *redmineclient.RdUser
+	DeleteUserFunc                        func(id int)
+	GetUserListFunc                       func(filter ...string) []redmineclient.RdUserData
+	GetIssueFunc                          func(id int) *redmineclient.RdIssueData
+	CreateIssueFunc                       func(issue *redmineclient.RdIssue) *redmineclient.RdIssue
+	CreateIssueIdempotentFunc             func(issue *redmineclient.RdIssue, key string, options *redmineclient.DedupeOptions) (*redmineclient.RdIssue, bool, error)
+	UpdateIssueFunc                       func(issue *redmineclient.RdIssue) *redmineclient.RdIssueData
+	DeleteIssueFunc                       func(id int)
+	GetListIssueFunc                      func(filter ...string) []redmineclient.RdIssueData
+	GetListIssueByProjectFunc             func(projectID, statusID int) []redmineclient.RdIssueData
+	GetMyListIssueByProjectFunc           func(projectID, statusID int) []redmineclient.RdIssueData
+	GetIssueRelationFunc                  func(id int) *redmineclient.RdIssueRelation
+	CreateIssueRelationFunc               func(relation *redmineclient.RdIssueRelation) *redmineclient.RdIssueRelation
+	UpdateIssueRelationFunc               func(relation *redmineclient.RdIssueRelation) *redmineclient.RdIssueRelation
+	DeleteIssueRelationFunc               func(id int)
+	GetIssueRelationListFunc              func(id int) []redmineclient.RdIssueRelationData
+	GetProjectFunc                        func(id int) *redmineclient.RdProject
+	GetProjectByCodeFunc                  func(code string) *redmineclient.RdProject
+	CreateProjectFunc                     func(project *redmineclient.RdProject) *redmineclient.RdProject
+	UpdateProjectFunc                     func(project *redmineclient.RdProject) *redmineclient.RdProject
+	DeleteProjectFunc                     func(id int)
+	GetProjectListFunc                    func(filter ...string) []redmineclient.RdProjectData
+	GetMembershipFunc                     func(id int) *redmineclient.RdMembership
+	CreateMembershipFunc                  func(membership *redmineclient.RdMembership) *redmineclient.RdMembership
+	UpdateMembershipFunc                  func(membership *redmineclient.RdMembership) *redmineclient.RdMembership
+	DeleteMembershipFunc                  func(id int)
+	GetMembershipListFunc                 func(projectID int) []redmineclient.RdMembershipData
+	GetMembershipListByCodeFunc           func(projectCode string) []redmineclient.RdMembershipData
+	GetVersionFunc                        func(id int) *redmineclient.RdVersion
+	CreateVersionFunc                     func(version *redmineclient.RdVersion) *redmineclient.RdVersion
+	UpdateVersionFunc                     func(version *redmineclient.RdVersion) *redmineclient.RdVersion
+	DeleteVersionFunc                     func(id int)
+	GetVersionListFunc                    func(projectID int) []redmineclient.RdVersionData
+	GetVersionByProjectListFunc           func(projectCode string) []redmineclient.RdVersionData
+	GetIssueCategoryFunc                  func(id int) *redmineclient.RdIssueCategoryData
+	CreateIssueCategoryFunc               func(issueCategory *redmineclient.RdIssueCategory) *redmineclient.RdIssueCategory
+	UpdateIssueCategoryFunc               func(issueCategory *redmineclient.RdIssueCategory) *redmineclient.RdIssueCategoryData
+	DeleteIssueCategoryFunc               func(id int)
+	GetListIssueCategoryFunc              func(projectID int) []redmineclient.RdIssueCategoryData
+	GetListIssueCategoryByProjectCodeFunc func(projectCode string) []redmineclient.RdIssueCategoryData
+	GetTimeEntrieFunc                     func(id int) *redmineclient.RdTimeEntrie
+	CreateTimeEntrieFunc                  func(timeEntrie *redmineclient.RdTimeEntrie) *redmineclient.RdTimeEntrie
+	UpdateTimeEntrieFunc                  func(timeEntrie *redmineclient.RdTimeEntrie) *redmineclient.RdTimeEntrie
+	DeleteTimeEntrieFunc                  func(id int)
+	GetListTimeEntrieFunc                 func(filter ...string) []redmineclient.RdTimeEntrieData
+	GetListTimeEntrieByProjectFunc        func(projectID int, filter ...string) []redmineclient.RdTimeEntrieData
+	GetListTimeEntrieByProjectCodeFunc    func(projectCode string, filter ...string) []redmineclient.RdTimeEntrieData
+	GetWikiPageFunc                       func(url string) *redmineclient.RdWikiPage
+	CreateWikiPageFunc                    func(wikiPage *redmineclient.RdWikiPage, url string) *redmineclient.RdWikiPage
+	UpdateWikiPageFunc                    func(wikiPage *redmineclient.RdWikiPage, url string) *redmineclient.RdWikiPage
+	DeleteWikiPageFunc                    func(project interface{}, title string)
+	GetAttachmentFunc                     func(id int) *redmineclient.RdAttachment
+	GetListFileFunc                       func(projectID int) []redmineclient.RdFileData
+	GetListFileByProjectCodeFunc          func(projectCode string) []redmineclient.RdFileData
+	SearchFunc                            func(query string, filter ...string) []redmineclient.RdSearchResult
+	SearchByProjectFunc                   func(projectID int, query string, filter ...string) []redmineclient.RdSearchResult
+	SearchByProjectCodeFunc               func(projectCode string, query string, filter ...string) []redmineclient.RdSearchResult
+	GetListStatusIssueFunc                func() []redmineclient.RdIssueStatus
+	GetListTrackerFunc                    func() []redmineclient.RdTracker
+	GetListEnumerationFunc                func(listName string) []redmineclient.RdEnumeration
+	GetRoleFunc                           func(id int) *redmineclient.RdRole
+	GetListRoleFunc                       func() []redmineclient.RdRole
+	GetListCustomFieldFunc                func() []redmineclient.RdCustomField
+	GetListQueriesFunc                    func() []redmineclient.RdQuery
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ redmineclient.Client = (*Client)(nil)
+
+func (mock *Client) record(method string, args ...interface{}) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	mock.calls = append(mock.calls, Call{Method: method, Args: args})
+}
+
+// Calls вызовы метода method в порядке выполнения, при пустом method вызовы всех методов
+func (mock *Client) Calls(method string) []Call {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	calls := []Call{}
+	for _, call := range mock.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// Reset очистка записанных вызовов
+func (mock *Client) Reset() {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+
+	mock.calls = nil
+}
+
+func (mock *Client) GetCurrentUser() *redmineclient.RdUser {
+	mock.record("GetCurrentUser")
+	if mock.GetCurrentUserFunc != nil {
+		return mock.GetCurrentUserFunc()
+	}
+
+	return &redmineclient.RdUser{}
+}
+
+func (mock *Client) GetUser(id int) *redmineclient.RdUser {
+	mock.record("GetUser", id)
+	if mock.GetUserFunc != nil {
+		return mock.GetUserFunc(id)
+	}
+
+	return &redmineclient.RdUser{}
+}
+
+func (mock *Client) CreateUser(user *redmineclient.RdUser) *redmineclient.RdUser {
+	mock.record("CreateUser", user)
+	if mock.CreateUserFunc != nil {
+		return mock.CreateUserFunc(user)
+	}
+
+	return user
+}
+
+func (mock *Client) UpdateUser(user *redmineclient.RdUser) *redmineclient.RdUser {
+	mock.record("UpdateUser", user)
+	if mock.UpdateUserFunc != nil {
+		return mock.UpdateUserFunc(user)
+	}
+
+	return user
+}
+
+func (mock *Client) DeleteUser(id int) {
+	mock.record("DeleteUser", id)
+	if mock.DeleteUserFunc != nil {
+		mock.DeleteUserFunc(id)
+	}
+}
+
+func (mock *Client) GetUserList(filter ...string) []redmineclient.RdUserData {
+	mock.record("GetUserList", filter)
+	if mock.GetUserListFunc != nil {
+		return mock.GetUserListFunc(filter...)
+	}
+
+	return []redmineclient.RdUserData{}
+}
+
+func (mock *Client) GetIssue(id int) *redmineclient.RdIssueData {
+	mock.record("GetIssue", id)
+	if mock.GetIssueFunc != nil {
+		return mock.GetIssueFunc(id)
+	}
+
+	return &redmineclient.RdIssueData{}
+}
+
+func (mock *Client) CreateIssue(issue *redmineclient.RdIssue) *redmineclient.RdIssue {
+	mock.record("CreateIssue", issue)
+	if mock.CreateIssueFunc != nil {
+		return mock.CreateIssueFunc(issue)
+	}
+
+	return issue
+}
+
+func (mock *Client) CreateIssueIdempotent(issue *redmineclient.RdIssue, key string, options *redmineclient.DedupeOptions) (*redmineclient.RdIssue, bool, error) {
+	mock.record("CreateIssueIdempotent", issue, key, options)
+	if mock.CreateIssueIdempotentFunc != nil {
+		return mock.CreateIssueIdempotentFunc(issue, key, options)
+	}
+
+	return issue, true, nil
+}
+
+func (mock *Client) UpdateIssue(issue *redmineclient.RdIssue) *redmineclient.RdIssueData {
+	mock.record("UpdateIssue", issue)
+	if mock.UpdateIssueFunc != nil {
+		return mock.UpdateIssueFunc(issue)
+	}
+
+	return issueData(issue)
+}
+
+func (mock *Client) DeleteIssue(id int) {
+	mock.record("DeleteIssue", id)
+	if mock.DeleteIssueFunc != nil {
+		mock.DeleteIssueFunc(id)
+	}
+}
+
+func (mock *Client) GetListIssue(filter ...string) []redmineclient.RdIssueData {
+	mock.record("GetListIssue", filter)
+	if mock.GetListIssueFunc != nil {
+		return mock.GetListIssueFunc(filter...)
+	}
+
+	return []redmineclient.RdIssueData{}
+}
+
+func (mock *Client) GetListIssueByProject(projectID, statusID int) []redmineclient.RdIssueData {
+	mock.record("GetListIssueByProject", projectID, statusID)
+	if mock.GetListIssueByProjectFunc != nil {
+		return mock.GetListIssueByProjectFunc(projectID, statusID)
+	}
+
+	return []redmineclient.RdIssueData{}
+}
+
+func (mock *Client) GetMyListIssueByProject(projectID, statusID int) []redmineclient.RdIssueData {
+	mock.record("GetMyListIssueByProject", projectID, statusID)
+	if mock.GetMyListIssueByProjectFunc != nil {
+		return mock.GetMyListIssueByProjectFunc(projectID, statusID)
+	}
+
+	return []redmineclient.RdIssueData{}
+}
+
+func (mock *Client) GetIssueRelation(id int) *redmineclient.RdIssueRelation {
+	mock.record("GetIssueRelation", id)
+	if mock.GetIssueRelationFunc != nil {
+		return mock.GetIssueRelationFunc(id)
+	}
+
+	return &redmineclient.RdIssueRelation{}
+}
+
+func (mock *Client) CreateIssueRelation(relation *redmineclient.RdIssueRelation) *redmineclient.RdIssueRelation {
+	mock.record("CreateIssueRelation", relation)
+	if mock.CreateIssueRelationFunc != nil {
+		return mock.CreateIssueRelationFunc(relation)
+	}
+
+	return relation
+}
+
+func (mock *Client) UpdateIssueRelation(relation *redmineclient.RdIssueRelation) *redmineclient.RdIssueRelation {
+	mock.record("UpdateIssueRelation", relation)
+	if mock.UpdateIssueRelationFunc != nil {
+		return mock.UpdateIssueRelationFunc(relation)
+	}
+
+	return relation
+}
+
+func (mock *Client) DeleteIssueRelation(id int) {
+	mock.record("DeleteIssueRelation", id)
+	if mock.DeleteIssueRelationFunc != nil {
+		mock.DeleteIssueRelationFunc(id)
+	}
+}
+
+func (mock *Client) GetIssueRelationList(id int) []redmineclient.RdIssueRelationData {
+	mock.record("GetIssueRelationList", id)
+	if mock.GetIssueRelationListFunc != nil {
+		return mock.GetIssueRelationListFunc(id)
+	}
+
+	return []redmineclient.RdIssueRelationData{}
+}
+
+func (mock *Client) GetProject(id int) *redmineclient.RdProject {
+	mock.record("GetProject", id)
+	if mock.GetProjectFunc != nil {
+		return mock.GetProjectFunc(id)
+	}
+
+	return &redmineclient.RdProject{}
+}
+
+func (mock *Client) GetProjectByCode(code string) *redmineclient.RdProject {
+	mock.record("GetProjectByCode", code)
+	if mock.GetProjectByCodeFunc != nil {
+		return mock.GetProjectByCodeFunc(code)
+	}
+
+	return &redmineclient.RdProject{}
+}
+
+func (mock *Client) CreateProject(project *redmineclient.RdProject) *redmineclient.RdProject {
+	mock.record("CreateProject", project)
+	if mock.CreateProjectFunc != nil {
+		return mock.CreateProjectFunc(project)
+	}
+
+	return project
+}
+
+func (mock *Client) UpdateProject(project *redmineclient.RdProject) *redmineclient.RdProject {
+	mock.record("UpdateProject", project)
+	if mock.UpdateProjectFunc != nil {
+		return mock.UpdateProjectFunc(project)
+	}
+
+	return project
+}
+
+func (mock *Client) DeleteProject(id int) {
+	mock.record("DeleteProject", id)
+	if mock.DeleteProjectFunc != nil {
+		mock.DeleteProjectFunc(id)
+	}
+}
+
+func (mock *Client) GetProjectList(filter ...string) []redmineclient.RdProjectData {
+	mock.record("GetProjectList", filter)
+	if mock.GetProjectListFunc != nil {
+		return mock.GetProjectListFunc(filter...)
+	}
+
+	return []redmineclient.RdProjectData{}
+}
+
+func (mock *Client) GetMembership(id int) *redmineclient.RdMembership {
+	mock.record("GetMembership", id)
+	if mock.GetMembershipFunc != nil {
+		return mock.GetMembershipFunc(id)
+	}
+
+	return &redmineclient.RdMembership{}
+}
+
+func (mock *Client) CreateMembership(membership *redmineclient.RdMembership) *redmineclient.RdMembership {
+	mock.record("CreateMembership", membership)
+	if mock.CreateMembershipFunc != nil {
+		return mock.CreateMembershipFunc(membership)
+	}
+
+	return membership
+}
+
+func (mock *Client) UpdateMembership(membership *redmineclient.RdMembership) *redmineclient.RdMembership {
+	mock.record("UpdateMembership", membership)
+	if mock.UpdateMembershipFunc != nil {
+		return mock.UpdateMembershipFunc(membership)
+	}
+
+	return membership
+}
+
+func (mock *Client) DeleteMembership(id int) {
+	mock.record("DeleteMembership", id)
+	if mock.DeleteMembershipFunc != nil {
+		mock.DeleteMembershipFunc(id)
+	}
+}
+
+func (mock *Client) GetMembershipList(projectID int) []redmineclient.RdMembershipData {
+	mock.record("GetMembershipList", projectID)
+	if mock.GetMembershipListFunc != nil {
+		return mock.GetMembershipListFunc(projectID)
+	}
+
+	return []redmineclient.RdMembershipData{}
+}
+
+func (mock *Client) GetMembershipListByCode(projectCode string) []redmineclient.RdMembershipData {
+	mock.record("GetMembershipListByCode", projectCode)
+	if mock.GetMembershipListByCodeFunc != nil {
+		return mock.GetMembershipListByCodeFunc(projectCode)
+	}
+
+	return []redmineclient.RdMembershipData{}
+}
+
+func (mock *Client) GetVersion(id int) *redmineclient.RdVersion {
+	mock.record("GetVersion", id)
+	if mock.GetVersionFunc != nil {
+		return mock.GetVersionFunc(id)
+	}
+
+	return &redmineclient.RdVersion{}
+}
+
+func (mock *Client) CreateVersion(version *redmineclient.RdVersion) *redmineclient.RdVersion {
+	mock.record("CreateVersion", version)
+	if mock.CreateVersionFunc != nil {
+		return mock.CreateVersionFunc(version)
+	}
+
+	return version
+}
+
+func (mock *Client) UpdateVersion(version *redmineclient.RdVersion) *redmineclient.RdVersion {
+	mock.record("UpdateVersion", version)
+	if mock.UpdateVersionFunc != nil {
+		return mock.UpdateVersionFunc(version)
+	}
+
+	return version
+}
+
+func (mock *Client) DeleteVersion(id int) {
+	mock.record("DeleteVersion", id)
+	if mock.DeleteVersionFunc != nil {
+		mock.DeleteVersionFunc(id)
+	}
+}
+
+func (mock *Client) GetVersionList(projectID int) []redmineclient.RdVersionData {
+	mock.record("GetVersionList", projectID)
+	if mock.GetVersionListFunc != nil {
+		return mock.GetVersionListFunc(projectID)
+	}
+
+	return []redmineclient.RdVersionData{}
+}
+
+func (mock *Client) GetVersionByProjectList(projectCode string) []redmineclient.RdVersionData {
+	mock.record("GetVersionByProjectList", projectCode)
+	if mock.GetVersionByProjectListFunc != nil {
+		return mock.GetVersionByProjectListFunc(projectCode)
+	}
+
+	return []redmineclient.RdVersionData{}
+}
+
+func (mock *Client) GetIssueCategory(id int) *redmineclient.RdIssueCategoryData {
+	mock.record("GetIssueCategory", id)
+	if mock.GetIssueCategoryFunc != nil {
+		return mock.GetIssueCategoryFunc(id)
+	}
+
+	return &redmineclient.RdIssueCategoryData{}
+}
+
+func (mock *Client) CreateIssueCategory(issueCategory *redmineclient.RdIssueCategory) *redmineclient.RdIssueCategory {
+	mock.record("CreateIssueCategory", issueCategory)
+	if mock.CreateIssueCategoryFunc != nil {
+		return mock.CreateIssueCategoryFunc(issueCategory)
+	}
+
+	return issueCategory
+}
+
+func (mock *Client) UpdateIssueCategory(issueCategory *redmineclient.RdIssueCategory) *redmineclient.RdIssueCategoryData {
+	mock.record("UpdateIssueCategory", issueCategory)
+	if mock.UpdateIssueCategoryFunc != nil {
+		return mock.UpdateIssueCategoryFunc(issueCategory)
+	}
+
+	return issueCategoryData(issueCategory)
+}
+
+func (mock *Client) DeleteIssueCategory(id int) {
+	mock.record("DeleteIssueCategory", id)
+	if mock.DeleteIssueCategoryFunc != nil {
+		mock.DeleteIssueCategoryFunc(id)
+	}
+}
+
+func (mock *Client) GetListIssueCategory(projectID int) []redmineclient.RdIssueCategoryData {
+	mock.record("GetListIssueCategory", projectID)
+	if mock.GetListIssueCategoryFunc != nil {
+		return mock.GetListIssueCategoryFunc(projectID)
+	}
+
+	return []redmineclient.RdIssueCategoryData{}
+}
+
+func (mock *Client) GetListIssueCategoryByProjectCode(projectCode string) []redmineclient.RdIssueCategoryData {
+	mock.record("GetListIssueCategoryByProjectCode", projectCode)
+	if mock.GetListIssueCategoryByProjectCodeFunc != nil {
+		return mock.GetListIssueCategoryByProjectCodeFunc(projectCode)
+	}
+
+	return []redmineclient.RdIssueCategoryData{}
+}
+
+func (mock *Client) GetTimeEntrie(id int) *redmineclient.RdTimeEntrie {
+	mock.record("GetTimeEntrie", id)
+	if mock.GetTimeEntrieFunc != nil {
+		return mock.GetTimeEntrieFunc(id)
+	}
+
+	return &redmineclient.RdTimeEntrie{}
+}
+
+func (mock *Client) CreateTimeEntrie(timeEntrie *redmineclient.RdTimeEntrie) *redmineclient.RdTimeEntrie {
+	mock.record("CreateTimeEntrie", timeEntrie)
+	if mock.CreateTimeEntrieFunc != nil {
+		return mock.CreateTimeEntrieFunc(timeEntrie)
+	}
+
+	return timeEntrie
+}
+
+func (mock *Client) UpdateTimeEntrie(timeEntrie *redmineclient.RdTimeEntrie) *redmineclient.RdTimeEntrie {
+	mock.record("UpdateTimeEntrie", timeEntrie)
+	if mock.UpdateTimeEntrieFunc != nil {
+		return mock.UpdateTimeEntrieFunc(timeEntrie)
+	}
+
+	return timeEntrie
+}
+
+func (mock *Client) DeleteTimeEntrie(id int) {
+	mock.record("DeleteTimeEntrie", id)
+	if mock.DeleteTimeEntrieFunc != nil {
+		mock.DeleteTimeEntrieFunc(id)
+	}
+}
+
+func (mock *Client) GetListTimeEntrie(filter ...string) []redmineclient.RdTimeEntrieData {
+	mock.record("GetListTimeEntrie", filter)
+	if mock.GetListTimeEntrieFunc != nil {
+		return mock.GetListTimeEntrieFunc(filter...)
+	}
+
+	return []redmineclient.RdTimeEntrieData{}
+}
+
+func (mock *Client) GetListTimeEntrieByProject(projectID int, filter ...string) []redmineclient.RdTimeEntrieData {
+	mock.record("GetListTimeEntrieByProject", projectID, filter)
+	if mock.GetListTimeEntrieByProjectFunc != nil {
+		return mock.GetListTimeEntrieByProjectFunc(projectID, filter...)
+	}
+
+	return []redmineclient.RdTimeEntrieData{}
+}
+
+func (mock *Client) GetListTimeEntrieByProjectCode(projectCode string, filter ...string) []redmineclient.RdTimeEntrieData {
+	mock.record("GetListTimeEntrieByProjectCode", projectCode, filter)
+	if mock.GetListTimeEntrieByProjectCodeFunc != nil {
+		return mock.GetListTimeEntrieByProjectCodeFunc(projectCode, filter...)
+	}
+
+	return []redmineclient.RdTimeEntrieData{}
+}
+
+func (mock *Client) GetWikiPage(url string) *redmineclient.RdWikiPage {
+	mock.record("GetWikiPage", url)
+	if mock.GetWikiPageFunc != nil {
+		return mock.GetWikiPageFunc(url)
+	}
+
+	return &redmineclient.RdWikiPage{}
+}
+
+func (mock *Client) CreateWikiPage(wikiPage *redmineclient.RdWikiPage, url string) *redmineclient.RdWikiPage {
+	mock.record("CreateWikiPage", wikiPage, url)
+	if mock.CreateWikiPageFunc != nil {
+		return mock.CreateWikiPageFunc(wikiPage, url)
+	}
+
+	return wikiPage
+}
+
+func (mock *Client) UpdateWikiPage(wikiPage *redmineclient.RdWikiPage, url string) *redmineclient.RdWikiPage {
+	mock.record("UpdateWikiPage", wikiPage, url)
+	if mock.UpdateWikiPageFunc != nil {
+		return mock.UpdateWikiPageFunc(wikiPage, url)
+	}
+
+	return wikiPage
+}
+
+func (mock *Client) DeleteWikiPage(project interface{}, title string) {
+	mock.record("DeleteWikiPage", project, title)
+	if mock.DeleteWikiPageFunc != nil {
+		mock.DeleteWikiPageFunc(project, title)
+	}
+}
+
+func (mock *Client) GetAttachment(id int) *redmineclient.RdAttachment {
+	mock.record("GetAttachment", id)
+	if mock.GetAttachmentFunc != nil {
+		return mock.GetAttachmentFunc(id)
+	}
+
+	return &redmineclient.RdAttachment{}
+}
+
+func (mock *Client) GetListFile(projectID int) []redmineclient.RdFileData {
+	mock.record("GetListFile", projectID)
+	if mock.GetListFileFunc != nil {
+		return mock.GetListFileFunc(projectID)
+	}
+
+	return []redmineclient.RdFileData{}
+}
+
+func (mock *Client) GetListFileByProjectCode(projectCode string) []redmineclient.RdFileData {
+	mock.record("GetListFileByProjectCode", projectCode)
+	if mock.GetListFileByProjectCodeFunc != nil {
+		return mock.GetListFileByProjectCodeFunc(projectCode)
+	}
+
+	return []redmineclient.RdFileData{}
+}
+
+func (mock *Client) Search(query string, filter ...string) []redmineclient.RdSearchResult {
+	mock.record("Search", query, filter)
+	if mock.SearchFunc != nil {
+		return mock.SearchFunc(query, filter...)
+	}
+
+	return []redmineclient.RdSearchResult{}
+}
+
+func (mock *Client) SearchByProject(projectID int, query string, filter ...string) []redmineclient.RdSearchResult {
+	mock.record("SearchByProject", projectID, query, filter)
+	if mock.SearchByProjectFunc != nil {
+		return mock.SearchByProjectFunc(projectID, query, filter...)
+	}
+
+	return []redmineclient.RdSearchResult{}
+}
+
+func (mock *Client) SearchByProjectCode(projectCode string, query string, filter ...string) []redmineclient.RdSearchResult {
+	mock.record("SearchByProjectCode", projectCode, query, filter)
+	if mock.SearchByProjectCodeFunc != nil {
+		return mock.SearchByProjectCodeFunc(projectCode, query, filter...)
+	}
+
+	return []redmineclient.RdSearchResult{}
+}
+
+func (mock *Client) GetListStatusIssue() []redmineclient.RdIssueStatus {
+	mock.record("GetListStatusIssue")
+	if mock.GetListStatusIssueFunc != nil {
+		return mock.GetListStatusIssueFunc()
+	}
+
+	return []redmineclient.RdIssueStatus{}
+}
+
+func (mock *Client) GetListTracker() []redmineclient.RdTracker {
+	mock.record("GetListTracker")
+	if mock.GetListTrackerFunc != nil {
+		return mock.GetListTrackerFunc()
+	}
+
+	return []redmineclient.RdTracker{}
+}
+
+func (mock *Client) GetListEnumeration(listName string) []redmineclient.RdEnumeration {
+	mock.record("GetListEnumeration", listName)
+	if mock.GetListEnumerationFunc != nil {
+		return mock.GetListEnumerationFunc(listName)
+	}
+
+	return []redmineclient.RdEnumeration{}
+}
+
+func (mock *Client) GetRole(id int) *redmineclient.RdRole {
+	mock.record("GetRole", id)
+	if mock.GetRoleFunc != nil {
+		return mock.GetRoleFunc(id)
+	}
+
+	return &redmineclient.RdRole{}
+}
+
+func (mock *Client) GetListRole() []redmineclient.RdRole {
+	mock.record("GetListRole")
+	if mock.GetListRoleFunc != nil {
+		return mock.GetListRoleFunc()
+	}
+
+	return []redmineclient.RdRole{}
+}
+
+func (mock *Client) GetListCustomField() []redmineclient.RdCustomField {
+	mock.record("GetListCustomField")
+	if mock.GetListCustomFieldFunc != nil {
+		return mock.GetListCustomFieldFunc()
+	}
+
+	return []redmineclient.RdCustomField{}
+}
+
+func (mock *Client) GetListQueries() []redmineclient.RdQuery {
+	mock.record("GetListQueries")
+	if mock.GetListQueriesFunc != nil {
+		return mock.GetListQueriesFunc()
+	}
+
+	return []redmineclient.RdQuery{}
+}
+
+// issueData ответ UpdateIssue по умолчанию: поля переданной задачи
+func issueData(issue *redmineclient.RdIssue) *redmineclient.RdIssueData {
+	data := &redmineclient.RdIssueData{
+		ID:             issue.ID,
+		Project:        redmineclient.RdLinkObject{ID: issue.Project},
+		Tracker:        redmineclient.RdLinkObject{ID: issue.Tracker},
+		Status:         redmineclient.RdLinkObject{ID: issue.Status},
+		Priority:       redmineclient.RdLinkObject{ID: issue.Priority},
+		AssignedTo:     redmineclient.RdLinkObject{ID: issue.AssignedTo},
+		Category:       redmineclient.RdLinkObject{ID: issue.Category},
+		FixedVersion:   redmineclient.RdLinkObject{ID: issue.FixedVersion},
+		Parent:         redmineclient.RdLinkObject{ID: issue.Parent},
+		Subject:        issue.Subject,
+		Description:    issue.Description,
+		DoneRatio:      issue.DoneRatio,
+		IsPrivate:      issue.IsPrivate,
+		EstimatedHours: issue.EstimatedHours,
+		CustomFields:   issue.CustomFields,
+	}
+	if !issue.StartDate.IsZero() {
+		data.StartDate = issue.StartDate.Format(redmineclient.DateFormat)
+	}
+	if !issue.DueDate.IsZero() {
+		data.DueDate = issue.DueDate.Format(redmineclient.DateFormat)
+	}
+
+	return data
+}
+
+// issueCategoryData ответ UpdateIssueCategory по умолчанию: поля переданной категории
+func issueCategoryData(issueCategory *redmineclient.RdIssueCategory) *redmineclient.RdIssueCategoryData {
+	return &redmineclient.RdIssueCategoryData{
+		ID:         issueCategory.ID,
+		Project:    redmineclient.RdLinkObject{ID: issueCategory.Project},
+		Name:       issueCategory.Name,
+		AssignedTo: redmineclient.RdLinkObject{ID: issueCategory.AssignedTo},
+	}
+}
@@ -0,0 +1,88 @@
+package redminemock_test
+
+import (
+	"reflect"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminemock"
+)
+
+// closeIssue код, зависящий только от интерфейса redmineclient.Client
+func closeIssue(client redmineclient.Client, id, statusID int) *redmineclient.RdIssueData {
+	issue := client.GetIssue(id).ToIssue()
+	issue.Status = statusID
+	issue.Notes = "closed automatically"
+
+	return client.UpdateIssue(issue)
+}
+
+func TestClientThroughInterface(t *testing.T) {
+	mock := &redminemock.Client{
+		GetIssueFunc: func(id int) *redmineclient.RdIssueData {
+			return &redmineclient.RdIssueData{ID: id, Subject: "Test", Status: redmineclient.RdLinkObject{ID: 1}}
+		},
+	}
+
+	updated := closeIssue(mock, 7, 5)
+	if updated.ID != 7 || updated.Subject != "Test" || updated.Status.ID != 5 {
+		t.Errorf("updated issue %+v", updated)
+	}
+
+	calls := mock.Calls("")
+	if len(calls) != 2 || calls[0].Method != "GetIssue" || calls[1].Method != "UpdateIssue" {
+		t.Fatalf("calls %+v", calls)
+	}
+	if !reflect.DeepEqual(calls[0].Args, []interface{}{7}) {
+		t.Errorf("GetIssue args %v", calls[0].Args)
+	}
+	if sent := calls[1].Args[0].(*redmineclient.RdIssue); sent.Status != 5 || sent.Notes != "closed automatically" {
+		t.Errorf("UpdateIssue got %+v", sent)
+	}
+
+	mock.Reset()
+	if calls := mock.Calls(""); len(calls) != 0 {
+		t.Errorf("calls after Reset %+v", calls)
+	}
+}
+
+func TestClientEchoesCreatedEntities(t *testing.T) {
+	var client redmineclient.Client = &redminemock.Client{}
+
+	issue := &redmineclient.RdIssue{Project: 1, Subject: "new"}
+	if created := client.CreateIssue(issue); created != issue {
+		t.Errorf("CreateIssue = %+v, want input", created)
+	}
+	if created, isNew, err := client.CreateIssueIdempotent(issue, "key", nil); created != issue || !isNew || err != nil {
+		t.Errorf("CreateIssueIdempotent = %+v, %v, %v", created, isNew, err)
+	}
+	project := &redmineclient.RdProject{Name: "Demo", Identifier: "demo"}
+	if created := client.CreateProject(project); created != project {
+		t.Errorf("CreateProject = %+v, want input", created)
+	}
+	category := &redmineclient.RdIssueCategory{ID: 3, Project: 1, Name: "UI"}
+	if updated := client.UpdateIssueCategory(category); updated.ID != 3 || updated.Name != "UI" || updated.Project.ID != 1 {
+		t.Errorf("UpdateIssueCategory = %+v", updated)
+	}
+	// методы чтения без функции возвращают пустое значение
+	if got := client.GetIssue(1); got.ID != 0 {
+		t.Errorf("GetIssue = %+v, want empty", got)
+	}
+	if got := client.GetListIssue(); got == nil || len(got) != 0 {
+		t.Errorf("GetListIssue = %v, want empty slice", got)
+	}
+}
+
+func TestClientFuncOverridesEcho(t *testing.T) {
+	mock := &redminemock.Client{
+		CreateIssueFunc: func(issue *redmineclient.RdIssue) *redmineclient.RdIssue {
+			created := *issue
+			created.ID = 42
+			return &created
+		},
+	}
+
+	if created := mock.CreateIssue(&redmineclient.RdIssue{Subject: "new"}); created.ID != 42 || created.Subject != "new" {
+		t.Errorf("CreateIssue = %+v", created)
+	}
+}
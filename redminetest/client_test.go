@@ -0,0 +1,406 @@
+package redminetest_test
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+// newClient фейковый сервер и клиент к нему, любая ошибка запроса проваливает тест
+func newClient(t *testing.T) (*redminetest.Server, *redmineclient.ApiRedmineClient) {
+	t.Helper()
+	srv := redminetest.NewServer()
+	t.Cleanup(srv.Close)
+
+	return srv, srv.Client(redmineclient.WithErrorHandler(func(err error) {
+		t.Errorf("request failed: %v", err)
+	}))
+}
+
+// createProject проект demo для тестов
+func createProject(t *testing.T, client *redmineclient.ApiRedmineClient) *redmineclient.RdProject {
+	t.Helper()
+	project := client.CreateProject(&redmineclient.RdProject{Name: "Demo", Identifier: "demo"})
+	if project.ID == 0 {
+		t.Fatal("project was not created")
+	}
+
+	return project
+}
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse(redmineclient.DateFormat, value)
+	return parsed
+}
+
+func TestIssueWithoutOptionalFields(t *testing.T) {
+	_, client := newClient(t)
+	project := createProject(t, client)
+
+	created := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "minimal"})
+	issue := client.GetIssue(created.ID)
+
+	if issue.ID != created.ID || issue.Subject != "minimal" {
+		t.Fatalf("GetIssue = #%d %q, want #%d %q", issue.ID, issue.Subject, created.ID, "minimal")
+	}
+	if issue.Parent.ID != 0 || issue.AssignedTo.ID != 0 || issue.Category.ID != 0 || issue.FixedVersion.ID != 0 {
+		t.Errorf("missing links decoded as %+v %+v %+v %+v", issue.Parent, issue.AssignedTo, issue.Category, issue.FixedVersion)
+	}
+	if issue.DueDate != "" || !issue.GetDueDate().IsZero() {
+		t.Errorf("due date = %q, want empty", issue.DueDate)
+	}
+	if issue.EstimatedHours != nil || !issue.ClosedOn.IsZero() {
+		t.Errorf("estimated hours = %v, closed on = %v, want empty", issue.EstimatedHours, issue.ClosedOn)
+	}
+	if issue.Status.ID != redminetest.StatusNew || issue.Tracker.ID != redminetest.TrackerBug {
+		t.Errorf("status %d tracker %d, want defaults", issue.Status.ID, issue.Tracker.ID)
+	}
+}
+
+func TestIssueFieldsRoundTrip(t *testing.T) {
+	_, client := newClient(t)
+	project := createProject(t, client)
+
+	parent := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "parent"})
+	child := client.CreateIssue(&redmineclient.RdIssue{
+		Project:        project.ID,
+		Subject:        "child",
+		Parent:         parent.ID,
+		Priority:       redminetest.PriorityHigh,
+		StartDate:      date("2024-03-04"),
+		DueDate:        date("2024-03-08"),
+		DoneRatio:      redmineclient.Int(30),
+		EstimatedHours: redmineclient.Float(6.5),
+		IsPrivate:      redmineclient.Bool(true),
+	})
+
+	issue := client.GetIssue(child.ID)
+	if issue.Parent.ID != parent.ID || issue.Priority.ID != redminetest.PriorityHigh {
+		t.Errorf("parent %d priority %d, want %d %d", issue.Parent.ID, issue.Priority.ID, parent.ID, redminetest.PriorityHigh)
+	}
+	if !issue.GetStartDate().Equal(date("2024-03-04")) || !issue.GetDueDate().Equal(date("2024-03-08")) {
+		t.Errorf("dates %q..%q, want 2024-03-04..2024-03-08", issue.StartDate, issue.DueDate)
+	}
+	if *issue.DoneRatio != 30 || *issue.EstimatedHours != 6.5 || !*issue.IsPrivate {
+		t.Errorf("done %d estimate %v private %v", *issue.DoneRatio, *issue.EstimatedHours, *issue.IsPrivate)
+	}
+	if total := client.GetIssue(parent.ID).TotalEstimatedHours; total != 6.5 {
+		t.Errorf("parent total estimate = %v, want 6.5", total)
+	}
+}
+
+func TestIssueNullEstimateRoundTrip(t *testing.T) {
+	_, client := newClient(t)
+	project := createProject(t, client)
+	created := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "without estimate"})
+
+	issue := client.GetIssue(created.ID).ToIssue()
+	if issue.EstimatedHours != nil {
+		t.Fatalf("ToIssue estimated hours = %v, want nil", *issue.EstimatedHours)
+	}
+	issue.Subject = "renamed"
+	updated := client.UpdateIssue(issue)
+	if updated.Subject != "renamed" || updated.EstimatedHours != nil {
+		t.Errorf("after update: subject %q estimate %v, want null estimate", updated.Subject, updated.EstimatedHours)
+	}
+}
+
+func TestUpdateIssueSendsZeroValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		update redmineclient.RdIssue
+		check  func(issue *redmineclient.RdIssueData) bool
+	}{
+		{"done ratio", redmineclient.RdIssue{DoneRatio: redmineclient.Int(0)}, func(issue *redmineclient.RdIssueData) bool { return *issue.DoneRatio == 0 }},
+		{"private", redmineclient.RdIssue{IsPrivate: redmineclient.Bool(false)}, func(issue *redmineclient.RdIssueData) bool { return !*issue.IsPrivate }},
+		{"estimate", redmineclient.RdIssue{EstimatedHours: redmineclient.Float(0)}, func(issue *redmineclient.RdIssueData) bool {
+			return issue.EstimatedHours != nil && *issue.EstimatedHours == 0
+		}},
+		{"untouched", redmineclient.RdIssue{Subject: "renamed"}, func(issue *redmineclient.RdIssueData) bool {
+			return *issue.DoneRatio == 50 && *issue.IsPrivate && *issue.EstimatedHours == 2
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, client := newClient(t)
+			project := createProject(t, client)
+			created := client.CreateIssue(&redmineclient.RdIssue{
+				Project:        project.ID,
+				Subject:        "issue",
+				DoneRatio:      redmineclient.Int(50),
+				IsPrivate:      redmineclient.Bool(true),
+				EstimatedHours: redmineclient.Float(2),
+			})
+
+			update := test.update
+			update.ID = created.ID
+			updated := client.UpdateIssue(&update)
+			if updated.ID != created.ID {
+				t.Fatalf("UpdateIssue returned #%d, want #%d", updated.ID, created.ID)
+			}
+			if !test.check(updated) {
+				t.Errorf("after update: done %v private %v estimate %v", updated.DoneRatio, updated.IsPrivate, updated.EstimatedHours)
+			}
+		})
+	}
+}
+
+func TestListIssueFilters(t *testing.T) {
+	_, client := newClient(t)
+	project := createProject(t, client)
+	parent := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "release"})
+	client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "write docs", Parent: parent.ID})
+	client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "fix login", Status: redminetest.StatusClosed})
+	client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "fix logout", Tracker: redminetest.TrackerFeature})
+
+	tests := []struct {
+		name   string
+		filter []string
+		want   []string
+	}{
+		{"open by default", []string{"sort=id"}, []string{"release", "write docs", "fix logout"}},
+		{"closed", []string{"status_id=closed"}, []string{"fix login"}},
+		{"any status", []string{"status_id=*", "sort=id"}, []string{"release", "write docs", "fix login", "fix logout"}},
+		{"subject", []string{"status_id=*", "subject=~fix", "sort=id"}, []string{"fix login", "fix logout"}},
+		{"tracker", []string{"tracker_id=" + strconv.Itoa(redminetest.TrackerFeature)}, []string{"fix logout"}},
+		{"parent", []string{"parent_id=" + strconv.Itoa(parent.ID)}, []string{"write docs"}},
+		{"project", []string{"project_id=" + strconv.Itoa(project.ID), "subject=~docs"}, []string{"write docs"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := []string{}
+			for _, issue := range client.GetListIssue(test.filter...) {
+				got = append(got, issue.Subject)
+			}
+			if !equalStrings(got, test.want) {
+				t.Errorf("GetListIssue(%v) = %q, want %q", test.filter, got, test.want)
+			}
+		})
+	}
+}
+
+func TestResourceAllReadsEveryPage(t *testing.T) {
+	_, client := newClient(t)
+	project := createProject(t, client)
+	for i := 0; i < 130; i++ {
+		client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "issue " + strconv.Itoa(i)})
+	}
+
+	list := redmineclient.Resource[redmineclient.RdIssueData]{ListKey: "issues", CollectionPath: "/issues.json"}
+	if got := len(list.List(client)); got != 25 {
+		t.Errorf("List returned %d issues, want the default page of 25", got)
+	}
+	if got := len(list.All(client, "sort=id")); got != 130 {
+		t.Errorf("All returned %d issues, want 130", got)
+	}
+	page := list.Page(client, 120, 100)
+	if page.TotalCount != 130 || len(page.Items) != 10 || page.Offset != 120 {
+		t.Errorf("Page(120, 100) = %d items, total %d, offset %d", len(page.Items), page.TotalCount, page.Offset)
+	}
+}
+
+func TestResourceTryReturnsErrors(t *testing.T) {
+	srv := redminetest.NewServer()
+	defer srv.Close()
+	var handled []error
+	client := srv.Client(redmineclient.WithErrorHandler(func(err error) { handled = append(handled, err) }))
+	project := createProject(t, client)
+
+	issues := redmineclient.Resource[redmineclient.RdIssue]{Key: "issue", ListKey: "issues", Path: "/issues/%v.json", CollectionPath: "/issues.json"}
+	created := &redmineclient.RdIssue{Project: project.ID, Subject: "resource"}
+	if err := issues.TryCreate(client, created); err != nil || created.ID == 0 {
+		t.Fatalf("TryCreate: issue #%d, err %v", created.ID, err)
+	}
+	if got, err := issues.TryGet(client, created.ID); err != nil || got.Subject != "resource" {
+		t.Errorf("TryGet(%d) = %+v, err %v", created.ID, got, err)
+	}
+
+	// ответ без ListKey считается ошибкой
+	wrongList := redmineclient.Resource[redmineclient.RdIssue]{ListKey: "tasks", CollectionPath: "/issues.json"}
+	tests := []struct {
+		name   string
+		call   func() error
+		status int
+	}{
+		{"TryCreate", func() error { return issues.TryCreate(client, &redmineclient.RdIssue{Project: project.ID}) }, http.StatusUnprocessableEntity},
+		{"TryGet", func() error { _, err := issues.TryGet(client, 404); return err }, http.StatusNotFound},
+		{"TryUpdate", func() error { return issues.TryUpdate(client, 404, &redmineclient.RdIssue{Subject: "x"}) }, http.StatusNotFound},
+		{"TryDelete", func() error { return issues.TryDelete(client, 404) }, http.StatusNotFound},
+		{"TryList", func() error { _, err := wrongList.TryList(client); return err }, 0},
+		{"TryPage", func() error { _, err := wrongList.TryPage(client, 0, 10); return err }, 0},
+		{"TryAll", func() error { _, err := wrongList.TryAll(client); return err }, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handled = nil
+			err := test.call()
+			if err == nil {
+				t.Fatal("no error returned")
+			}
+			apiErr := &redmineclient.APIError{}
+			if test.status != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != test.status) {
+				t.Errorf("error = %v, want status %d", err, test.status)
+			}
+			if len(handled) != 1 || handled[0] != err {
+				t.Errorf("error handler got %v, want %v", handled, err)
+			}
+		})
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	srv := redminetest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	project := createProject(t, client)
+
+	tests := []struct {
+		name    string
+		call    func(client *redmineclient.ApiRedmineClient)
+		status  int
+		message string
+	}{
+		{"issue without subject", func(client *redmineclient.ApiRedmineClient) {
+			client.CreateIssue(&redmineclient.RdIssue{Project: project.ID})
+		}, http.StatusUnprocessableEntity, "Subject cannot be blank"},
+		{"due date before start", func(client *redmineclient.ApiRedmineClient) {
+			client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "x", StartDate: date("2024-03-08"), DueDate: date("2024-03-04")})
+		}, http.StatusUnprocessableEntity, "Due date must be greater than start date"},
+		{"project without name", func(client *redmineclient.ApiRedmineClient) {
+			client.CreateProject(&redmineclient.RdProject{Identifier: "nameless"})
+		}, http.StatusUnprocessableEntity, "Name cannot be blank"},
+		{"time entry without hours", func(client *redmineclient.ApiRedmineClient) {
+			client.CreateTimeEntrie(&redmineclient.RdTimeEntrie{Project: project.ID, SpentOn: date("2024-03-04")})
+		}, http.StatusUnprocessableEntity, "Hours cannot be blank"},
+		{"missing issue", func(client *redmineclient.ApiRedmineClient) {
+			client.GetIssue(404)
+		}, http.StatusNotFound, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err error
+			test.call(client.With(redmineclient.CaptureError(&err)))
+
+			apiErr := &redmineclient.APIError{}
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *APIError", err)
+			}
+			if apiErr.StatusCode != test.status {
+				t.Errorf("status = %d, want %d", apiErr.StatusCode, test.status)
+			}
+			if test.message != "" && !containsString(apiErr.Errors, test.message) {
+				t.Errorf("errors = %q, want %q", apiErr.Errors, test.message)
+			}
+		})
+	}
+}
+
+func TestVersionDueDate(t *testing.T) {
+	_, client := newClient(t)
+	project := createProject(t, client)
+
+	undated := client.CreateVersion(&redmineclient.RdVersion{Project: project.ID, Name: "someday"})
+	dated := client.CreateVersion(&redmineclient.RdVersion{Project: project.ID, Name: "1.0", DueDate: date("2024-06-01")})
+
+	if got := client.GetVersion(undated.ID); got.Name != "someday" || !got.DueDate.IsZero() || got.Project != project.ID {
+		t.Errorf("undated version = %+v", got)
+	}
+	if got := client.GetVersion(dated.ID); !got.DueDate.Equal(date("2024-06-01")) {
+		t.Errorf("due date = %v, want 2024-06-01", got.DueDate)
+	}
+	if got := len(client.GetVersionList(project.ID)); got != 2 {
+		t.Errorf("GetVersionList returned %d versions, want 2", got)
+	}
+}
+
+func TestIssueCategoryUpdate(t *testing.T) {
+	_, client := newClient(t)
+	project := createProject(t, client)
+
+	category := client.CreateIssueCategory(&redmineclient.RdIssueCategory{Project: project.ID, Name: "Backend"})
+	if category.ID == 0 || category.AssignedTo != 0 {
+		t.Fatalf("created category = %+v", category)
+	}
+	updated := client.UpdateIssueCategory(&redmineclient.RdIssueCategory{ID: category.ID, Name: "API"})
+	if updated.ID != category.ID || updated.Name != "API" || updated.Project.ID != project.ID {
+		t.Errorf("UpdateIssueCategory = %+v", updated)
+	}
+}
+
+func TestTimeEntries(t *testing.T) {
+	_, client := newClient(t)
+	project := createProject(t, client)
+	issue := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "work"})
+
+	entry := client.CreateTimeEntrie(&redmineclient.RdTimeEntrie{
+		Issue:    issue.ID,
+		Hours:    1.5,
+		SpentOn:  date("2024-03-04"),
+		Activity: redminetest.ActivityDesign,
+		Comments: "sketch",
+	})
+	client.CreateTimeEntrie(&redmineclient.RdTimeEntrie{Project: project.ID, Hours: 2, SpentOn: date("2024-03-05")})
+
+	got := client.GetTimeEntrie(entry.ID)
+	if got.Issue != issue.ID || got.Project != project.ID || got.Hours != 1.5 || !got.SpentOn.Equal(date("2024-03-04")) {
+		t.Errorf("GetTimeEntrie = %+v", got)
+	}
+	if got := len(client.GetListTimeEntrie("issue_id=" + strconv.Itoa(issue.ID))); got != 1 {
+		t.Errorf("entries of issue = %d, want 1", got)
+	}
+	if got := len(client.GetListTimeEntrieByProject(project.ID)); got != 2 {
+		t.Errorf("entries of project = %d, want 2", got)
+	}
+	if spent := client.GetIssue(issue.ID).SpentHours; spent != 1.5 {
+		t.Errorf("issue spent hours = %v, want 1.5", spent)
+	}
+}
+
+func TestWikiPageLifecycle(t *testing.T) {
+	srv, client := newClient(t)
+	createProject(t, client)
+
+	client.CreateWikiPage(&redmineclient.RdWikiPage{Text: "h1. Start", Comments: "first"}, "/projects/demo/wiki/Start")
+	client.UpdateWikiPage(&redmineclient.RdWikiPage{Text: "h1. Start again", Version: 1}, "/projects/demo/wiki/Start")
+
+	page := client.GetWikiPage("/projects/demo/wiki/Start.json")
+	if page.Title != "Start" || page.Text != "h1. Start again" || page.Version != 2 {
+		t.Errorf("GetWikiPage = %+v", page)
+	}
+
+	client.DeleteWikiPage("demo", "Start")
+	var err error
+	srv.Client().With(redmineclient.CaptureError(&err)).GetWikiPage("/projects/demo/wiki/Start.json")
+	apiErr := &redmineclient.APIError{}
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("deleted page: error = %v, want 404", err)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,290 @@
+package redminetest
+
+import (
+	"net/http"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+// Статусы задач по умолчанию
+const (
+	StatusNew        = 1
+	StatusInProgress = 2
+	StatusResolved   = 3
+	StatusFeedback   = 4
+	StatusClosed     = 5
+	StatusRejected   = 6
+)
+
+// Трекеры по умолчанию
+const (
+	TrackerBug     = 1
+	TrackerFeature = 2
+	TrackerSupport = 3
+)
+
+// Приоритеты задач по умолчанию
+const (
+	PriorityLow       = 1
+	PriorityNormal    = 2
+	PriorityHigh      = 3
+	PriorityUrgent    = 4
+	PriorityImmediate = 5
+)
+
+// Виды деятельности для трудозатрат по умолчанию
+const (
+	ActivityDesign      = 8
+	ActivityDevelopment = 9
+)
+
+// Роли по умолчанию
+const (
+	RoleManager   = 3
+	RoleDeveloper = 4
+	RoleReporter  = 5
+)
+
+type statusData struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	IsClosed bool   `json:"is_closed"`
+}
+
+type trackerData struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	DefaultStatus *linkData `json:"default_status"`
+}
+
+type enumerationData struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	IsDefault bool   `json:"is_default"`
+}
+
+type roleData struct {
+	ID                    int      `json:"id"`
+	Name                  string   `json:"name"`
+	Assignable            bool     `json:"assignable"`
+	IssuesVisibility      string   `json:"issues_visibility"`
+	TimeEntriesVisibility string   `json:"time_entries_visibility"`
+	UsersVisibility       string   `json:"users_visibility"`
+	Permissions           []string `json:"permissions"`
+}
+
+type customFieldData struct {
+	ID             int      `json:"id"`
+	Name           string   `json:"name"`
+	CustomizedType string   `json:"customized_type"`
+	FieldFormat    string   `json:"field_format"`
+	Regexp         string   `json:"regexp"`
+	MinLength      *int     `json:"min_length"`
+	MaxLength      *int     `json:"max_length"`
+	IsRequired     bool     `json:"is_required"`
+	IsFilter       bool     `json:"is_filter"`
+	Searchable     bool     `json:"searchable"`
+	Multiple       bool     `json:"multiple"`
+	DefaultValue   *string  `json:"default_value"`
+	Visible        bool     `json:"visible"`
+	PossibleValues []string `json:"possible_values,omitempty"`
+}
+
+type queryData struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	IsPublic  bool   `json:"is_public"`
+	ProjectID *int   `json:"project_id"`
+}
+
+var statuses = []statusData{
+	{ID: StatusNew, Name: "New"},
+	{ID: StatusInProgress, Name: "In Progress"},
+	{ID: StatusResolved, Name: "Resolved"},
+	{ID: StatusFeedback, Name: "Feedback"},
+	{ID: StatusClosed, Name: "Closed", IsClosed: true},
+	{ID: StatusRejected, Name: "Rejected", IsClosed: true},
+}
+
+var trackers = []trackerData{
+	{ID: TrackerBug, Name: "Bug", DefaultStatus: link(StatusNew, "New")},
+	{ID: TrackerFeature, Name: "Feature", DefaultStatus: link(StatusNew, "New")},
+	{ID: TrackerSupport, Name: "Support", DefaultStatus: link(StatusNew, "New")},
+}
+
+var enumerations = map[string][]enumerationData{
+	"issue_priorities": {
+		{ID: PriorityLow, Name: "Low"},
+		{ID: PriorityNormal, Name: "Normal", IsDefault: true},
+		{ID: PriorityHigh, Name: "High"},
+		{ID: PriorityUrgent, Name: "Urgent"},
+		{ID: PriorityImmediate, Name: "Immediate"},
+	},
+	"time_entry_activities": {
+		{ID: ActivityDesign, Name: "Design"},
+		{ID: ActivityDevelopment, Name: "Development", IsDefault: true},
+	},
+	"document_categories": {
+		{ID: 6, Name: "User documentation"},
+		{ID: 7, Name: "Technical documentation"},
+	},
+}
+
+var roles = []roleData{
+	{
+		ID: RoleManager, Name: "Manager", Assignable: true,
+		IssuesVisibility: "all", TimeEntriesVisibility: "all", UsersVisibility: "all",
+		Permissions: []string{"add_issues", "edit_issues", "manage_members", "manage_versions", "log_time", "edit_wiki_pages"},
+	},
+	{
+		ID: RoleDeveloper, Name: "Developer", Assignable: true,
+		IssuesVisibility: "default", TimeEntriesVisibility: "all", UsersVisibility: "members_of_visible_projects",
+		Permissions: []string{"add_issues", "edit_issues", "log_time", "edit_wiki_pages"},
+	},
+	{
+		ID: RoleReporter, Name: "Reporter",
+		IssuesVisibility: "default", TimeEntriesVisibility: "all", UsersVisibility: "members_of_visible_projects",
+		Permissions: []string{"add_issues"},
+	},
+}
+
+func findStatus(id int) (statusData, bool) {
+	for _, status := range statuses {
+		if status.ID == id {
+			return status, true
+		}
+	}
+
+	return statusData{}, false
+}
+
+func findTracker(id int) (trackerData, bool) {
+	for _, tracker := range trackers {
+		if tracker.ID == id {
+			return tracker, true
+		}
+	}
+
+	return trackerData{}, false
+}
+
+func findRole(id int) (roleData, bool) {
+	for _, role := range roles {
+		if role.ID == id {
+			return role, true
+		}
+	}
+
+	return roleData{}, false
+}
+
+func findEnumeration(name string, id int) (enumerationData, bool) {
+	for _, enumeration := range enumerations[name] {
+		if enumeration.ID == id {
+			return enumeration, true
+		}
+	}
+
+	return enumerationData{}, false
+}
+
+func defaultEnumeration(name string) enumerationData {
+	for _, enumeration := range enumerations[name] {
+		if enumeration.IsDefault {
+			return enumeration
+		}
+	}
+
+	return enumerations[name][0]
+}
+
+func statusLink(id int) *linkData {
+	status, _ := findStatus(id)
+	return link(status.ID, status.Name)
+}
+
+func isClosedStatus(id int) bool {
+	status, _ := findStatus(id)
+	return status.IsClosed
+}
+
+func (s *Server) listStatuses(req *request) {
+	renderAll(req, "issue_statuses", statuses)
+}
+
+func (s *Server) listTrackers(req *request) {
+	renderAll(req, "trackers", trackers)
+}
+
+func (s *Server) listEnumeration(req *request) {
+	name := req.param("name")
+	values, ok := enumerations[name]
+	if !ok {
+		req.status(http.StatusNotFound)
+		return
+	}
+
+	renderAll(req, name, values)
+}
+
+func (s *Server) listRoles(req *request) {
+	list := []*linkData{}
+	for _, role := range roles {
+		list = append(list, link(role.ID, role.Name))
+	}
+
+	renderAll(req, "roles", list)
+}
+
+func (s *Server) getRole(req *request) {
+	role, ok := findRole(req.id("id"))
+	if !ok {
+		req.status(http.StatusNotFound)
+		return
+	}
+
+	req.render(http.StatusOK, map[string]interface{}{"role": role})
+}
+
+func (s *Server) listCustomFields(req *request) {
+	if !req.user.admin {
+		req.status(http.StatusForbidden)
+		return
+	}
+
+	fields := []customFieldData{}
+	for _, id := range sortedIDs(s.customFields) {
+		fields = append(fields, customFieldResponse(s.customFields[id]))
+	}
+
+	renderAll(req, "custom_fields", fields)
+}
+
+func (s *Server) listQueries(req *request) {
+	renderPage(req, "queries", []queryData{})
+}
+
+func customFieldResponse(field *redmineclient.RdCustomField) customFieldData {
+	data := customFieldData{
+		ID:             field.ID,
+		Name:           field.Name,
+		CustomizedType: field.CustomizedType,
+		FieldFormat:    field.FieldFormat,
+		Regexp:         field.Regexp,
+		IsRequired:     field.IsRequired,
+		IsFilter:       field.IsFilter,
+		Searchable:     field.Searchable,
+		Multiple:       field.Multiple,
+		DefaultValue:   stringOrNull(field.DefaultValue),
+		Visible:        field.Visible,
+		PossibleValues: field.PossibleValues,
+	}
+	if field.MinLength > 0 {
+		data.MinLength = &field.MinLength
+	}
+	if field.MaxLength > 0 {
+		data.MaxLength = &field.MaxLength
+	}
+
+	return data
+}
@@ -0,0 +1,1178 @@
+package redminetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+type issue struct {
+	id             int
+	projectID      int
+	trackerID      int
+	statusID       int
+	priorityID     int
+	authorID       int
+	assignedToID   int
+	categoryID     int
+	fixedVersionID int
+	parentID       int
+	subject        string
+	description    string
+	startDate      string
+	dueDate        string
+	doneRatio      int
+	isPrivate      bool
+	estimatedHours *float64
+	customFields   map[int]string
+	watcherIDs     []int
+	attachmentIDs  []int
+	journals       []journalData
+	createdOn      time.Time
+	updatedOn      time.Time
+	closedOn       time.Time
+}
+
+// issueData задача в ответе: отсутствующие ссылки не выводятся, пустые даты и оценки отдаются как null
+type issueData struct {
+	ID                  int               `json:"id"`
+	Project             *linkData         `json:"project"`
+	Tracker             *linkData         `json:"tracker"`
+	Status              *linkData         `json:"status"`
+	Priority            *linkData         `json:"priority"`
+	Author              *linkData         `json:"author"`
+	AssignedTo          *linkData         `json:"assigned_to,omitempty"`
+	Category            *linkData         `json:"category,omitempty"`
+	FixedVersion        *linkData         `json:"fixed_version,omitempty"`
+	Parent              *linkData         `json:"parent,omitempty"`
+	Subject             string            `json:"subject"`
+	Description         string            `json:"description"`
+	StartDate           *string           `json:"start_date"`
+	DueDate             *string           `json:"due_date"`
+	DoneRatio           int               `json:"done_ratio"`
+	IsPrivate           bool              `json:"is_private"`
+	EstimatedHours      *float64          `json:"estimated_hours"`
+	TotalEstimatedHours *float64          `json:"total_estimated_hours"`
+	SpentHours          float64           `json:"spent_hours"`
+	TotalSpentHours     float64           `json:"total_spent_hours"`
+	CustomFields        []customValueData `json:"custom_fields,omitempty"`
+	CreatedOn           time.Time         `json:"created_on"`
+	UpdatedOn           time.Time         `json:"updated_on"`
+	ClosedOn            *time.Time        `json:"closed_on"`
+	Journals            []journalData     `json:"journals,omitempty"`
+	Attachments         []attachmentData  `json:"attachments,omitempty"`
+	Relations           []relationData    `json:"relations,omitempty"`
+	Children            []childData       `json:"children,omitempty"`
+	Changesets          []changesetData   `json:"changesets,omitempty"`
+	Watchers            []*linkData       `json:"watchers,omitempty"`
+	AllowedStatuses     []statusData      `json:"allowed_statuses,omitempty"`
+}
+
+type customValueData struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+}
+
+type journalData struct {
+	ID           int                 `json:"id"`
+	User         *linkData           `json:"user"`
+	Notes        string              `json:"notes"`
+	CreatedOn    time.Time           `json:"created_on"`
+	PrivateNotes bool                `json:"private_notes"`
+	Details      []journalDetailData `json:"details"`
+}
+
+type journalDetailData struct {
+	Property string  `json:"property"`
+	Name     string  `json:"name"`
+	OldValue *string `json:"old_value"`
+	NewValue *string `json:"new_value"`
+}
+
+type childData struct {
+	ID       int         `json:"id"`
+	Tracker  *linkData   `json:"tracker"`
+	Subject  string      `json:"subject"`
+	Children []childData `json:"children,omitempty"`
+}
+
+type changesetData struct {
+	Revision    string    `json:"revision"`
+	User        *linkData `json:"user,omitempty"`
+	Comments    string    `json:"comments"`
+	CommittedOn time.Time `json:"committed_on"`
+}
+
+type uploadParams struct {
+	Token       string `json:"token"`
+	Filename    string `json:"filename"`
+	Description string `json:"description"`
+	ContentType string `json:"content_type"`
+}
+
+type issueParams struct {
+	ProjectID      *int               `json:"project_id"`
+	TrackerID      *int               `json:"tracker_id"`
+	StatusID       *int               `json:"status_id"`
+	PriorityID     *int               `json:"priority_id"`
+	AssignedToID   nullable[int]      `json:"assigned_to_id"`
+	CategoryID     nullable[int]      `json:"category_id"`
+	FixedVersionID nullable[int]      `json:"fixed_version_id"`
+	ParentIssueID  nullable[int]      `json:"parent_issue_id"`
+	Subject        *string            `json:"subject"`
+	Description    *string            `json:"description"`
+	StartDate      nullable[string]   `json:"start_date"`
+	DueDate        nullable[string]   `json:"due_date"`
+	DoneRatio      *int               `json:"done_ratio"`
+	IsPrivate      *bool              `json:"is_private"`
+	EstimatedHours nullable[*float64] `json:"estimated_hours"`
+	Notes          string             `json:"notes"`
+	PrivateNotes   bool               `json:"private_notes"`
+	WatcherUserIDs []int              `json:"watcher_user_ids"`
+	CustomFields   []customValueData  `json:"custom_fields"`
+	Uploads        []uploadParams     `json:"uploads"`
+}
+
+// nullable поле запроса, которое redmine очищает значением null или ""
+type nullable[T any] struct {
+	set   bool
+	value T
+}
+
+func (field *nullable[T]) UnmarshalJSON(data []byte) error {
+	field.set = true
+	if string(data) == "null" || string(data) == `""` {
+		var zero T
+		field.value = zero
+		return nil
+	}
+
+	return json.Unmarshal(data, &field.value)
+}
+
+func (field nullable[T]) apply(value *T) {
+	if field.set {
+		*value = field.value
+	}
+}
+
+func setInt(field *int, value *int) {
+	if value != nil {
+		*field = *value
+	}
+}
+
+func (params *issueParams) apply(i *issue) {
+	setInt(&i.projectID, params.ProjectID)
+	setInt(&i.trackerID, params.TrackerID)
+	setInt(&i.statusID, params.StatusID)
+	setInt(&i.priorityID, params.PriorityID)
+	params.AssignedToID.apply(&i.assignedToID)
+	params.CategoryID.apply(&i.categoryID)
+	params.FixedVersionID.apply(&i.fixedVersionID)
+	params.ParentIssueID.apply(&i.parentID)
+	setString(&i.subject, params.Subject)
+	setString(&i.description, params.Description)
+	params.StartDate.apply(&i.startDate)
+	params.DueDate.apply(&i.dueDate)
+	setInt(&i.doneRatio, params.DoneRatio)
+	if params.IsPrivate != nil {
+		i.isPrivate = *params.IsPrivate
+	}
+	params.EstimatedHours.apply(&i.estimatedHours)
+
+	customFields := map[int]string{}
+	for id, value := range i.customFields {
+		customFields[id] = value
+	}
+	for _, field := range params.CustomFields {
+		customFields[field.ID] = field.Value
+	}
+	i.customFields = customFields
+}
+
+func (s *Server) issueCustomFields() []*redmineclient.RdCustomField {
+	fields := []*redmineclient.RdCustomField{}
+	for _, id := range sortedIDs(s.customFields) {
+		if field := s.customFields[id]; field.CustomizedType == "issue" {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+func validateCustomValue(errs *validation, field *redmineclient.RdCustomField, value string) {
+	if value == "" {
+		if field.IsRequired {
+			errs.add("%s cannot be blank", field.Name)
+		}
+		return
+	}
+
+	switch field.FieldFormat {
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			errs.add("%s is not a number", field.Name)
+		}
+	case "float":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			errs.add("%s is not a number", field.Name)
+		}
+	case "date":
+		if !validDate(value) {
+			errs.add("%s is not a valid date", field.Name)
+		}
+	case "bool":
+		if value != "0" && value != "1" {
+			errs.add("%s is not included in the list", field.Name)
+		}
+	}
+	if len(field.PossibleValues) > 0 {
+		found := false
+		for _, possible := range field.PossibleValues {
+			found = found || possible == value
+		}
+		if !found {
+			errs.add("%s is not included in the list", field.Name)
+		}
+	}
+	if field.MinLength > 0 && len(value) < field.MinLength {
+		errs.add("%s is too short (minimum is %d characters)", field.Name, field.MinLength)
+	}
+	if field.MaxLength > 0 && len(value) > field.MaxLength {
+		errs.add("%s is too long (maximum is %d characters)", field.Name, field.MaxLength)
+	}
+	if field.Regexp != "" {
+		if pattern, err := regexp.Compile(field.Regexp); err == nil && !pattern.MatchString(value) {
+			errs.add("%s is invalid", field.Name)
+		}
+	}
+}
+
+// isAssignable пользователь участник проекта с ролью, допускающей назначение задач
+func (s *Server) isAssignable(userID, projectID int) bool {
+	if u, ok := s.users[userID]; !ok || !u.active() {
+		return false
+	}
+	for _, m := range s.memberships {
+		if m.userID != userID || m.projectID != projectID {
+			continue
+		}
+		for _, roleID := range m.roleIDs {
+			if role, _ := findRole(roleID); role.Assignable {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isAncestor задача ancestorID является родителем issueID на любом уровне
+func (s *Server) isAncestor(ancestorID, issueID int) bool {
+	for i, ok := s.issues[issueID]; ok && i.parentID != 0; i, ok = s.issues[i.parentID] {
+		if i.parentID == ancestorID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) validateIssue(i *issue) validation {
+	errs := validation{}
+	if _, ok := s.projects[i.projectID]; !ok {
+		errs.add("Project cannot be blank")
+	}
+	if _, ok := findTracker(i.trackerID); !ok {
+		errs.add("Tracker cannot be blank")
+	}
+	if _, ok := findStatus(i.statusID); !ok {
+		errs.add("Status cannot be blank")
+	}
+	if _, ok := findEnumeration("issue_priorities", i.priorityID); !ok {
+		errs.add("Priority cannot be blank")
+	}
+	if i.subject == "" {
+		errs.add("Subject cannot be blank")
+	} else if len(i.subject) > 255 {
+		errs.add("Subject is too long (maximum is 255 characters)")
+	}
+	if i.assignedToID != 0 && !s.isAssignable(i.assignedToID, i.projectID) {
+		errs.add("Assignee is invalid")
+	}
+	if c, ok := s.categories[i.categoryID]; i.categoryID != 0 && (!ok || c.projectID != i.projectID) {
+		errs.add("Category is not included in the list")
+	}
+	if v, ok := s.versions[i.fixedVersionID]; i.fixedVersionID != 0 &&
+		(!ok || !s.versionAvailable(v, i.projectID) || v.status != "open") {
+		errs.add("Target version is not included in the list")
+	}
+	if i.parentID != 0 {
+		if _, ok := s.issues[i.parentID]; !ok || i.parentID == i.id || s.isAncestor(i.id, i.parentID) {
+			errs.add("Parent task is invalid")
+		}
+	}
+	if i.startDate != "" && !validDate(i.startDate) {
+		errs.add("Start date is not a valid date")
+	}
+	if i.dueDate != "" && !validDate(i.dueDate) {
+		errs.add("Due date is not a valid date")
+	}
+	if validDate(i.startDate) && validDate(i.dueDate) && i.dueDate < i.startDate {
+		errs.add("Due date must be greater than start date")
+	}
+	if i.doneRatio < 0 || i.doneRatio > 100 {
+		errs.add("%% Done is not included in the list")
+	}
+	if i.estimatedHours != nil && *i.estimatedHours < 0 {
+		errs.add("Estimated time is invalid")
+	}
+	for _, field := range s.issueCustomFields() {
+		validateCustomValue(&errs, field, i.customFields[field.ID])
+	}
+
+	return errs
+}
+
+func (s *Server) canViewIssue(u *user, i *issue) bool {
+	p, ok := s.projects[i.projectID]
+	if !ok || !s.canView(u, p) {
+		return false
+	}
+
+	return !i.isPrivate || u.admin || i.authorID == u.id || i.assignedToID == u.id || s.isMember(u.id, p.id)
+}
+
+func (s *Server) pathIssue(req *request) *issue {
+	i, ok := s.issues[req.id("id")]
+	if !ok {
+		req.status(http.StatusNotFound)
+		return nil
+	}
+	if !s.canViewIssue(req.user, i) {
+		req.status(http.StatusForbidden)
+		return nil
+	}
+
+	return i
+}
+
+func (s *Server) spentHours(issueID int) float64 {
+	hours := 0.0
+	for _, entry := range s.timeEntries {
+		if entry.issueID == issueID {
+			hours += entry.hours
+		}
+	}
+
+	return hours
+}
+
+func (s *Server) childIssues(parentID int) []*issue {
+	children := []*issue{}
+	for _, id := range sortedIDs(s.issues) {
+		if i := s.issues[id]; i.parentID == parentID {
+			children = append(children, i)
+		}
+	}
+
+	return children
+}
+
+func (s *Server) issueTotals(i *issue) (float64, float64) {
+	estimated, spent := 0.0, s.spentHours(i.id)
+	if i.estimatedHours != nil {
+		estimated = *i.estimatedHours
+	}
+	for _, child := range s.childIssues(i.id) {
+		childEstimated, childSpent := s.issueTotals(child)
+		estimated += childEstimated
+		spent += childSpent
+	}
+
+	return estimated, spent
+}
+
+func (s *Server) issueChildren(u *user, parentID int) []childData {
+	children := []childData{}
+	for _, child := range s.childIssues(parentID) {
+		if !s.canViewIssue(u, child) {
+			continue
+		}
+		tracker, _ := findTracker(child.trackerID)
+		children = append(children, childData{
+			ID:       child.id,
+			Tracker:  link(tracker.ID, tracker.Name),
+			Subject:  child.subject,
+			Children: s.issueChildren(u, child.id),
+		})
+	}
+
+	return children
+}
+
+func (s *Server) issueData(u *user, i *issue, includes map[string]bool) issueData {
+	tracker, _ := findTracker(i.trackerID)
+	priority, _ := findEnumeration("issue_priorities", i.priorityID)
+	totalEstimated, totalSpent := s.issueTotals(i)
+
+	data := issueData{
+		ID:                  i.id,
+		Project:             s.projectLink(i.projectID),
+		Tracker:             link(tracker.ID, tracker.Name),
+		Status:              statusLink(i.statusID),
+		Priority:            link(priority.ID, priority.Name),
+		Author:              s.userLink(i.authorID),
+		AssignedTo:          s.userLink(i.assignedToID),
+		Category:            s.categoryLink(i.categoryID),
+		FixedVersion:        s.versionLink(i.fixedVersionID),
+		Parent:              idLink(i.parentID),
+		Subject:             i.subject,
+		Description:         i.description,
+		StartDate:           stringOrNull(i.startDate),
+		DueDate:             stringOrNull(i.dueDate),
+		DoneRatio:           i.doneRatio,
+		IsPrivate:           i.isPrivate,
+		EstimatedHours:      i.estimatedHours,
+		TotalEstimatedHours: hoursOrNull(totalEstimated),
+		SpentHours:          s.spentHours(i.id),
+		TotalSpentHours:     totalSpent,
+		CreatedOn:           i.createdOn,
+		UpdatedOn:           i.updatedOn,
+		ClosedOn:            timeOrNull(i.closedOn),
+	}
+	for _, field := range s.issueCustomFields() {
+		data.CustomFields = append(data.CustomFields, customValueData{
+			ID:    field.ID,
+			Name:  field.Name,
+			Value: i.customFields[field.ID],
+		})
+	}
+
+	if includes["journals"] {
+		data.Journals = []journalData{}
+		for _, journal := range i.journals {
+			if !journal.PrivateNotes || u.admin || s.isMember(u.id, i.projectID) {
+				data.Journals = append(data.Journals, journal)
+			}
+		}
+	}
+	if includes["attachments"] {
+		data.Attachments = []attachmentData{}
+		for _, id := range i.attachmentIDs {
+			data.Attachments = append(data.Attachments, s.attachmentData(s.attachments[id]))
+		}
+	}
+	if includes["relations"] {
+		data.Relations = []relationData{}
+		for _, r := range s.issueRelations(u, i.id) {
+			data.Relations = append(data.Relations, r.data())
+		}
+	}
+	if includes["children"] {
+		data.Children = s.issueChildren(u, i.id)
+	}
+	if includes["watchers"] {
+		data.Watchers = []*linkData{}
+		for _, id := range i.watcherIDs {
+			data.Watchers = append(data.Watchers, s.userLink(id))
+		}
+	}
+	if includes["changesets"] {
+		data.Changesets = []changesetData{}
+	}
+	if includes["allowed_statuses"] {
+		data.AllowedStatuses = statuses
+	}
+
+	return data
+}
+
+// matchIDFilter фильтр по id: "*", "!*", "me", списки через "|" и отрицание "!"
+func matchIDFilter(filter string, value int, me int) bool {
+	switch filter {
+	case "*":
+		return value != 0
+	case "!*":
+		return value == 0
+	}
+
+	negate := strings.HasPrefix(filter, "!")
+	found := false
+	for _, item := range strings.Split(strings.TrimPrefix(filter, "!"), "|") {
+		id, _ := strconv.Atoi(item)
+		if item == "me" {
+			id = me
+		}
+		found = found || id == value
+	}
+
+	return found != negate
+}
+
+// matchTextFilter фильтр по тексту: "~" содержит, "!~" не содержит, иначе точное совпадение
+func matchTextFilter(filter string, value string) bool {
+	switch {
+	case strings.HasPrefix(filter, "!~"):
+		return !strings.Contains(strings.ToLower(value), strings.ToLower(filter[2:]))
+	case strings.HasPrefix(filter, "~"):
+		return strings.Contains(strings.ToLower(value), strings.ToLower(filter[1:]))
+	case strings.HasPrefix(filter, "!"):
+		return value != filter[1:]
+	}
+
+	return value == filter
+}
+
+// matchDateFilter фильтр по дате: ">=date", "<=date", "><from|to" или точная дата
+func matchDateFilter(filter string, value time.Time) bool {
+	switch {
+	case strings.HasPrefix(filter, "><"):
+		from, to, ok := strings.Cut(filter[2:], "|")
+		return ok && afterBound(value, from) && beforeBound(value, to)
+	case strings.HasPrefix(filter, ">="):
+		return afterBound(value, filter[2:])
+	case strings.HasPrefix(filter, "<="):
+		return beforeBound(value, filter[2:])
+	}
+
+	return value.Format(redmineclient.DateFormat) == filter
+}
+
+// afterBound value не раньше bound (дата или время RFC3339)
+func afterBound(value time.Time, bound string) bool {
+	if boundTime, err := time.Parse(time.RFC3339, bound); err == nil {
+		return !value.Before(boundTime)
+	}
+
+	return value.Format(redmineclient.DateFormat) >= bound
+}
+
+// beforeBound value не позже bound (дата или время RFC3339)
+func beforeBound(value time.Time, bound string) bool {
+	if boundTime, err := time.Parse(time.RFC3339, bound); err == nil {
+		return !value.After(boundTime)
+	}
+
+	return value.Format(redmineclient.DateFormat) <= bound
+}
+
+func matchStatusFilter(filter string, statusID int) bool {
+	switch filter {
+	case "", "o", "open":
+		return !isClosedStatus(statusID)
+	case "c", "closed":
+		return isClosedStatus(statusID)
+	}
+
+	return matchIDFilter(filter, statusID, 0)
+}
+
+// projectWithSubprojects id проекта и всех его подпроектов
+func (s *Server) projectWithSubprojects(projectID int) map[int]bool {
+	ids := map[int]bool{projectID: true}
+	for changed := true; changed; {
+		changed = false
+		for _, p := range s.projects {
+			if ids[p.parentID] && !ids[p.id] {
+				ids[p.id] = true
+				changed = true
+			}
+		}
+	}
+
+	return ids
+}
+
+func (s *Server) issueFilter(req *request) (func(i *issue) bool, bool) {
+	query := req.URL.Query()
+	filters := []func(i *issue) bool{}
+
+	projectRef := req.param("project")
+	if projectRef == "" {
+		projectRef = query.Get("project_id")
+	}
+	if projectRef != "" {
+		p := s.findProject(projectRef)
+		if p == nil {
+			req.status(http.StatusNotFound)
+			return nil, false
+		}
+		projects := map[int]bool{p.id: true}
+		if query.Get("subproject_id") != "!*" {
+			projects = s.projectWithSubprojects(p.id)
+		}
+		filters = append(filters, func(i *issue) bool { return projects[i.projectID] })
+	}
+
+	idFilters := map[string]func(i *issue) int{
+		"tracker_id":       func(i *issue) int { return i.trackerID },
+		"priority_id":      func(i *issue) int { return i.priorityID },
+		"author_id":        func(i *issue) int { return i.authorID },
+		"assigned_to_id":   func(i *issue) int { return i.assignedToID },
+		"category_id":      func(i *issue) int { return i.categoryID },
+		"fixed_version_id": func(i *issue) int { return i.fixedVersionID },
+		"parent_id":        func(i *issue) int { return i.parentID },
+	}
+	if filter := query.Get("parent_id"); strings.HasPrefix(filter, "~") {
+		// "~" все подзадачи на любом уровне вложенности
+		ancestorID, _ := strconv.Atoi(filter[1:])
+		filters = append(filters, func(i *issue) bool { return s.isAncestor(ancestorID, i.id) })
+		delete(idFilters, "parent_id")
+	}
+	for name, field := range idFilters {
+		if filter := query.Get(name); filter != "" {
+			field := field
+			filters = append(filters, func(i *issue) bool { return matchIDFilter(filter, field(i), req.user.id) })
+		}
+	}
+
+	status := query.Get("status_id")
+	filters = append(filters, func(i *issue) bool { return matchStatusFilter(status, i.statusID) })
+	if filter := query.Get("issue_id"); filter != "" {
+		ids := map[int]bool{}
+		for _, item := range strings.Split(filter, ",") {
+			id, _ := strconv.Atoi(strings.TrimSpace(item))
+			ids[id] = true
+		}
+		filters = append(filters, func(i *issue) bool { return ids[i.id] })
+	}
+	if filter := query.Get("subject"); filter != "" {
+		filters = append(filters, func(i *issue) bool { return matchTextFilter(filter, i.subject) })
+	}
+	if filter := query.Get("created_on"); filter != "" {
+		filters = append(filters, func(i *issue) bool { return matchDateFilter(filter, i.createdOn) })
+	}
+	if filter := query.Get("updated_on"); filter != "" {
+		filters = append(filters, func(i *issue) bool { return matchDateFilter(filter, i.updatedOn) })
+	}
+	for name, values := range query {
+		if !strings.HasPrefix(name, "cf_") {
+			continue
+		}
+		fieldID, err := strconv.Atoi(strings.TrimPrefix(name, "cf_"))
+		if err != nil {
+			continue
+		}
+		filter := values[0]
+		filters = append(filters, func(i *issue) bool { return matchTextFilter(filter, i.customFields[fieldID]) })
+	}
+
+	return func(i *issue) bool {
+		if !s.canViewIssue(req.user, i) {
+			return false
+		}
+		for _, filter := range filters {
+			if !filter(i) {
+				return false
+			}
+		}
+		return true
+	}, true
+}
+
+// sortIssues сортировка по параметру sort вида "field:desc,field"
+func sortIssues(issues []*issue, spec string) {
+	if spec == "" {
+		spec = "id:desc"
+	}
+	keys := strings.Split(spec, ",")
+	compare := func(a, b *issue, field string) int {
+		switch field {
+		case "subject":
+			return strings.Compare(a.subject, b.subject)
+		case "priority":
+			return a.priorityID - b.priorityID
+		case "status":
+			return a.statusID - b.statusID
+		case "start_date":
+			return strings.Compare(a.startDate, b.startDate)
+		case "due_date":
+			return strings.Compare(a.dueDate, b.dueDate)
+		case "created_on":
+			return a.createdOn.Compare(b.createdOn)
+		case "updated_on":
+			return a.updatedOn.Compare(b.updatedOn)
+		}
+		return a.id - b.id
+	}
+
+	sort.SliceStable(issues, func(x, y int) bool {
+		for _, key := range keys {
+			field, direction, _ := strings.Cut(strings.TrimSpace(key), ":")
+			result := compare(issues[x], issues[y], field)
+			if direction == "desc" {
+				result = -result
+			}
+			if result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+}
+
+func (s *Server) listIssues(req *request) {
+	filter, ok := s.issueFilter(req)
+	if !ok {
+		return
+	}
+
+	matched := []*issue{}
+	for _, i := range s.issues {
+		if filter(i) {
+			matched = append(matched, i)
+		}
+	}
+	sortIssues(matched, req.query("sort"))
+
+	includes := req.includes()
+	issues := []issueData{}
+	for _, i := range matched {
+		issues = append(issues, s.issueData(req.user, i, includes))
+	}
+
+	renderPage(req, "issues", issues)
+}
+
+func (s *Server) getIssue(req *request) {
+	i := s.pathIssue(req)
+	if i == nil {
+		return
+	}
+
+	data := s.issueData(req.user, i, req.includes())
+	req.render(http.StatusOK, map[string]interface{}{"issue": data})
+}
+
+// attachUploads привязка загруженных файлов к задаче, возвращает ошибки для неизвестных токенов
+func (s *Server) attachUploads(uploads []uploadParams) ([]*attachment, validation) {
+	attached := []*attachment{}
+	errs := validation{}
+	for _, upload := range uploads {
+		a := s.attachmentByToken(upload.Token)
+		if a == nil {
+			errs.add("Attachments is invalid")
+			continue
+		}
+		if upload.Filename != "" {
+			a.filename = upload.Filename
+		}
+		if upload.ContentType != "" {
+			a.contentType = upload.ContentType
+		}
+		a.description = upload.Description
+		attached = append(attached, a)
+	}
+
+	return attached, errs
+}
+
+func (s *Server) createIssue(req *request) {
+	params := issueParams{}
+	if !req.decode("issue", &params) {
+		return
+	}
+
+	i := &issue{
+		trackerID:  trackers[0].ID,
+		priorityID: defaultEnumeration("issue_priorities").ID,
+		authorID:   req.user.id,
+		startDate:  s.today(),
+	}
+	params.apply(i)
+	if params.StatusID == nil {
+		tracker, _ := findTracker(i.trackerID)
+		i.statusID = tracker.DefaultStatus.ID
+	}
+	if p, ok := s.projects[i.projectID]; ok && !s.editableProject(req, p) {
+		return
+	}
+
+	errs := s.validateIssue(i)
+	attached, uploadErrs := s.attachUploads(params.Uploads)
+	errs = append(errs, uploadErrs...)
+	if len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	i.id = s.nextID("issue")
+	i.createdOn = s.timeNow()
+	i.updatedOn = i.createdOn
+	if isClosedStatus(i.statusID) {
+		i.closedOn = i.createdOn
+	}
+	for _, a := range attached {
+		a.attach(i.id)
+		i.attachmentIDs = append(i.attachmentIDs, a.id)
+	}
+	for _, userID := range params.WatcherUserIDs {
+		if _, ok := s.users[userID]; ok {
+			i.watcherIDs = append(i.watcherIDs, userID)
+		}
+	}
+	s.issues[i.id] = i
+
+	data := s.issueData(req.user, i, nil)
+	req.render(http.StatusCreated, map[string]interface{}{"issue": data})
+}
+
+// hoursOrNull суммарная оценка трудозатрат для ответа, 0 отдаётся как null
+func hoursOrNull(hours float64) *float64 {
+	if hours == 0 {
+		return nil
+	}
+
+	return &hours
+}
+
+func formatHours(hours *float64) string {
+	if hours == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*hours, 'f', -1, 64)
+}
+
+func formatID(id int) string {
+	if id == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(id)
+}
+
+// journalDetails изменения атрибутов и настраиваемых полей задачи
+func (s *Server) journalDetails(old, updated *issue) []journalDetailData {
+	details := []journalDetailData{}
+	attr := func(name, oldValue, newValue string) {
+		if oldValue != newValue {
+			details = append(details, journalDetailData{
+				Property: "attr", Name: name, OldValue: stringOrNull(oldValue), NewValue: stringOrNull(newValue),
+			})
+		}
+	}
+
+	attr("project_id", formatID(old.projectID), formatID(updated.projectID))
+	attr("tracker_id", formatID(old.trackerID), formatID(updated.trackerID))
+	attr("subject", old.subject, updated.subject)
+	attr("description", old.description, updated.description)
+	attr("status_id", formatID(old.statusID), formatID(updated.statusID))
+	attr("priority_id", formatID(old.priorityID), formatID(updated.priorityID))
+	attr("assigned_to_id", formatID(old.assignedToID), formatID(updated.assignedToID))
+	attr("category_id", formatID(old.categoryID), formatID(updated.categoryID))
+	attr("fixed_version_id", formatID(old.fixedVersionID), formatID(updated.fixedVersionID))
+	attr("parent_id", formatID(old.parentID), formatID(updated.parentID))
+	attr("start_date", old.startDate, updated.startDate)
+	attr("due_date", old.dueDate, updated.dueDate)
+	attr("done_ratio", strconv.Itoa(old.doneRatio), strconv.Itoa(updated.doneRatio))
+	attr("estimated_hours", formatHours(old.estimatedHours), formatHours(updated.estimatedHours))
+	attr("is_private", strconv.FormatBool(old.isPrivate), strconv.FormatBool(updated.isPrivate))
+
+	for _, field := range s.issueCustomFields() {
+		if oldValue, newValue := old.customFields[field.ID], updated.customFields[field.ID]; oldValue != newValue {
+			details = append(details, journalDetailData{
+				Property: "cf", Name: strconv.Itoa(field.ID), OldValue: stringOrNull(oldValue), NewValue: stringOrNull(newValue),
+			})
+		}
+	}
+
+	return details
+}
+
+func (s *Server) updateIssue(req *request) {
+	i := s.pathIssue(req)
+	if i == nil || !s.editableProject(req, s.projects[i.projectID]) {
+		return
+	}
+
+	params := issueParams{}
+	if !req.decode("issue", &params) {
+		return
+	}
+	updated := *i
+	params.apply(&updated)
+	if p, ok := s.projects[updated.projectID]; ok && updated.projectID != i.projectID && !s.canEdit(req.user, p) {
+		req.unprocessable([]string{"Project is invalid"})
+		return
+	}
+
+	errs := s.validateIssue(&updated)
+	attached, uploadErrs := s.attachUploads(params.Uploads)
+	errs = append(errs, uploadErrs...)
+	if len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	details := s.journalDetails(i, &updated)
+	for _, a := range attached {
+		a.attach(updated.id)
+		updated.attachmentIDs = append(updated.attachmentIDs, a.id)
+		details = append(details, journalDetailData{
+			Property: "attachment", Name: strconv.Itoa(a.id), NewValue: stringOrNull(a.filename),
+		})
+	}
+	now := s.timeNow()
+	if params.Notes != "" || len(details) > 0 {
+		updated.journals = append(append([]journalData{}, i.journals...), journalData{
+			ID:           s.nextID("journal"),
+			User:         s.userLink(req.user.id),
+			Notes:        params.Notes,
+			PrivateNotes: params.PrivateNotes,
+			CreatedOn:    now,
+			Details:      details,
+		})
+		updated.updatedOn = now
+	}
+	if isClosedStatus(updated.statusID) && !isClosedStatus(i.statusID) {
+		updated.closedOn = now
+	}
+	if params.WatcherUserIDs != nil {
+		updated.watcherIDs = params.WatcherUserIDs
+	}
+
+	*i = updated
+	req.status(http.StatusNoContent)
+}
+
+// removeIssue удаление задачи с подзадачами, связями, трудозатратами и вложениями
+func (s *Server) removeIssue(issueID int) {
+	i, ok := s.issues[issueID]
+	if !ok {
+		return
+	}
+	for _, child := range s.childIssues(issueID) {
+		s.removeIssue(child.id)
+	}
+	for id, r := range s.relations {
+		if r.issueID == issueID || r.issueToID == issueID {
+			delete(s.relations, id)
+		}
+	}
+	for id, entry := range s.timeEntries {
+		if entry.issueID == issueID {
+			delete(s.timeEntries, id)
+		}
+	}
+	for _, id := range i.attachmentIDs {
+		delete(s.attachments, id)
+	}
+	delete(s.issues, issueID)
+}
+
+func (s *Server) deleteIssue(req *request) {
+	i := s.pathIssue(req)
+	if i == nil || !s.editableProject(req, s.projects[i.projectID]) {
+		return
+	}
+
+	s.removeIssue(i.id)
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) addWatcher(req *request) {
+	i := s.pathIssue(req)
+	if i == nil || !s.editableProject(req, s.projects[i.projectID]) {
+		return
+	}
+
+	params := struct {
+		UserID int `json:"user_id"`
+	}{}
+	if !req.decodeRaw(&params) {
+		return
+	}
+	if _, ok := s.users[params.UserID]; !ok {
+		req.unprocessable([]string{"User is invalid"})
+		return
+	}
+	for _, id := range i.watcherIDs {
+		if id == params.UserID {
+			req.status(http.StatusNoContent)
+			return
+		}
+	}
+
+	i.watcherIDs = append(i.watcherIDs, params.UserID)
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) removeWatcher(req *request) {
+	i := s.pathIssue(req)
+	if i == nil || !s.editableProject(req, s.projects[i.projectID]) {
+		return
+	}
+
+	userID := req.id("user")
+	watchers := []int{}
+	for _, id := range i.watcherIDs {
+		if id != userID {
+			watchers = append(watchers, id)
+		}
+	}
+	i.watcherIDs = watchers
+	req.status(http.StatusNoContent)
+}
+
+type relation struct {
+	id           int
+	issueID      int
+	issueToID    int
+	relationType redmineclient.RdRelationType
+	delay        *int
+}
+
+type relationParams struct {
+	IssueToID    int                          `json:"issue_to_id"`
+	RelationType redmineclient.RdRelationType `json:"relation_type"`
+	Delay        *int                         `json:"delay"`
+}
+
+type relationData struct {
+	ID           int    `json:"id"`
+	IssueID      int    `json:"issue_id"`
+	IssueToID    int    `json:"issue_to_id"`
+	RelationType string `json:"relation_type"`
+	Delay        *int   `json:"delay"`
+}
+
+func (r *relation) data() relationData {
+	return relationData{
+		ID:           r.id,
+		IssueID:      r.issueID,
+		IssueToID:    r.issueToID,
+		RelationType: string(r.relationType),
+		Delay:        r.delay,
+	}
+}
+
+// issueRelations связи задачи с видимыми пользователю задачами
+func (s *Server) issueRelations(u *user, issueID int) []*relation {
+	relations := []*relation{}
+	for _, id := range sortedIDs(s.relations) {
+		r := s.relations[id]
+		otherID := r.issueToID
+		if r.issueToID == issueID {
+			otherID = r.issueID
+		} else if r.issueID != issueID {
+			continue
+		}
+		if other, ok := s.issues[otherID]; ok && s.canViewIssue(u, other) {
+			relations = append(relations, r)
+		}
+	}
+
+	return relations
+}
+
+func (s *Server) listRelations(req *request) {
+	i := s.pathIssue(req)
+	if i == nil {
+		return
+	}
+
+	relations := []relationData{}
+	for _, r := range s.issueRelations(req.user, i.id) {
+		relations = append(relations, r.data())
+	}
+
+	renderAll(req, "relations", relations)
+}
+
+func (s *Server) createRelation(req *request) {
+	i := s.pathIssue(req)
+	if i == nil || !s.editableProject(req, s.projects[i.projectID]) {
+		return
+	}
+
+	params := relationParams{}
+	if !req.decode("relation", &params) {
+		return
+	}
+	if params.RelationType == "" {
+		params.RelationType = redmineclient.RelationRelates
+	}
+	r := &relation{issueID: i.id, issueToID: params.IssueToID, relationType: params.RelationType}
+	if r.relationType.HasDelay() {
+		r.delay = params.Delay
+	}
+
+	errs := validation{}
+	if other, ok := s.issues[r.issueToID]; !ok || !s.canViewIssue(req.user, other) {
+		errs.add("Related issue cannot be blank")
+	} else if r.issueToID == r.issueID {
+		errs.add("Related issue is invalid")
+	}
+	if !r.relationType.IsValid() {
+		errs.add("Relation type is not included in the list")
+	}
+	// redmine хранит обратные типы связей в прямом виде
+	switch r.relationType {
+	case redmineclient.RelationBlocked, redmineclient.RelationFollows,
+		redmineclient.RelationDuplicated, redmineclient.RelationCopiedFrom:
+		r.issueID, r.issueToID, r.relationType = r.issueToID, r.issueID, r.relationType.Inverse()
+	}
+	for _, other := range s.relations {
+		if other.issueID == r.issueID && other.issueToID == r.issueToID ||
+			other.issueID == r.issueToID && other.issueToID == r.issueID {
+			errs.add("Related issue has already been taken")
+			break
+		}
+	}
+	if len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	r.id = s.nextID("relation")
+	s.relations[r.id] = r
+	req.render(http.StatusCreated, map[string]interface{}{"relation": r.data()})
+}
+
+func (s *Server) pathRelation(req *request) *relation {
+	r, ok := s.relations[req.id("id")]
+	if !ok || !s.canViewIssue(req.user, s.issues[r.issueID]) || !s.canViewIssue(req.user, s.issues[r.issueToID]) {
+		req.status(http.StatusNotFound)
+		return nil
+	}
+
+	return r
+}
+
+func (s *Server) getRelation(req *request) {
+	r := s.pathRelation(req)
+	if r == nil {
+		return
+	}
+
+	req.render(http.StatusOK, map[string]interface{}{"relation": r.data()})
+}
+
+func (s *Server) deleteRelation(req *request) {
+	r := s.pathRelation(req)
+	if r == nil || !s.editableProject(req, s.projects[s.issues[r.issueID].projectID]) {
+		return
+	}
+
+	delete(s.relations, r.id)
+	req.status(http.StatusNoContent)
+}
+
+func issueTitle(i *issue) string {
+	tracker, _ := findTracker(i.trackerID)
+	status, _ := findStatus(i.statusID)
+
+	return fmt.Sprintf("%s #%d (%s): %s", tracker.Name, i.id, status.Name, i.subject)
+}
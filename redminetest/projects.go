@@ -0,0 +1,833 @@
+package redminetest
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var identifierPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+type project struct {
+	id          int
+	name        string
+	identifier  string
+	description string
+	homepage    string
+	isPublic    bool
+	parentID    int
+	createdOn   time.Time
+	updatedOn   time.Time
+}
+
+type projectData struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Identifier  string    `json:"identifier"`
+	Description string    `json:"description"`
+	Homepage    string    `json:"homepage"`
+	Parent      *linkData `json:"parent,omitempty"`
+	IsPublic    bool      `json:"is_public"`
+	CreatedOn   time.Time `json:"created_on"`
+	UpdatedOn   time.Time `json:"updated_on"`
+}
+
+type projectParams struct {
+	Name        *string `json:"name"`
+	Identifier  *string `json:"identifier"`
+	Description *string `json:"description"`
+	Homepage    *string `json:"homepage"`
+	IsPublic    *bool   `json:"is_public"`
+	ParentID    *int    `json:"parent_id"`
+}
+
+func (params *projectParams) apply(p *project) {
+	setString(&p.name, params.Name)
+	setString(&p.description, params.Description)
+	setString(&p.homepage, params.Homepage)
+	if params.IsPublic != nil {
+		p.isPublic = *params.IsPublic
+	}
+	if params.ParentID != nil {
+		p.parentID = *params.ParentID
+	}
+}
+
+type membership struct {
+	id        int
+	projectID int
+	userID    int
+	roleIDs   []int
+}
+
+type membershipData struct {
+	ID      int         `json:"id"`
+	Project *linkData   `json:"project"`
+	User    *linkData   `json:"user,omitempty"`
+	Roles   []*linkData `json:"roles"`
+}
+
+type membershipParams struct {
+	UserID  *int   `json:"user_id"`
+	RoleIDs *[]int `json:"role_ids"`
+}
+
+type version struct {
+	id          int
+	projectID   int
+	name        string
+	description string
+	status      string
+	sharing     string
+	dueDate     string
+	createdOn   time.Time
+	updatedOn   time.Time
+}
+
+type versionParams struct {
+	Name          *string `json:"name"`
+	Description   *string `json:"description"`
+	Status        *string `json:"status"`
+	Sharing       *string `json:"sharing"`
+	DueDate       *string `json:"due_date"`
+	EffectiveDate *string `json:"effective_date"`
+}
+
+func (params *versionParams) apply(v *version) {
+	setString(&v.name, params.Name)
+	setString(&v.description, params.Description)
+	setString(&v.status, params.Status)
+	setString(&v.sharing, params.Sharing)
+	setString(&v.dueDate, params.EffectiveDate)
+	setString(&v.dueDate, params.DueDate)
+}
+
+type versionData struct {
+	ID          int       `json:"id"`
+	Project     *linkData `json:"project"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	DueDate     *string   `json:"due_date"`
+	Sharing     string    `json:"sharing"`
+	CreatedOn   time.Time `json:"created_on"`
+	UpdatedOn   time.Time `json:"updated_on"`
+}
+
+type category struct {
+	id           int
+	projectID    int
+	name         string
+	assignedToID int
+}
+
+type categoryData struct {
+	ID         int       `json:"id"`
+	Project    *linkData `json:"project"`
+	Name       string    `json:"name"`
+	AssignedTo *linkData `json:"assigned_to,omitempty"`
+}
+
+type categoryParams struct {
+	Name         *string `json:"name"`
+	AssignedToID *int    `json:"assigned_to_id"`
+}
+
+// sortedIDs ключи в порядке возрастания
+func sortedIDs[T any](items map[int]T) []int {
+	ids := make([]int, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+// renderCounted список без постраничного вывода с total_count
+func renderCounted[T any](req *request, key string, items []T) {
+	req.render(http.StatusOK, map[string]interface{}{key: items, "total_count": len(items)})
+}
+
+// findProject проект по id или идентификатору
+func (s *Server) findProject(ref string) *project {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return s.projects[id]
+	}
+	for _, p := range s.projects {
+		if p.identifier == ref {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) isMember(userID, projectID int) bool {
+	for _, m := range s.memberships {
+		if m.userID == userID && m.projectID == projectID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) canView(u *user, p *project) bool {
+	return u.admin || p.isPublic || s.isMember(u.id, p.id)
+}
+
+func (s *Server) canEdit(u *user, p *project) bool {
+	return u.admin || s.isMember(u.id, p.id)
+}
+
+// visibleProject проект из пути запроса, отвечает 404 или 403 если проект недоступен
+func (s *Server) visibleProject(req *request, projectID int) *project {
+	p, ok := s.projects[projectID]
+	if !ok {
+		req.status(http.StatusNotFound)
+		return nil
+	}
+	if !s.canView(req.user, p) {
+		req.status(http.StatusForbidden)
+		return nil
+	}
+
+	return p
+}
+
+func (s *Server) pathProject(req *request) *project {
+	p := s.findProject(req.param("project"))
+	if p == nil {
+		req.status(http.StatusNotFound)
+		return nil
+	}
+
+	return s.visibleProject(req, p.id)
+}
+
+// editableProject проект, доступный пользователю для изменения, иначе 403
+func (s *Server) editableProject(req *request, p *project) bool {
+	if !s.canEdit(req.user, p) {
+		req.status(http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) projectLink(id int) *linkData {
+	if p, ok := s.projects[id]; ok {
+		return link(p.id, p.name)
+	}
+
+	return nil
+}
+
+func (s *Server) validateProject(p *project) validation {
+	errs := validation{}
+	if p.name == "" {
+		errs.add("Name cannot be blank")
+	} else if len(p.name) > 255 {
+		errs.add("Name is too long (maximum is 255 characters)")
+	}
+	if p.identifier == "" {
+		errs.add("Identifier cannot be blank")
+	} else if _, err := strconv.Atoi(p.identifier); err == nil || len(p.identifier) > 100 ||
+		!identifierPattern.MatchString(p.identifier) {
+		errs.add("Identifier is invalid")
+	} else if other := s.findProject(p.identifier); other != nil && other.id != p.id {
+		errs.add("Identifier has already been taken")
+	}
+	if p.parentID != 0 {
+		if _, ok := s.projects[p.parentID]; !ok || p.parentID == p.id {
+			errs.add("Subproject of is invalid")
+		}
+	}
+
+	return errs
+}
+
+func (s *Server) projectData(p *project) projectData {
+	return projectData{
+		ID:          p.id,
+		Name:        p.name,
+		Identifier:  p.identifier,
+		Description: p.description,
+		Homepage:    p.homepage,
+		Parent:      s.projectLink(p.parentID),
+		IsPublic:    p.isPublic,
+		CreatedOn:   p.createdOn,
+		UpdatedOn:   p.updatedOn,
+	}
+}
+
+func (s *Server) listProjects(req *request) {
+	projects := []projectData{}
+	for _, id := range sortedIDs(s.projects) {
+		if p := s.projects[id]; s.canView(req.user, p) {
+			projects = append(projects, s.projectData(p))
+		}
+	}
+
+	renderPage(req, "projects", projects)
+}
+
+func (s *Server) getProject(req *request) {
+	p := s.pathProject(req)
+	if p == nil {
+		return
+	}
+
+	req.render(http.StatusOK, map[string]interface{}{"project": s.projectData(p)})
+}
+
+func (s *Server) createProject(req *request) {
+	if !req.user.admin {
+		req.status(http.StatusForbidden)
+		return
+	}
+
+	params := projectParams{}
+	if !req.decode("project", &params) {
+		return
+	}
+	p := &project{isPublic: true}
+	params.apply(p)
+	setString(&p.identifier, params.Identifier)
+	if errs := s.validateProject(p); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	p.id = s.nextID("project")
+	p.createdOn = s.timeNow()
+	p.updatedOn = p.createdOn
+	s.projects[p.id] = p
+	req.render(http.StatusCreated, map[string]interface{}{"project": s.projectData(p)})
+}
+
+func (s *Server) updateProject(req *request) {
+	p := s.pathProject(req)
+	if p == nil || !s.editableProject(req, p) {
+		return
+	}
+
+	params := projectParams{}
+	if !req.decode("project", &params) {
+		return
+	}
+	updated := *p
+	params.apply(&updated)
+	if errs := s.validateProject(&updated); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	updated.updatedOn = s.timeNow()
+	*p = updated
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) deleteProject(req *request) {
+	p := s.pathProject(req)
+	if p == nil {
+		return
+	}
+	if !req.user.admin {
+		req.status(http.StatusForbidden)
+		return
+	}
+
+	s.removeProject(p.id)
+	req.status(http.StatusNoContent)
+}
+
+// removeProject удаление проекта, подпроектов и всех данных проекта
+func (s *Server) removeProject(projectID int) {
+	for id, p := range s.projects {
+		if p.parentID == projectID {
+			s.removeProject(id)
+		}
+	}
+	for id, i := range s.issues {
+		if i.projectID == projectID {
+			s.removeIssue(id)
+		}
+	}
+	for id, m := range s.memberships {
+		if m.projectID == projectID {
+			delete(s.memberships, id)
+		}
+	}
+	for id, v := range s.versions {
+		if v.projectID == projectID {
+			delete(s.versions, id)
+		}
+	}
+	for id, c := range s.categories {
+		if c.projectID == projectID {
+			delete(s.categories, id)
+		}
+	}
+	for id, entry := range s.timeEntries {
+		if entry.projectID == projectID {
+			delete(s.timeEntries, id)
+		}
+	}
+	for id, page := range s.wikiPages {
+		if page.projectID == projectID {
+			delete(s.wikiPages, id)
+		}
+	}
+	delete(s.projects, projectID)
+}
+
+func (s *Server) sortedMemberships() []*membership {
+	memberships := []*membership{}
+	for _, id := range sortedIDs(s.memberships) {
+		memberships = append(memberships, s.memberships[id])
+	}
+
+	return memberships
+}
+
+func (s *Server) membershipData(m *membership) membershipData {
+	roles := []*linkData{}
+	for _, roleID := range m.roleIDs {
+		if role, ok := findRole(roleID); ok {
+			roles = append(roles, link(role.ID, role.Name))
+		}
+	}
+
+	return membershipData{
+		ID:      m.id,
+		Project: s.projectLink(m.projectID),
+		User:    s.userLink(m.userID),
+		Roles:   roles,
+	}
+}
+
+func (s *Server) validateMembership(m *membership) validation {
+	errs := validation{}
+	if _, ok := s.users[m.userID]; !ok {
+		errs.add("Principal cannot be blank")
+	} else {
+		for _, other := range s.memberships {
+			if other.id != m.id && other.projectID == m.projectID && other.userID == m.userID {
+				errs.add("Principal has already been taken")
+				break
+			}
+		}
+	}
+	if len(m.roleIDs) == 0 {
+		errs.add("Role cannot be empty")
+	}
+	for _, roleID := range m.roleIDs {
+		if _, ok := findRole(roleID); !ok {
+			errs.add("Role is invalid")
+			break
+		}
+	}
+
+	return errs
+}
+
+func (s *Server) listMemberships(req *request) {
+	p := s.pathProject(req)
+	if p == nil {
+		return
+	}
+
+	memberships := []membershipData{}
+	for _, m := range s.sortedMemberships() {
+		if m.projectID == p.id {
+			memberships = append(memberships, s.membershipData(m))
+		}
+	}
+
+	renderPage(req, "memberships", memberships)
+}
+
+func (s *Server) createMembership(req *request) {
+	p := s.pathProject(req)
+	if p == nil || !s.editableProject(req, p) {
+		return
+	}
+
+	params := membershipParams{}
+	if !req.decode("membership", &params) {
+		return
+	}
+	m := &membership{projectID: p.id}
+	if params.UserID != nil {
+		m.userID = *params.UserID
+	}
+	if params.RoleIDs != nil {
+		m.roleIDs = *params.RoleIDs
+	}
+	if errs := s.validateMembership(m); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	m.id = s.nextID("membership")
+	s.memberships[m.id] = m
+	req.render(http.StatusCreated, map[string]interface{}{"membership": s.membershipData(m)})
+}
+
+// pathMembership участие из пути запроса с проверкой доступа к проекту
+func (s *Server) pathMembership(req *request) *membership {
+	m, ok := s.memberships[req.id("id")]
+	if !ok {
+		req.status(http.StatusNotFound)
+		return nil
+	}
+	if s.visibleProject(req, m.projectID) == nil {
+		return nil
+	}
+
+	return m
+}
+
+func (s *Server) getMembership(req *request) {
+	m := s.pathMembership(req)
+	if m == nil {
+		return
+	}
+
+	req.render(http.StatusOK, map[string]interface{}{"membership": s.membershipData(m)})
+}
+
+func (s *Server) updateMembership(req *request) {
+	m := s.pathMembership(req)
+	if m == nil || !s.editableProject(req, s.projects[m.projectID]) {
+		return
+	}
+
+	params := membershipParams{}
+	if !req.decode("membership", &params) {
+		return
+	}
+	updated := *m
+	if params.RoleIDs != nil {
+		updated.roleIDs = *params.RoleIDs
+	}
+	if errs := s.validateMembership(&updated); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	*m = updated
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) deleteMembership(req *request) {
+	m := s.pathMembership(req)
+	if m == nil || !s.editableProject(req, s.projects[m.projectID]) {
+		return
+	}
+
+	delete(s.memberships, m.id)
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) versionLink(id int) *linkData {
+	if v, ok := s.versions[id]; ok {
+		return link(v.id, v.name)
+	}
+
+	return nil
+}
+
+func (s *Server) validateVersion(v *version) validation {
+	errs := validation{}
+	if v.name == "" {
+		errs.add("Name cannot be blank")
+	} else {
+		for _, other := range s.versions {
+			if other.id != v.id && other.projectID == v.projectID && other.name == v.name {
+				errs.add("Name has already been taken")
+				break
+			}
+		}
+	}
+	switch v.status {
+	case "open", "locked", "closed":
+	default:
+		errs.add("Status is not included in the list")
+	}
+	switch v.sharing {
+	case "none", "descendants", "hierarchy", "tree", "system":
+	default:
+		errs.add("Sharing is not included in the list")
+	}
+	if v.dueDate != "" && !validDate(v.dueDate) {
+		errs.add("Due date is not a valid date")
+	}
+
+	return errs
+}
+
+func (s *Server) versionData(v *version) versionData {
+	return versionData{
+		ID:          v.id,
+		Project:     s.projectLink(v.projectID),
+		Name:        v.name,
+		Description: v.description,
+		Status:      v.status,
+		DueDate:     stringOrNull(v.dueDate),
+		Sharing:     v.sharing,
+		CreatedOn:   v.createdOn,
+		UpdatedOn:   v.updatedOn,
+	}
+}
+
+// versionAvailable версия может быть назначена задачам проекта
+func (s *Server) versionAvailable(v *version, projectID int) bool {
+	return v.projectID == projectID || v.sharing == "system"
+}
+
+func (s *Server) listVersions(req *request) {
+	p := s.pathProject(req)
+	if p == nil {
+		return
+	}
+
+	versions := []versionData{}
+	for _, id := range sortedIDs(s.versions) {
+		if v := s.versions[id]; s.versionAvailable(v, p.id) {
+			versions = append(versions, s.versionData(v))
+		}
+	}
+
+	renderCounted(req, "versions", versions)
+}
+
+func (s *Server) createVersion(req *request) {
+	p := s.pathProject(req)
+	if p == nil || !s.editableProject(req, p) {
+		return
+	}
+
+	params := versionParams{}
+	if !req.decode("version", &params) {
+		return
+	}
+	v := &version{projectID: p.id, status: "open", sharing: "none"}
+	params.apply(v)
+	if errs := s.validateVersion(v); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	v.id = s.nextID("version")
+	v.createdOn = s.timeNow()
+	v.updatedOn = v.createdOn
+	s.versions[v.id] = v
+	req.render(http.StatusCreated, map[string]interface{}{"version": s.versionData(v)})
+}
+
+func (s *Server) pathVersion(req *request) *version {
+	v, ok := s.versions[req.id("id")]
+	if !ok {
+		req.status(http.StatusNotFound)
+		return nil
+	}
+	if s.visibleProject(req, v.projectID) == nil {
+		return nil
+	}
+
+	return v
+}
+
+func (s *Server) getVersion(req *request) {
+	v := s.pathVersion(req)
+	if v == nil {
+		return
+	}
+
+	req.render(http.StatusOK, map[string]interface{}{"version": s.versionData(v)})
+}
+
+func (s *Server) updateVersion(req *request) {
+	v := s.pathVersion(req)
+	if v == nil || !s.editableProject(req, s.projects[v.projectID]) {
+		return
+	}
+
+	params := versionParams{}
+	if !req.decode("version", &params) {
+		return
+	}
+	updated := *v
+	params.apply(&updated)
+	if errs := s.validateVersion(&updated); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	updated.updatedOn = s.timeNow()
+	*v = updated
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) deleteVersion(req *request) {
+	v := s.pathVersion(req)
+	if v == nil || !s.editableProject(req, s.projects[v.projectID]) {
+		return
+	}
+	for _, i := range s.issues {
+		if i.fixedVersionID == v.id {
+			req.unprocessable([]string{"Unable to delete version"})
+			return
+		}
+	}
+
+	delete(s.versions, v.id)
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) categoryLink(id int) *linkData {
+	if c, ok := s.categories[id]; ok {
+		return link(c.id, c.name)
+	}
+
+	return nil
+}
+
+func (s *Server) validateCategory(c *category) validation {
+	errs := validation{}
+	if c.name == "" {
+		errs.add("Name cannot be blank")
+	} else {
+		for _, other := range s.categories {
+			if other.id != c.id && other.projectID == c.projectID && other.name == c.name {
+				errs.add("Name has already been taken")
+				break
+			}
+		}
+	}
+	if _, ok := s.users[c.assignedToID]; c.assignedToID != 0 && !ok {
+		errs.add("Assignee is invalid")
+	}
+
+	return errs
+}
+
+func (s *Server) categoryData(c *category) categoryData {
+	return categoryData{
+		ID:         c.id,
+		Project:    s.projectLink(c.projectID),
+		Name:       c.name,
+		AssignedTo: s.userLink(c.assignedToID),
+	}
+}
+
+func (s *Server) listCategories(req *request) {
+	p := s.pathProject(req)
+	if p == nil {
+		return
+	}
+
+	categories := []categoryData{}
+	for _, id := range sortedIDs(s.categories) {
+		if c := s.categories[id]; c.projectID == p.id {
+			categories = append(categories, s.categoryData(c))
+		}
+	}
+
+	renderCounted(req, "issue_categories", categories)
+}
+
+func (s *Server) createCategory(req *request) {
+	p := s.pathProject(req)
+	if p == nil || !s.editableProject(req, p) {
+		return
+	}
+
+	params := categoryParams{}
+	if !req.decode("issue_category", &params) {
+		return
+	}
+	c := &category{projectID: p.id}
+	setString(&c.name, params.Name)
+	if params.AssignedToID != nil {
+		c.assignedToID = *params.AssignedToID
+	}
+	if errs := s.validateCategory(c); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	c.id = s.nextID("issue_category")
+	s.categories[c.id] = c
+	req.render(http.StatusCreated, map[string]interface{}{"issue_category": s.categoryData(c)})
+}
+
+func (s *Server) pathCategory(req *request) *category {
+	c, ok := s.categories[req.id("id")]
+	if !ok {
+		req.status(http.StatusNotFound)
+		return nil
+	}
+	if s.visibleProject(req, c.projectID) == nil {
+		return nil
+	}
+
+	return c
+}
+
+func (s *Server) getCategory(req *request) {
+	c := s.pathCategory(req)
+	if c == nil {
+		return
+	}
+
+	req.render(http.StatusOK, map[string]interface{}{"issue_category": s.categoryData(c)})
+}
+
+func (s *Server) updateCategory(req *request) {
+	c := s.pathCategory(req)
+	if c == nil || !s.editableProject(req, s.projects[c.projectID]) {
+		return
+	}
+
+	params := categoryParams{}
+	if !req.decode("issue_category", &params) {
+		return
+	}
+	updated := *c
+	setString(&updated.name, params.Name)
+	if params.AssignedToID != nil {
+		updated.assignedToID = *params.AssignedToID
+	}
+	if errs := s.validateCategory(&updated); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	*c = updated
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) deleteCategory(req *request) {
+	c := s.pathCategory(req)
+	if c == nil || !s.editableProject(req, s.projects[c.projectID]) {
+		return
+	}
+
+	reassignTo, _ := strconv.Atoi(req.query("reassign_to_id"))
+	if other, ok := s.categories[reassignTo]; !ok || other.projectID != c.projectID {
+		reassignTo = 0
+	}
+	for _, i := range s.issues {
+		if i.categoryID == c.id {
+			i.categoryID = reassignTo
+		}
+	}
+	delete(s.categories, c.id)
+	req.status(http.StatusNoContent)
+}
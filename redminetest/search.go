@@ -0,0 +1,143 @@
+package redminetest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type searchResultData struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Type        string    `json:"type"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	Datetime    time.Time `json:"datetime"`
+}
+
+// matchTokens вхождение слов запроса в текст: всех при allWords, иначе хотя бы одного
+func matchTokens(tokens []string, allWords bool, texts ...string) bool {
+	text := strings.ToLower(strings.Join(texts, " "))
+	for _, token := range tokens {
+		found := strings.Contains(text, token)
+		if found && !allWords {
+			return true
+		}
+		if !found && allWords {
+			return false
+		}
+	}
+
+	return allWords
+}
+
+func (s *Server) search(req *request) {
+	var projects map[int]bool
+	if req.param("project") != "" {
+		p := s.pathProject(req)
+		if p == nil {
+			return
+		}
+		projects = s.projectWithSubprojects(p.id)
+	}
+
+	query := req.URL.Query()
+	tokens := strings.Fields(strings.ToLower(query.Get("q")))
+	allWords := query.Get("all_words") != "0"
+	titlesOnly := query.Get("titles_only") == "1"
+	scopes := map[string]bool{}
+	for _, scope := range []string{"issues", "wiki_pages", "projects"} {
+		if query.Get(scope) == "1" {
+			scopes[scope] = true
+		}
+	}
+	inScope := func(scope string) bool {
+		return len(scopes) == 0 || scopes[scope]
+	}
+	inProject := func(projectID int) bool {
+		return (projects == nil || projects[projectID]) && s.canView(req.user, s.projects[projectID])
+	}
+
+	results := []searchResultData{}
+	if len(tokens) > 0 && inScope("issues") {
+		for _, i := range s.issues {
+			if !inProject(i.projectID) || !s.canViewIssue(req.user, i) ||
+				query.Get("open_issues") == "1" && isClosedStatus(i.statusID) {
+				continue
+			}
+			texts := []string{i.subject}
+			if !titlesOnly {
+				texts = append(texts, i.description)
+			}
+			if !matchTokens(tokens, allWords, texts...) {
+				continue
+			}
+
+			resultType := "issue"
+			if isClosedStatus(i.statusID) {
+				resultType = "issue closed"
+			}
+			results = append(results, searchResultData{
+				ID:          i.id,
+				Title:       issueTitle(i),
+				Type:        resultType,
+				URL:         fmt.Sprintf("%s/issues/%d", s.URL, i.id),
+				Description: i.description,
+				Datetime:    i.createdOn,
+			})
+		}
+	}
+	if len(tokens) > 0 && inScope("wiki_pages") {
+		for _, page := range s.wikiPages {
+			content := page.current()
+			texts := []string{page.title}
+			if !titlesOnly {
+				texts = append(texts, content.text)
+			}
+			if !inProject(page.projectID) || !matchTokens(tokens, allWords, texts...) {
+				continue
+			}
+
+			results = append(results, searchResultData{
+				ID:          page.id,
+				Title:       "Wiki: " + page.title,
+				Type:        "wiki-page",
+				URL:         fmt.Sprintf("%s/projects/%s/wiki/%s", s.URL, s.projects[page.projectID].identifier, page.title),
+				Description: content.text,
+				Datetime:    content.updatedOn,
+			})
+		}
+	}
+	if len(tokens) > 0 && inScope("projects") {
+		for _, p := range s.projects {
+			texts := []string{p.name}
+			if !titlesOnly {
+				texts = append(texts, p.description)
+			}
+			if !inProject(p.id) || !matchTokens(tokens, allWords, texts...) {
+				continue
+			}
+
+			results = append(results, searchResultData{
+				ID:          p.id,
+				Title:       "Project: " + p.name,
+				Type:        "project",
+				URL:         fmt.Sprintf("%s/projects/%s", s.URL, p.identifier),
+				Description: p.description,
+				Datetime:    p.createdOn,
+			})
+		}
+	}
+	sort.SliceStable(results, func(x, y int) bool {
+		if !results[x].Datetime.Equal(results[y].Datetime) {
+			return results[x].Datetime.After(results[y].Datetime)
+		}
+		if results[x].Type != results[y].Type {
+			return results[x].Type < results[y].Type
+		}
+		return results[x].ID > results[y].ID
+	})
+
+	renderPage(req, "results", results)
+}
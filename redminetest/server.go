@@ -0,0 +1,490 @@
+// Package redminetest фейковый redmine для тестов без настоящего сервера.
+//
+// NewServer запускает httptest.Server с REST API redmine и хранилищем в памяти:
+//
+//	srv := redminetest.NewServer()
+//	defer srv.Close()
+//	client := srv.Client()
+package redminetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+const (
+	// AdminLogin логин администратора, создаваемого NewServer
+	AdminLogin = "admin"
+	// AdminAPIKey API ключ администратора
+	AdminAPIKey = "redminetest-admin-key"
+
+	defaultLimit = 25
+	maxLimit     = 100
+)
+
+// Server фейковый redmine.
+// Поддерживает аутентификацию по API ключу (X-Redmine-API-Key, параметр key, basic auth),
+// X-Redmine-Switch-User, постраничный вывод (offset, limit, page) и ошибки валидации 422
+type Server struct {
+	*httptest.Server
+
+	mu  sync.Mutex
+	now func() time.Time
+	seq map[string]int
+
+	users        map[int]*user
+	projects     map[int]*project
+	memberships  map[int]*membership
+	versions     map[int]*version
+	categories   map[int]*category
+	issues       map[int]*issue
+	relations    map[int]*relation
+	timeEntries  map[int]*timeEntry
+	wikiPages    map[int]*wikiPage
+	attachments  map[int]*attachment
+	customFields map[int]*redmineclient.RdCustomField
+}
+
+// NewServer запуск фейкового redmine с администратором AdminLogin и справочниками по умолчанию
+func NewServer() *Server {
+	s := &Server{
+		now: func() time.Time {
+			return time.Now().UTC().Truncate(time.Second)
+		},
+		seq:          map[string]int{},
+		users:        map[int]*user{},
+		projects:     map[int]*project{},
+		memberships:  map[int]*membership{},
+		versions:     map[int]*version{},
+		categories:   map[int]*category{},
+		issues:       map[int]*issue{},
+		relations:    map[int]*relation{},
+		timeEntries:  map[int]*timeEntry{},
+		wikiPages:    map[int]*wikiPage{},
+		attachments:  map[int]*attachment{},
+		customFields: map[int]*redmineclient.RdCustomField{},
+	}
+	s.addUser(&user{
+		login:     AdminLogin,
+		firstname: "Redmine",
+		lastname:  "Admin",
+		mail:      "admin@example.net",
+		apiKey:    AdminAPIKey,
+		admin:     true,
+	})
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Client клиент с ключом администратора
+func (s *Server) Client(options ...redmineclient.Option) *redmineclient.ApiRedmineClient {
+	return redmineclient.NewApiRedmineClient(AdminAPIKey, s.URL, options...)
+}
+
+// SetNow источник текущего времени для created_on, updated_on и дат по умолчанию
+func (s *Server) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+}
+
+// AddUser новый активный пользователь, возвращает id и API ключ
+func (s *Server) AddUser(login string, admin bool) (int, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := &user{
+		login:     login,
+		firstname: login,
+		lastname:  "User",
+		mail:      login + "@example.net",
+		admin:     admin,
+	}
+	s.addUser(u)
+
+	return u.id, u.apiKey
+}
+
+// AddCustomField новое настраиваемое поле, возвращает id
+func (s *Server) AddCustomField(field redmineclient.RdCustomField) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if field.CustomizedType == "" {
+		field.CustomizedType = "issue"
+	}
+	if field.FieldFormat == "" {
+		field.FieldFormat = "string"
+	}
+	field.ID = s.nextID("custom_field")
+	s.customFields[field.ID] = &field
+
+	return field.ID
+}
+
+func (s *Server) nextID(kind string) int {
+	s.seq[kind]++
+	return s.seq[kind]
+}
+
+func (s *Server) timeNow() time.Time {
+	return s.now()
+}
+
+func (s *Server) today() string {
+	return s.now().Format(redmineclient.DateFormat)
+}
+
+type request struct {
+	*http.Request
+	w      http.ResponseWriter
+	user   *user
+	params map[string]string
+}
+
+func (req *request) param(name string) string {
+	return req.params[name]
+}
+
+func (req *request) id(name string) int {
+	id, _ := strconv.Atoi(req.params[name])
+	return id
+}
+
+func (req *request) query(name string) string {
+	return req.URL.Query().Get(name)
+}
+
+// includes значения параметра include
+func (req *request) includes() map[string]bool {
+	includes := map[string]bool{}
+	for _, name := range strings.Split(req.query("include"), ",") {
+		includes[strings.TrimSpace(name)] = true
+	}
+
+	return includes
+}
+
+// decode тело запроса вида {"key": {...}}, при ошибке отвечает 400
+func (req *request) decode(key string, value interface{}) bool {
+	body := map[string]json.RawMessage{}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		req.status(http.StatusBadRequest)
+		return false
+	}
+	if data, ok := body[key]; ok {
+		if err := json.Unmarshal(data, value); err != nil {
+			req.status(http.StatusBadRequest)
+			return false
+		}
+	}
+
+	return true
+}
+
+// decodeRaw тело запроса без обёртки, при ошибке отвечает 400
+func (req *request) decodeRaw(value interface{}) bool {
+	if err := json.NewDecoder(req.Body).Decode(value); err != nil {
+		req.status(http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+func (req *request) render(status int, value interface{}) {
+	req.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	req.w.WriteHeader(status)
+	json.NewEncoder(req.w).Encode(value)
+}
+
+func (req *request) status(status int) {
+	req.w.WriteHeader(status)
+}
+
+func (req *request) unprocessable(errs []string) {
+	req.render(http.StatusUnprocessableEntity, map[string][]string{"errors": errs})
+}
+
+// pageBounds offset и limit из параметров запроса
+func (req *request) pageBounds() (int, int) {
+	limit, err := strconv.Atoi(req.query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	offset, err := strconv.Atoi(req.query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+		if page, err := strconv.Atoi(req.query("page")); err == nil && page > 0 {
+			offset = (page - 1) * limit
+		}
+	}
+
+	return offset, limit
+}
+
+// renderPage страница списка items с total_count, offset и limit
+func renderPage[T any](req *request, key string, items []T) {
+	offset, limit := req.pageBounds()
+	total := len(items)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	req.render(http.StatusOK, map[string]interface{}{
+		key:           items[offset:end],
+		"total_count": total,
+		"offset":      offset,
+		"limit":       limit,
+	})
+}
+
+// renderAll список без постраничного вывода, как у справочников redmine
+func renderAll[T any](req *request, key string, items []T) {
+	req.render(http.StatusOK, map[string]interface{}{key: items})
+}
+
+type validation []string
+
+func (v *validation) add(format string, args ...interface{}) {
+	*v = append(*v, fmt.Sprintf(format, args...))
+}
+
+func validDate(value string) bool {
+	_, err := time.Parse(redmineclient.DateFormat, value)
+	return err == nil
+}
+
+// linkData ссылка на сущность в ответе, например {"id": 1, "name": "Bug"}; у родительской задачи только id
+type linkData struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+func link(id int, name string) *linkData {
+	return &linkData{ID: id, Name: name}
+}
+
+// idLink ссылка только с id, nil если id не задан
+func idLink(id int) *linkData {
+	if id == 0 {
+		return nil
+	}
+
+	return &linkData{ID: id}
+}
+
+// stringOrNull необязательное значение для ответа, например дата: пустая строка отдаётся как null
+func stringOrNull(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+// timeOrNull время для ответа, нулевое время отдаётся как null
+func timeOrNull(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+
+	return &value
+}
+
+type route struct {
+	method  string
+	pattern []string
+	raw     bool
+	handler func(req *request)
+}
+
+func newRoute(method, pattern string, handler func(req *request)) route {
+	return route{method: method, pattern: strings.Split(strings.Trim(pattern, "/"), "/"), handler: handler}
+}
+
+func (r route) match(segments []string, params map[string]string) bool {
+	if len(segments) != len(r.pattern) {
+		return false
+	}
+	for i, segment := range r.pattern {
+		if strings.HasPrefix(segment, ":") {
+			params[segment[1:]] = segments[i]
+		} else if segment != segments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Server) routes() []route {
+	download := newRoute(http.MethodGet, "/attachments/download/:id/:filename", s.downloadAttachment)
+	download.raw = true
+
+	return []route{
+		newRoute(http.MethodGet, "/users/current", s.getCurrentUser),
+		newRoute(http.MethodGet, "/users", s.listUsers),
+		newRoute(http.MethodPost, "/users", s.createUser),
+		newRoute(http.MethodGet, "/users/:id", s.getUser),
+		newRoute(http.MethodPut, "/users/:id", s.updateUser),
+		newRoute(http.MethodDelete, "/users/:id", s.deleteUser),
+
+		newRoute(http.MethodGet, "/projects", s.listProjects),
+		newRoute(http.MethodPost, "/projects", s.createProject),
+		newRoute(http.MethodGet, "/projects/:project", s.getProject),
+		newRoute(http.MethodPut, "/projects/:project", s.updateProject),
+		newRoute(http.MethodDelete, "/projects/:project", s.deleteProject),
+
+		newRoute(http.MethodGet, "/projects/:project/memberships", s.listMemberships),
+		newRoute(http.MethodPost, "/projects/:project/memberships", s.createMembership),
+		newRoute(http.MethodGet, "/memberships/:id", s.getMembership),
+		newRoute(http.MethodPut, "/memberships/:id", s.updateMembership),
+		newRoute(http.MethodDelete, "/memberships/:id", s.deleteMembership),
+
+		newRoute(http.MethodGet, "/projects/:project/versions", s.listVersions),
+		newRoute(http.MethodPost, "/projects/:project/versions", s.createVersion),
+		newRoute(http.MethodGet, "/versions/:id", s.getVersion),
+		newRoute(http.MethodPut, "/versions/:id", s.updateVersion),
+		newRoute(http.MethodDelete, "/versions/:id", s.deleteVersion),
+
+		newRoute(http.MethodGet, "/projects/:project/issue_categories", s.listCategories),
+		newRoute(http.MethodPost, "/projects/:project/issue_categories", s.createCategory),
+		newRoute(http.MethodGet, "/issue_categories/:id", s.getCategory),
+		newRoute(http.MethodPut, "/issue_categories/:id", s.updateCategory),
+		newRoute(http.MethodDelete, "/issue_categories/:id", s.deleteCategory),
+
+		newRoute(http.MethodGet, "/issues", s.listIssues),
+		newRoute(http.MethodPost, "/issues", s.createIssue),
+		newRoute(http.MethodGet, "/projects/:project/issues", s.listIssues),
+		newRoute(http.MethodGet, "/issues/:id", s.getIssue),
+		newRoute(http.MethodPut, "/issues/:id", s.updateIssue),
+		newRoute(http.MethodDelete, "/issues/:id", s.deleteIssue),
+		newRoute(http.MethodPost, "/issues/:id/watchers", s.addWatcher),
+		newRoute(http.MethodDelete, "/issues/:id/watchers/:user", s.removeWatcher),
+
+		newRoute(http.MethodGet, "/issues/:id/relations", s.listRelations),
+		newRoute(http.MethodPost, "/issues/:id/relations", s.createRelation),
+		newRoute(http.MethodGet, "/relations/:id", s.getRelation),
+		newRoute(http.MethodDelete, "/relations/:id", s.deleteRelation),
+
+		newRoute(http.MethodGet, "/time_entries", s.listTimeEntries),
+		newRoute(http.MethodPost, "/time_entries", s.createTimeEntry),
+		newRoute(http.MethodGet, "/projects/:project/time_entries", s.listTimeEntries),
+		newRoute(http.MethodGet, "/time_entries/:id", s.getTimeEntry),
+		newRoute(http.MethodPut, "/time_entries/:id", s.updateTimeEntry),
+		newRoute(http.MethodDelete, "/time_entries/:id", s.deleteTimeEntry),
+
+		newRoute(http.MethodGet, "/projects/:project/wiki/index", s.listWikiPages),
+		newRoute(http.MethodGet, "/projects/:project/wiki/:title", s.getWikiPage),
+		newRoute(http.MethodGet, "/projects/:project/wiki/:title/:version", s.getWikiPage),
+		newRoute(http.MethodPut, "/projects/:project/wiki/:title", s.saveWikiPage),
+		newRoute(http.MethodDelete, "/projects/:project/wiki/:title", s.deleteWikiPage),
+
+		newRoute(http.MethodGet, "/issue_statuses", s.listStatuses),
+		newRoute(http.MethodGet, "/trackers", s.listTrackers),
+		newRoute(http.MethodGet, "/enumerations/:name", s.listEnumeration),
+		newRoute(http.MethodGet, "/roles", s.listRoles),
+		newRoute(http.MethodGet, "/roles/:id", s.getRole),
+		newRoute(http.MethodGet, "/custom_fields", s.listCustomFields),
+		newRoute(http.MethodGet, "/queries", s.listQueries),
+
+		newRoute(http.MethodGet, "/search", s.search),
+		newRoute(http.MethodGet, "/projects/:project/search", s.search),
+
+		newRoute(http.MethodPost, "/uploads", s.upload),
+		newRoute(http.MethodGet, "/attachments/:id", s.getAttachment),
+		newRoute(http.MethodDelete, "/attachments/:id", s.deleteAttachment),
+		download,
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.Trim(r.URL.Path, "/")
+	raw := !strings.HasSuffix(path, ".json")
+	segments := strings.Split(strings.TrimSuffix(path, ".json"), "/")
+
+	req := &request{Request: r, w: w, params: map[string]string{}}
+	pathFound := false
+	for _, route := range s.routes() {
+		if route.raw != raw || !route.match(segments, req.params) {
+			continue
+		}
+		pathFound = true
+		if route.method != r.Method {
+			continue
+		}
+
+		status := s.authenticate(req)
+		if status != http.StatusOK {
+			if status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Basic realm="Redmine API"`)
+			}
+			req.status(status)
+			return
+		}
+		route.handler(req)
+		return
+	}
+
+	if pathFound {
+		req.status(http.StatusMethodNotAllowed)
+		return
+	}
+	req.status(http.StatusNotFound)
+}
+
+// authenticate пользователь по API ключу или логину и паролю с учётом X-Redmine-Switch-User
+func (s *Server) authenticate(req *request) int {
+	key := req.Header.Get("X-Redmine-API-Key")
+	if key == "" {
+		key = req.query("key")
+	}
+	login, password, basic := req.BasicAuth()
+
+	var current *user
+	for _, u := range s.users {
+		if !u.active() {
+			continue
+		}
+		if key != "" && u.apiKey == key ||
+			key == "" && basic && (u.apiKey == login || u.login == login && u.password != "" && u.password == password) {
+			current = u
+			break
+		}
+	}
+	if current == nil {
+		return http.StatusUnauthorized
+	}
+
+	if switchLogin := req.Header.Get("X-Redmine-Switch-User"); switchLogin != "" && current.admin {
+		switched := s.userByLogin(switchLogin)
+		if switched == nil || !switched.active() {
+			return http.StatusPreconditionFailed
+		}
+		current = switched
+	}
+	req.user = current
+
+	return http.StatusOK
+}
@@ -0,0 +1,273 @@
+package redminetest
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+const maxHours = 1000
+
+type timeEntry struct {
+	id         int
+	projectID  int
+	issueID    int
+	userID     int
+	activityID int
+	hours      float64
+	comments   string
+	spentOn    string
+	createdOn  time.Time
+	updatedOn  time.Time
+}
+
+type timeEntryData struct {
+	ID        int       `json:"id"`
+	Project   *linkData `json:"project"`
+	Issue     *linkData `json:"issue,omitempty"`
+	User      *linkData `json:"user"`
+	Activity  *linkData `json:"activity"`
+	Hours     float64   `json:"hours"`
+	Comments  string    `json:"comments"`
+	SpentOn   string    `json:"spent_on"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+}
+
+type timeEntryParams struct {
+	ProjectID  *int     `json:"project_id"`
+	IssueID    *int     `json:"issue_id"`
+	UserID     *int     `json:"user_id"`
+	ActivityID *int     `json:"activity_id"`
+	Hours      *float64 `json:"hours"`
+	Comments   *string  `json:"comments"`
+	SpentOn    *string  `json:"spent_on"`
+}
+
+func (params *timeEntryParams) apply(entry *timeEntry) {
+	setInt(&entry.projectID, params.ProjectID)
+	setInt(&entry.issueID, params.IssueID)
+	setInt(&entry.userID, params.UserID)
+	setInt(&entry.activityID, params.ActivityID)
+	if params.Hours != nil {
+		entry.hours = *params.Hours
+	}
+	setString(&entry.comments, params.Comments)
+	setString(&entry.spentOn, params.SpentOn)
+}
+
+func (s *Server) validateTimeEntry(entry *timeEntry) validation {
+	errs := validation{}
+	if entry.issueID != 0 {
+		if i, ok := s.issues[entry.issueID]; !ok || i.projectID != entry.projectID {
+			errs.add("Issue is invalid")
+		}
+	}
+	if _, ok := s.projects[entry.projectID]; !ok {
+		errs.add("Project cannot be blank")
+	}
+	if u, ok := s.users[entry.userID]; !ok || !u.active() {
+		errs.add("User is invalid")
+	}
+	if _, ok := findEnumeration("time_entry_activities", entry.activityID); !ok {
+		errs.add("Activity cannot be blank")
+	}
+	if entry.hours == 0 {
+		errs.add("Hours cannot be blank")
+	} else if entry.hours < 0 || entry.hours > maxHours {
+		errs.add("Hours is invalid")
+	}
+	if entry.spentOn == "" {
+		errs.add("Date cannot be blank")
+	} else if !validDate(entry.spentOn) {
+		errs.add("Date is not a valid date")
+	}
+	if len(entry.comments) > 1024 {
+		errs.add("Comment is too long (maximum is 1024 characters)")
+	}
+
+	return errs
+}
+
+func (s *Server) timeEntryData(entry *timeEntry) timeEntryData {
+	activity, _ := findEnumeration("time_entry_activities", entry.activityID)
+
+	return timeEntryData{
+		ID:        entry.id,
+		Project:   s.projectLink(entry.projectID),
+		Issue:     idLink(entry.issueID),
+		User:      s.userLink(entry.userID),
+		Activity:  link(activity.ID, activity.Name),
+		Hours:     entry.hours,
+		Comments:  entry.comments,
+		SpentOn:   entry.spentOn,
+		CreatedOn: entry.createdOn,
+		UpdatedOn: entry.updatedOn,
+	}
+}
+
+func (s *Server) listTimeEntries(req *request) {
+	query := req.URL.Query()
+	projects := map[int]bool(nil)
+	projectRef := req.param("project")
+	if projectRef == "" {
+		projectRef = query.Get("project_id")
+	}
+	if projectRef != "" {
+		p := s.findProject(projectRef)
+		if p == nil {
+			req.status(http.StatusNotFound)
+			return
+		}
+		projects = s.projectWithSubprojects(p.id)
+	}
+
+	matched := []*timeEntry{}
+	for _, entry := range s.timeEntries {
+		if projects != nil && !projects[entry.projectID] || !s.canView(req.user, s.projects[entry.projectID]) {
+			continue
+		}
+		if filter := query.Get("issue_id"); filter != "" && !matchIDFilter(filter, entry.issueID, req.user.id) {
+			continue
+		}
+		if filter := query.Get("user_id"); filter != "" && !matchIDFilter(filter, entry.userID, req.user.id) {
+			continue
+		}
+		if filter := query.Get("activity_id"); filter != "" && !matchIDFilter(filter, entry.activityID, req.user.id) {
+			continue
+		}
+		if filter := query.Get("spent_on"); filter != "" && !matchDateFilter(filter, parseDate(entry.spentOn)) {
+			continue
+		}
+		if from := query.Get("from"); from != "" && entry.spentOn < from {
+			continue
+		}
+		if to := query.Get("to"); to != "" && entry.spentOn > to {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	sort.Slice(matched, func(x, y int) bool {
+		if matched[x].spentOn != matched[y].spentOn {
+			return matched[x].spentOn > matched[y].spentOn
+		}
+		return matched[x].id > matched[y].id
+	})
+
+	entries := []timeEntryData{}
+	for _, entry := range matched {
+		entries = append(entries, s.timeEntryData(entry))
+	}
+
+	renderPage(req, "time_entries", entries)
+}
+
+func parseDate(value string) time.Time {
+	date, _ := time.Parse(redmineclient.DateFormat, value)
+	return date
+}
+
+// prepareTimeEntry проект из задачи и проверка прав на списание времени
+func (s *Server) prepareTimeEntry(req *request, entry *timeEntry) bool {
+	if i, ok := s.issues[entry.issueID]; ok {
+		if !s.canViewIssue(req.user, i) {
+			req.unprocessable([]string{"Issue is invalid"})
+			return false
+		}
+		entry.projectID = i.projectID
+	}
+	if p, ok := s.projects[entry.projectID]; ok && !s.editableProject(req, p) {
+		return false
+	}
+	if entry.userID != req.user.id && !req.user.admin {
+		req.status(http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) createTimeEntry(req *request) {
+	params := timeEntryParams{}
+	if !req.decode("time_entry", &params) {
+		return
+	}
+	entry := &timeEntry{
+		userID:     req.user.id,
+		activityID: defaultEnumeration("time_entry_activities").ID,
+		spentOn:    s.today(),
+	}
+	params.apply(entry)
+	if !s.prepareTimeEntry(req, entry) {
+		return
+	}
+	if errs := s.validateTimeEntry(entry); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	entry.id = s.nextID("time_entry")
+	entry.createdOn = s.timeNow()
+	entry.updatedOn = entry.createdOn
+	s.timeEntries[entry.id] = entry
+	req.render(http.StatusCreated, map[string]interface{}{"time_entry": s.timeEntryData(entry)})
+}
+
+func (s *Server) pathTimeEntry(req *request) *timeEntry {
+	entry, ok := s.timeEntries[req.id("id")]
+	if !ok {
+		req.status(http.StatusNotFound)
+		return nil
+	}
+	if s.visibleProject(req, entry.projectID) == nil {
+		return nil
+	}
+
+	return entry
+}
+
+func (s *Server) getTimeEntry(req *request) {
+	entry := s.pathTimeEntry(req)
+	if entry == nil {
+		return
+	}
+
+	req.render(http.StatusOK, map[string]interface{}{"time_entry": s.timeEntryData(entry)})
+}
+
+func (s *Server) updateTimeEntry(req *request) {
+	entry := s.pathTimeEntry(req)
+	if entry == nil {
+		return
+	}
+
+	params := timeEntryParams{}
+	if !req.decode("time_entry", &params) {
+		return
+	}
+	updated := *entry
+	params.apply(&updated)
+	if !s.prepareTimeEntry(req, &updated) {
+		return
+	}
+	if errs := s.validateTimeEntry(&updated); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	updated.updatedOn = s.timeNow()
+	*entry = updated
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) deleteTimeEntry(req *request) {
+	entry := s.pathTimeEntry(req)
+	if entry == nil || !s.prepareTimeEntry(req, entry) {
+		return
+	}
+
+	delete(s.timeEntries, entry.id)
+	req.status(http.StatusNoContent)
+}
@@ -0,0 +1,157 @@
+package redminetest
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+)
+
+type attachmentData struct {
+	ID           int       `json:"id"`
+	Filename     string    `json:"filename"`
+	Filesize     int       `json:"filesize"`
+	ContentType  string    `json:"content_type"`
+	Description  string    `json:"description"`
+	ContentURL   string    `json:"content_url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	Author       *linkData `json:"author"`
+	CreatedOn    time.Time `json:"created_on"`
+}
+
+type attachment struct {
+	id          int
+	token       string
+	filename    string
+	contentType string
+	description string
+	content     []byte
+	authorID    int
+	issueID     int
+	createdOn   time.Time
+}
+
+// attach привязка загруженного файла к задаче, токен после этого недействителен
+func (a *attachment) attach(issueID int) {
+	a.issueID = issueID
+	a.token = ""
+}
+
+func (s *Server) attachmentByToken(token string) *attachment {
+	if token == "" {
+		return nil
+	}
+	for _, a := range s.attachments {
+		if a.token == token && a.issueID == 0 {
+			return a
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) attachmentData(a *attachment) attachmentData {
+	return attachmentData{
+		ID:          a.id,
+		Filename:    a.filename,
+		Filesize:    len(a.content),
+		ContentType: a.contentType,
+		Description: a.description,
+		ContentURL:  fmt.Sprintf("%s/attachments/download/%d/%s", s.URL, a.id, a.filename),
+		Author:      s.userLink(a.authorID),
+		CreatedOn:   a.createdOn,
+	}
+}
+
+// upload загрузка файла, возвращает токен для привязки к задаче
+func (s *Server) upload(req *request) {
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType != "application/octet-stream" {
+		req.status(http.StatusNotAcceptable)
+		return
+	}
+	content, err := io.ReadAll(req.Body)
+	if err != nil {
+		req.status(http.StatusBadRequest)
+		return
+	}
+
+	a := &attachment{
+		id:        s.nextID("attachment"),
+		filename:  req.query("filename"),
+		content:   content,
+		authorID:  req.user.id,
+		createdOn: s.timeNow(),
+	}
+	if a.filename == "" {
+		a.filename = "upload"
+	}
+	a.contentType = mime.TypeByExtension(path.Ext(a.filename))
+	a.token = fmt.Sprintf("%d.%s", a.id, newAPIKey())
+	s.attachments[a.id] = a
+
+	req.render(http.StatusCreated, map[string]interface{}{
+		"upload": map[string]interface{}{"id": a.id, "token": a.token},
+	})
+}
+
+// pathAttachment вложение задачи, видимой пользователю
+func (s *Server) pathAttachment(req *request) *attachment {
+	a, ok := s.attachments[req.id("id")]
+	if !ok || a.issueID == 0 {
+		req.status(http.StatusNotFound)
+		return nil
+	}
+	if !s.canViewIssue(req.user, s.issues[a.issueID]) {
+		req.status(http.StatusForbidden)
+		return nil
+	}
+
+	return a
+}
+
+func (s *Server) getAttachment(req *request) {
+	a := s.pathAttachment(req)
+	if a == nil {
+		return
+	}
+
+	req.render(http.StatusOK, map[string]interface{}{"attachment": s.attachmentData(a)})
+}
+
+func (s *Server) deleteAttachment(req *request) {
+	a := s.pathAttachment(req)
+	if a == nil {
+		return
+	}
+	i := s.issues[a.issueID]
+	if !s.editableProject(req, s.projects[i.projectID]) {
+		return
+	}
+
+	attachmentIDs := []int{}
+	for _, id := range i.attachmentIDs {
+		if id != a.id {
+			attachmentIDs = append(attachmentIDs, id)
+		}
+	}
+	i.attachmentIDs = attachmentIDs
+	delete(s.attachments, a.id)
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) downloadAttachment(req *request) {
+	a := s.pathAttachment(req)
+	if a == nil {
+		return
+	}
+
+	contentType := a.contentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.w.Header().Set("Content-Type", contentType)
+	req.w.WriteHeader(http.StatusOK)
+	req.w.Write(a.content)
+}
@@ -0,0 +1,310 @@
+package redminetest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	userActive     = 1
+	userRegistered = 2
+	userLocked     = 3
+
+	minPasswordLength = 8
+)
+
+type user struct {
+	id          int
+	login       string
+	firstname   string
+	lastname    string
+	mail        string
+	password    string
+	apiKey      string
+	admin       bool
+	status      int
+	createdOn   time.Time
+	lastLoginOn time.Time
+}
+
+func (u *user) active() bool {
+	return u.status == userActive
+}
+
+func (u *user) name() string {
+	return u.firstname + " " + u.lastname
+}
+
+type userData struct {
+	ID          int              `json:"id"`
+	Login       string           `json:"login"`
+	Admin       bool             `json:"admin"`
+	Firstname   string           `json:"firstname"`
+	Lastname    string           `json:"lastname"`
+	Mail        string           `json:"mail"`
+	CreatedOn   time.Time        `json:"created_on"`
+	LastLoginOn *time.Time       `json:"last_login_on"`
+	APIKey      string           `json:"api_key,omitempty"`
+	Status      int              `json:"status"`
+	Memberships []membershipData `json:"memberships,omitempty"`
+	Groups      []*linkData      `json:"groups,omitempty"`
+}
+
+type userParams struct {
+	Login     *string `json:"login"`
+	Firstname *string `json:"firstname"`
+	Lastname  *string `json:"lastname"`
+	Mail      *string `json:"mail"`
+	Password  *string `json:"password"`
+	Status    *int    `json:"status"`
+	Admin     *bool   `json:"admin"`
+}
+
+func (params *userParams) apply(u *user) {
+	setString(&u.login, params.Login)
+	setString(&u.firstname, params.Firstname)
+	setString(&u.lastname, params.Lastname)
+	setString(&u.mail, params.Mail)
+	setString(&u.password, params.Password)
+	if params.Status != nil {
+		u.status = *params.Status
+	}
+	if params.Admin != nil {
+		u.admin = *params.Admin
+	}
+}
+
+func setString(field *string, value *string) {
+	if value != nil {
+		*field = strings.TrimSpace(*value)
+	}
+}
+
+func newAPIKey() string {
+	key := make([]byte, 20)
+	rand.Read(key)
+	return hex.EncodeToString(key)
+}
+
+func (s *Server) addUser(u *user) {
+	u.id = s.nextID("user")
+	if u.status == 0 {
+		u.status = userActive
+	}
+	if u.apiKey == "" {
+		u.apiKey = newAPIKey()
+	}
+	u.createdOn = s.timeNow()
+	s.users[u.id] = u
+}
+
+func (s *Server) userByLogin(login string) *user {
+	for _, u := range s.users {
+		if strings.EqualFold(u.login, login) {
+			return u
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) userLink(id int) *linkData {
+	if u, ok := s.users[id]; ok {
+		return link(u.id, u.name())
+	}
+
+	return nil
+}
+
+func (s *Server) validateUser(u *user) validation {
+	errs := validation{}
+	if u.login == "" {
+		errs.add("Login cannot be blank")
+	} else if other := s.userByLogin(u.login); other != nil && other.id != u.id {
+		errs.add("Login has already been taken")
+	}
+	if u.firstname == "" {
+		errs.add("First name cannot be blank")
+	}
+	if u.lastname == "" {
+		errs.add("Last name cannot be blank")
+	}
+	if u.mail == "" {
+		errs.add("Email cannot be blank")
+	} else if !strings.Contains(u.mail, "@") {
+		errs.add("Email is invalid")
+	} else {
+		for _, other := range s.users {
+			if other.id != u.id && strings.EqualFold(other.mail, u.mail) {
+				errs.add("Email has already been taken")
+				break
+			}
+		}
+	}
+	if u.password != "" && len(u.password) < minPasswordLength {
+		errs.add("Password is too short (minimum is %d characters)", minPasswordLength)
+	}
+	if u.status != userActive && u.status != userRegistered && u.status != userLocked {
+		errs.add("Status is not included in the list")
+	}
+
+	return errs
+}
+
+func (s *Server) userData(u *user, withKey bool, includes map[string]bool) userData {
+	data := userData{
+		ID:          u.id,
+		Login:       u.login,
+		Admin:       u.admin,
+		Firstname:   u.firstname,
+		Lastname:    u.lastname,
+		Mail:        u.mail,
+		CreatedOn:   u.createdOn,
+		LastLoginOn: timeOrNull(u.lastLoginOn),
+		Status:      u.status,
+	}
+	if withKey {
+		data.APIKey = u.apiKey
+	}
+	if includes["memberships"] {
+		data.Memberships = []membershipData{}
+		for _, m := range s.sortedMemberships() {
+			if m.userID == u.id {
+				data.Memberships = append(data.Memberships, s.membershipData(m))
+			}
+		}
+	}
+	if includes["groups"] {
+		data.Groups = []*linkData{}
+	}
+
+	return data
+}
+
+func (s *Server) getCurrentUser(req *request) {
+	data := s.userData(req.user, true, req.includes())
+	req.render(http.StatusOK, map[string]interface{}{"user": data})
+}
+
+func (s *Server) listUsers(req *request) {
+	if !req.user.admin {
+		req.status(http.StatusForbidden)
+		return
+	}
+
+	status := userActive
+	if value, ok := req.URL.Query()["status"]; ok {
+		status, _ = strconv.Atoi(value[0])
+	}
+	name := strings.ToLower(req.query("name"))
+
+	ids := []int{}
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	users := []userData{}
+	for _, id := range ids {
+		u := s.users[id]
+		if status != 0 && u.status != status {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(u.login+" "+u.name()+" "+u.mail), name) {
+			continue
+		}
+		users = append(users, s.userData(u, false, nil))
+	}
+
+	renderPage(req, "users", users)
+}
+
+func (s *Server) getUser(req *request) {
+	u, ok := s.users[req.id("id")]
+	if !ok || !u.active() && !req.user.admin {
+		req.status(http.StatusNotFound)
+		return
+	}
+
+	self := u.id == req.user.id
+	data := s.userData(u, req.user.admin || self, req.includes())
+	req.render(http.StatusOK, map[string]interface{}{"user": data})
+}
+
+func (s *Server) createUser(req *request) {
+	if !req.user.admin {
+		req.status(http.StatusForbidden)
+		return
+	}
+
+	params := userParams{}
+	if !req.decode("user", &params) {
+		return
+	}
+	u := &user{status: userActive}
+	params.apply(u)
+
+	errs := s.validateUser(u)
+	if u.password == "" {
+		errs.add("Password cannot be blank")
+	}
+	if len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	s.addUser(u)
+	data := s.userData(u, true, nil)
+	req.render(http.StatusCreated, map[string]interface{}{"user": data})
+}
+
+func (s *Server) updateUser(req *request) {
+	u, ok := s.users[req.id("id")]
+	if !ok {
+		req.status(http.StatusNotFound)
+		return
+	}
+	if !req.user.admin {
+		req.status(http.StatusForbidden)
+		return
+	}
+
+	params := userParams{}
+	if !req.decode("user", &params) {
+		return
+	}
+	updated := *u
+	params.apply(&updated)
+	if errs := s.validateUser(&updated); len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	*u = updated
+	req.status(http.StatusNoContent)
+}
+
+func (s *Server) deleteUser(req *request) {
+	u, ok := s.users[req.id("id")]
+	if !ok {
+		req.status(http.StatusNotFound)
+		return
+	}
+	if !req.user.admin {
+		req.status(http.StatusForbidden)
+		return
+	}
+
+	for id, m := range s.memberships {
+		if m.userID == u.id {
+			delete(s.memberships, id)
+		}
+	}
+	delete(s.users, u.id)
+	req.status(http.StatusNoContent)
+}
@@ -0,0 +1,245 @@
+package redminetest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type wikiPage struct {
+	id          int
+	projectID   int
+	title       string
+	parentTitle string
+	versions    []wikiVersion
+	createdOn   time.Time
+}
+
+type wikiVersion struct {
+	version   int
+	authorID  int
+	text      string
+	comments  string
+	updatedOn time.Time
+}
+
+func (page *wikiPage) current() wikiVersion {
+	return page.versions[len(page.versions)-1]
+}
+
+type wikiPageParams struct {
+	Text        *string `json:"text"`
+	Comments    string  `json:"comments"`
+	Version     *int    `json:"version"`
+	ParentTitle *string `json:"parent_title"`
+}
+
+type wikiParent struct {
+	Title string `json:"title"`
+}
+
+type wikiPageData struct {
+	Title     string      `json:"title"`
+	Parent    *wikiParent `json:"parent,omitempty"`
+	Text      string      `json:"text"`
+	Version   int         `json:"version"`
+	Author    *linkData   `json:"author"`
+	Comments  string      `json:"comments"`
+	CreatedOn time.Time   `json:"created_on"`
+	UpdatedOn time.Time   `json:"updated_on"`
+}
+
+type wikiIndexData struct {
+	Title     string      `json:"title"`
+	Parent    *wikiParent `json:"parent,omitempty"`
+	Version   int         `json:"version"`
+	CreatedOn time.Time   `json:"created_on"`
+	UpdatedOn time.Time   `json:"updated_on"`
+}
+
+// wikiTitle заголовок страницы в виде, который хранит redmine
+func wikiTitle(title string) string {
+	title = strings.Join(strings.Fields(title), "_")
+	title = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(",./?;:|", r) {
+			return -1
+		}
+		return r
+	}, title)
+	if first, size := utf8.DecodeRuneInString(title); size > 0 {
+		title = string(unicode.ToUpper(first)) + title[size:]
+	}
+
+	return title
+}
+
+func (s *Server) findWikiPage(projectID int, title string) *wikiPage {
+	title = wikiTitle(title)
+	for _, page := range s.wikiPages {
+		if page.projectID == projectID && strings.EqualFold(page.title, title) {
+			return page
+		}
+	}
+
+	return nil
+}
+
+func parent(title string) *wikiParent {
+	if title == "" {
+		return nil
+	}
+
+	return &wikiParent{Title: title}
+}
+
+func (s *Server) wikiPageData(page *wikiPage, content wikiVersion) wikiPageData {
+	return wikiPageData{
+		Title:     page.title,
+		Parent:    parent(page.parentTitle),
+		Text:      content.text,
+		Version:   content.version,
+		Author:    s.userLink(content.authorID),
+		Comments:  content.comments,
+		CreatedOn: page.createdOn,
+		UpdatedOn: content.updatedOn,
+	}
+}
+
+func (s *Server) listWikiPages(req *request) {
+	p := s.pathProject(req)
+	if p == nil {
+		return
+	}
+
+	pages := []wikiIndexData{}
+	for _, id := range sortedIDs(s.wikiPages) {
+		page := s.wikiPages[id]
+		if page.projectID != p.id {
+			continue
+		}
+		pages = append(pages, wikiIndexData{
+			Title:     page.title,
+			Parent:    parent(page.parentTitle),
+			Version:   page.current().version,
+			CreatedOn: page.createdOn,
+			UpdatedOn: page.current().updatedOn,
+		})
+	}
+
+	renderAll(req, "wiki_pages", pages)
+}
+
+func (s *Server) getWikiPage(req *request) {
+	p := s.pathProject(req)
+	if p == nil {
+		return
+	}
+	page := s.findWikiPage(p.id, req.param("title"))
+	if page == nil {
+		req.status(http.StatusNotFound)
+		return
+	}
+
+	content := page.current()
+	if versionParam := req.param("version"); versionParam != "" {
+		number, _ := strconv.Atoi(versionParam)
+		if number < 1 || number > len(page.versions) {
+			req.status(http.StatusNotFound)
+			return
+		}
+		content = page.versions[number-1]
+	}
+
+	req.render(http.StatusOK, map[string]interface{}{"wiki_page": s.wikiPageData(page, content)})
+}
+
+// saveWikiPage создание или изменение страницы, при устаревшей версии 409 Conflict
+func (s *Server) saveWikiPage(req *request) {
+	p := s.pathProject(req)
+	if p == nil || !s.editableProject(req, p) {
+		return
+	}
+
+	params := wikiPageParams{}
+	if !req.decode("wiki_page", &params) {
+		return
+	}
+	page := s.findWikiPage(p.id, req.param("title"))
+	created := page == nil
+	if created {
+		page = &wikiPage{projectID: p.id, title: wikiTitle(req.param("title")), createdOn: s.timeNow()}
+	} else if params.Version != nil && *params.Version != page.current().version {
+		req.status(http.StatusConflict)
+		return
+	}
+
+	errs := validation{}
+	if page.title == "" {
+		errs.add("Title cannot be blank")
+	}
+	parentTitle := page.parentTitle
+	if params.ParentTitle != nil {
+		parentTitle = ""
+		if *params.ParentTitle != "" {
+			parentPage := s.findWikiPage(p.id, *params.ParentTitle)
+			if parentPage == nil || parentPage == page {
+				errs.add("Parent page is invalid")
+			} else {
+				parentTitle = parentPage.title
+			}
+		}
+	}
+	text := ""
+	if !created {
+		text = page.current().text
+	}
+	if params.Text != nil {
+		text = *params.Text
+	}
+	if len(errs) > 0 {
+		req.unprocessable(errs)
+		return
+	}
+
+	page.parentTitle = parentTitle
+	if created || text != page.current().text {
+		page.versions = append(page.versions, wikiVersion{
+			version:   len(page.versions) + 1,
+			authorID:  req.user.id,
+			text:      text,
+			comments:  params.Comments,
+			updatedOn: s.timeNow(),
+		})
+	}
+	if !created {
+		req.status(http.StatusNoContent)
+		return
+	}
+
+	page.id = s.nextID("wiki_page")
+	s.wikiPages[page.id] = page
+	req.render(http.StatusCreated, map[string]interface{}{"wiki_page": s.wikiPageData(page, page.current())})
+}
+
+func (s *Server) deleteWikiPage(req *request) {
+	p := s.pathProject(req)
+	if p == nil || !s.editableProject(req, p) {
+		return
+	}
+	page := s.findWikiPage(p.id, req.param("title"))
+	if page == nil {
+		req.status(http.StatusNotFound)
+		return
+	}
+
+	for _, child := range s.wikiPages {
+		if child.projectID == p.id && child.parentTitle == page.title {
+			child.parentTitle = ""
+		}
+	}
+	delete(s.wikiPages, page.id)
+	req.status(http.StatusNoContent)
+}
@@ -0,0 +1,261 @@
+package redmineclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// resourcePageSize размер страницы при чтении всего списка, максимум redmine
+const resourcePageSize = 100
+
+// Resource описание ресурса REST API redmine: ключи JSON и шаблоны путей.
+// Все методы клиента построены на нём, новый ресурс объявляется одним значением:
+//
+//	var news = redmineclient.Resource[redmineclient.RdNewsData]{ListKey: "news", CollectionPath: "/news.json"}
+//	items := news.All(client)
+//
+// Тело запроса и ответ оборачиваются в Key, кроме типов с собственными
+// MarshalJSON/UnmarshalJSON (RdIssue, RdUser и т.п.), которые делают это сами.
+// Ошибки, как и у остальных методов клиента, передаются WithErrorHandler и CaptureError,
+// методы Try* кроме того возвращают их; ответ без Key или ListKey считается ошибкой вызова
+type Resource[T any] struct {
+	// Key ключ сущности в запросе и ответе, например "issue"
+	Key string
+	// ListKey ключ списка в ответе, например "issues"
+	ListKey string
+	// Path шаблон пути сущности, %v заменяется идентификатором, например "/issues/%v.json".
+	// Для вложенных сущностей первый %v родитель (см. In), например "/projects/%v/wiki/%v.json"
+	Path string
+	// CollectionPath путь списка и создания, например "/issues.json" или "/projects/%v/versions.json"
+	CollectionPath string
+}
+
+// Page страница списка
+type Page[T any] struct {
+	Items      []T
+	TotalCount int
+	Offset     int
+	Limit      int
+}
+
+// In ресурс вложенного списка или сущности: %v родителя в CollectionPath и Path заменяется parent, например проектом
+func (resource Resource[T]) In(parent interface{}) Resource[T] {
+	if strings.Contains(resource.CollectionPath, "%v") {
+		resource.CollectionPath = fmt.Sprintf(resource.CollectionPath, parent)
+	}
+	if strings.Count(resource.Path, "%v") > 1 {
+		resource.Path = fmt.Sprintf(resource.Path, parent, "%v")
+	}
+
+	return resource
+}
+
+// Get сущность по идентификатору
+func (resource Resource[T]) Get(arc *ApiRedmineClient, id interface{}, filter ...string) *T {
+	entity, _ := resource.TryGet(arc, id, filter...)
+	return entity
+}
+
+// List первая страница списка, параметры filter передаются как есть
+func (resource Resource[T]) List(arc *ApiRedmineClient, filter ...string) []T {
+	items, _ := resource.TryList(arc, filter...)
+	return items
+}
+
+// Page страница списка со смещением offset и размером limit
+func (resource Resource[T]) Page(arc *ApiRedmineClient, offset, limit int, filter ...string) *Page[T] {
+	page, _ := resource.TryPage(arc, offset, limit, filter...)
+	return page
+}
+
+// All все элементы списка, страницы запрашиваются по очереди до total_count.
+// При ошибке возвращаются элементы, прочитанные до неё
+func (resource Resource[T]) All(arc *ApiRedmineClient, filter ...string) []T {
+	items, _ := resource.TryAll(arc, filter...)
+	return items
+}
+
+// Create создание сущности, ответ декодируется в entity
+func (resource Resource[T]) Create(arc *ApiRedmineClient, entity *T) *T {
+	resource.TryCreate(arc, entity)
+
+	return entity
+}
+
+// Update изменение сущности, ответ (если есть) декодируется в entity
+func (resource Resource[T]) Update(arc *ApiRedmineClient, id interface{}, entity *T) *T {
+	resource.TryUpdate(arc, id, entity)
+
+	return entity
+}
+
+// Delete удаление сущности
+func (resource Resource[T]) Delete(arc *ApiRedmineClient, id interface{}) {
+	resource.TryDelete(arc, id)
+}
+
+// TryGet то же, что Get, но возвращает и ошибку. Как и у остальных методов,
+// ошибка также передаётся WithErrorHandler и CaptureError
+func (resource Resource[T]) TryGet(arc *ApiRedmineClient, id interface{}, filter ...string) (*T, error) {
+	return resource.get(arc, id, filter)
+}
+
+// TryList то же, что List, но возвращает и ошибку
+func (resource Resource[T]) TryList(arc *ApiRedmineClient, filter ...string) ([]T, error) {
+	page, err := resource.list(arc, filter)
+	return page.Items, err
+}
+
+// TryPage то же, что Page, но возвращает и ошибку
+func (resource Resource[T]) TryPage(arc *ApiRedmineClient, offset, limit int, filter ...string) (*Page[T], error) {
+	filter = append(filter[:len(filter):len(filter)], "offset="+strconv.Itoa(offset), "limit="+strconv.Itoa(limit))
+	return resource.list(arc, filter)
+}
+
+// TryAll то же, что All, но возвращает и ошибку вместе с элементами, прочитанными до неё
+func (resource Resource[T]) TryAll(arc *ApiRedmineClient, filter ...string) ([]T, error) {
+	return resource.all(arc, filter)
+}
+
+// TryCreate то же, что Create, но возвращает ошибку
+func (resource Resource[T]) TryCreate(arc *ApiRedmineClient, entity *T) error {
+	return resource.create(arc, entity)
+}
+
+// TryUpdate то же, что Update, но возвращает ошибку
+func (resource Resource[T]) TryUpdate(arc *ApiRedmineClient, id interface{}, entity *T) error {
+	return resource.update(arc, id, entity)
+}
+
+// TryDelete то же, что Delete, но возвращает ошибку
+func (resource Resource[T]) TryDelete(arc *ApiRedmineClient, id interface{}) error {
+	return arc.delete(fmt.Sprintf(resource.Path, id))
+}
+
+func (resource Resource[T]) get(arc *ApiRedmineClient, id interface{}, filter []string) (*T, error) {
+	entity := new(T)
+	err := arc.get(resource.withParams(arc, fmt.Sprintf(resource.Path, id), filter), resource.result(entity))
+
+	return entity, err
+}
+
+func (resource Resource[T]) create(arc *ApiRedmineClient, entity *T) error {
+	return arc.post(resource.CollectionPath, resource.body(entity), resource.result(entity))
+}
+
+func (resource Resource[T]) update(arc *ApiRedmineClient, id interface{}, entity *T) error {
+	return arc.put(fmt.Sprintf(resource.Path, id), resource.body(entity), resource.result(entity))
+}
+
+func (resource Resource[T]) all(arc *ApiRedmineClient, filter []string) ([]T, error) {
+	items := []T{}
+	for offset := 0; ; offset += resourcePageSize {
+		pageFilter := append(filter[:len(filter):len(filter)], "offset="+strconv.Itoa(offset), "limit="+strconv.Itoa(resourcePageSize))
+		page, err := resource.list(arc, pageFilter)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, page.Items...)
+		if len(page.Items) < resourcePageSize || len(items) >= page.TotalCount {
+			return items, nil
+		}
+	}
+}
+
+func (resource Resource[T]) withParams(arc *ApiRedmineClient, path string, filter []string) string {
+	if len(filter) == 0 {
+		return path
+	}
+
+	return path + arc.CompileGetParams(filter...)
+}
+
+func (resource Resource[T]) list(arc *ApiRedmineClient, filter []string) (*Page[T], error) {
+	page := &Page[T]{Items: []T{}}
+	err := arc.get(resource.withParams(arc, resource.CollectionPath, filter), &pageResult[T]{key: resource.ListKey, page: page})
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	return page, err
+}
+
+// body тело запроса: сущность в обёртке Key, если она не оборачивает себя сама
+func (resource Resource[T]) body(entity *T) interface{} {
+	if _, ok := interface{}(entity).(json.Marshaler); ok {
+		return entity
+	}
+
+	return &envelope[T]{key: resource.Key, entity: entity}
+}
+
+// result цель декодирования ответа с сущностью в обёртке Key
+func (resource Resource[T]) result(entity *T) interface{} {
+	if _, ok := interface{}(entity).(json.Unmarshaler); ok {
+		return entity
+	}
+
+	return &envelope[T]{key: resource.Key, entity: entity}
+}
+
+// envelope сущность в обёртке вида {"issue": {...}}, декодируется в уже созданную сущность
+type envelope[T any] struct {
+	key    string
+	entity *T
+}
+
+func (wrapper *envelope[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]*T{wrapper.key: wrapper.entity})
+}
+
+func (wrapper *envelope[T]) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	value, ok := fields[wrapper.key]
+	if !ok {
+		return fmt.Errorf("no %q in response", wrapper.key)
+	}
+
+	return json.Unmarshal(value, wrapper.entity)
+}
+
+// pageResult страница списка вида {"issues": [...], "total_count": 1, ...}
+type pageResult[T any] struct {
+	key  string
+	page *Page[T]
+}
+
+func (result *pageResult[T]) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	items, ok := fields[result.key]
+	if !ok {
+		return fmt.Errorf("no %q in response", result.key)
+	}
+	if err := json.Unmarshal(items, &result.page.Items); err != nil {
+		return fmt.Errorf("%v: %w", result.key, err)
+	}
+
+	paging := map[string]*int{
+		"total_count": &result.page.TotalCount,
+		"offset":      &result.page.Offset,
+		"limit":       &result.page.Limit,
+	}
+	for name, value := range paging {
+		raw, ok := fields[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, value); err != nil {
+			return fmt.Errorf("%v: %w", name, err)
+		}
+	}
+
+	return nil
+}
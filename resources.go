@@ -0,0 +1,50 @@
+package redmineclient
+
+// ресурсы REST API redmine, на которых построены методы клиента
+var (
+	users    = Resource[RdUser]{Key: "user", Path: "/users/%v.json", CollectionPath: "/users.json"}
+	userList = Resource[RdUserData]{ListKey: "users", CollectionPath: "/users.json"}
+
+	issues    = Resource[RdIssue]{Key: "issue", Path: "/issues/%v.json", CollectionPath: "/issues.json"}
+	issueData = Resource[RdIssueData]{Key: "issue", ListKey: "issues", Path: "/issues/%v.json", CollectionPath: "/issues.json"}
+
+	issueRelations    = Resource[RdIssueRelation]{Key: "relation", Path: "/relations/%v.json", CollectionPath: "/issues/%v/relations.json"}
+	issueRelationList = Resource[RdIssueRelationData]{ListKey: "relations", CollectionPath: "/issues/%v/relations.json"}
+
+	projects    = Resource[RdProject]{Key: "project", Path: "/projects/%v.json", CollectionPath: "/projects.json"}
+	projectList = Resource[RdProjectData]{ListKey: "projects", CollectionPath: "/projects.json"}
+
+	memberships    = Resource[RdMembership]{Key: "membership", Path: "/memberships/%v.json", CollectionPath: "/projects/%v/memberships.json"}
+	membershipList = Resource[RdMembershipData]{ListKey: "memberships", CollectionPath: "/projects/%v/memberships.json"}
+
+	versions    = Resource[RdVersion]{Key: "version", Path: "/versions/%v.json", CollectionPath: "/projects/%v/versions.json"}
+	versionList = Resource[RdVersionData]{ListKey: "versions", CollectionPath: "/projects/%v/versions.json"}
+
+	issueCategories   = Resource[RdIssueCategory]{Key: "issue_category", Path: "/issue_categories/%v.json", CollectionPath: "/projects/%v/issue_categories.json"}
+	issueCategoryData = Resource[RdIssueCategoryData]{
+		Key:            "issue_category",
+		ListKey:        "issue_categories",
+		Path:           "/issue_categories/%v.json",
+		CollectionPath: "/projects/%v/issue_categories.json",
+	}
+
+	timeEntries          = Resource[RdTimeEntrie]{Key: "time_entry", Path: "/time_entries/%v.json", CollectionPath: "/time_entries.json"}
+	timeEntryList        = Resource[RdTimeEntrieData]{ListKey: "time_entries", CollectionPath: "/time_entries.json"}
+	projectTimeEntryList = Resource[RdTimeEntrieData]{ListKey: "time_entries", CollectionPath: "/projects/%v/time_entries.json"}
+	wikiPages            = Resource[RdWikiPage]{Key: "wiki_page", Path: "%v"}
+	projectWikiPages     = Resource[RdWikiPage]{Key: "wiki_page", Path: "/projects/%v/wiki/%v.json"}
+	attachments          = Resource[RdAttachment]{Key: "attachment", Path: "/attachments/%v.json"}
+	files                = Resource[RdFileData]{ListKey: "files", CollectionPath: "/projects/%v/files.json"}
+	searchResults        = Resource[RdSearchResult]{ListKey: "results", CollectionPath: "/search.json"}
+	projectSearchResults = Resource[RdSearchResult]{ListKey: "results", CollectionPath: "/projects/%v/search.json"}
+	issueStatuses        = Resource[RdIssueStatus]{ListKey: "issue_statuses", CollectionPath: "/issue_statuses.json"}
+	trackers             = Resource[RdTracker]{ListKey: "trackers", CollectionPath: "/trackers.json"}
+	roles                = Resource[RdRole]{Key: "role", ListKey: "roles", Path: "/roles/%v.json", CollectionPath: "/roles.json"}
+	customFields         = Resource[RdCustomField]{ListKey: "custom_fields", CollectionPath: "/custom_fields.json"}
+	queries              = Resource[RdQuery]{ListKey: "queries", CollectionPath: "/queries.json"}
+)
+
+// enumerationList перечисление redmine по имени: issue_priorities, time_entry_activities, document_categories
+func enumerationList(name string) Resource[RdEnumeration] {
+	return Resource[RdEnumeration]{ListKey: name, CollectionPath: "/enumerations/" + name + ".json"}
+}
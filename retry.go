@@ -0,0 +1,211 @@
+package redmineclient
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultRetryAttempts   = 3
+	DefaultRetryMinBackoff = 500 * time.Millisecond
+	DefaultRetryMaxBackoff = 30 * time.Second
+	DefaultMaxRetryAfter   = time.Minute
+)
+
+// DefaultRetryStatuses коды ответа, при которых запрос повторяется
+var DefaultRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy политика повтора неудачных запросов.
+// GET, HEAD, PUT и DELETE повторяются всегда, POST только при RetryPOST
+type RetryPolicy struct {
+	// MaxAttempts количество попыток с учётом первой, по умолчанию DefaultRetryAttempts
+	MaxAttempts int
+	// MinBackoff задержка перед первым повтором, удваивается с каждой попыткой
+	MinBackoff time.Duration
+	// MaxBackoff максимальная задержка между попытками
+	MaxBackoff time.Duration
+	// MaxRetryAfter максимальное ожидание по заголовку Retry-After, при большем значении повтора нет
+	MaxRetryAfter time.Duration
+	// RetryPOST повторять POST запросы (создание сущностей может задублироваться)
+	RetryPOST bool
+	// RetryStatuses коды ответа для повтора, по умолчанию DefaultRetryStatuses
+	RetryStatuses []int
+	// OnAttempt вызывается после каждой попытки
+	OnAttempt func(attempt RetryAttempt)
+}
+
+// RetryAttempt результат попытки выполнения запроса
+type RetryAttempt struct {
+	Method     string
+	Path       string
+	Attempt    int
+	StatusCode int
+	Err        error
+	// Wait задержка перед следующей попыткой, 0 если попытка последняя
+	Wait time.Duration
+}
+
+// NewRetryPolicy политика повтора со значениями по умолчанию
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:   DefaultRetryAttempts,
+		MinBackoff:    DefaultRetryMinBackoff,
+		MaxBackoff:    DefaultRetryMaxBackoff,
+		MaxRetryAfter: DefaultMaxRetryAfter,
+		RetryStatuses: DefaultRetryStatuses,
+	}
+}
+
+func (policy *RetryPolicy) maxAttempts() int {
+	if policy.MaxAttempts <= 0 {
+		return DefaultRetryAttempts
+	}
+
+	return policy.MaxAttempts
+}
+
+func (policy *RetryPolicy) retryMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return policy.RetryPOST
+	}
+
+	return false
+}
+
+func (policy *RetryPolicy) retryStatus(statusCode int) bool {
+	statuses := policy.RetryStatuses
+	if statuses == nil {
+		statuses = DefaultRetryStatuses
+	}
+	for _, status := range statuses {
+		if status == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff задержка перед попыткой attempt+1 с равномерным разбросом
+func (policy *RetryPolicy) backoff(attempt int) time.Duration {
+	minBackoff, maxBackoff := policy.MinBackoff, policy.MaxBackoff
+	if minBackoff <= 0 {
+		minBackoff = DefaultRetryMinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+
+	delay := minBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// retryAfter задержка из заголовка Retry-After (секунды или HTTP дата)
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func (policy *RetryPolicy) roundTrip(base http.RoundTripper, req *http.Request) (*http.Response, error) {
+	canRetry := policy.retryMethod(req.Method) && (req.Body == nil || req.GetBody != nil)
+	maxAttempts := policy.maxAttempts()
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := base.RoundTrip(attemptReq)
+		retry := canRetry && attempt < maxAttempts
+		wait := time.Duration(0)
+		switch {
+		case err != nil:
+			retry = retry && req.Context().Err() == nil
+			wait = policy.backoff(attempt)
+		case policy.retryStatus(resp.StatusCode):
+			wait = policy.backoff(attempt)
+			if after, ok := retryAfter(resp); ok {
+				maxRetryAfter := policy.MaxRetryAfter
+				if maxRetryAfter <= 0 {
+					maxRetryAfter = DefaultMaxRetryAfter
+				}
+				retry = retry && after <= maxRetryAfter
+				wait = after
+			}
+		default:
+			retry = false
+		}
+		if !retry {
+			wait = 0
+		}
+
+		if policy.OnAttempt != nil {
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			policy.OnAttempt(RetryAttempt{
+				Method:     req.Method,
+				Path:       req.URL.Path,
+				Attempt:    attempt,
+				StatusCode: statusCode,
+				Err:        err,
+				Wait:       wait,
+			})
+		}
+		if !retry {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
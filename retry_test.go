@@ -0,0 +1,172 @@
+package redmineclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedTransport отвечает кодами из statuses по очереди, 0 означает сетевую ошибку
+type scriptedTransport struct {
+	statuses   []int
+	retryAfter string
+	requests   []string
+}
+
+func (transport *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+	}
+	transport.requests = append(transport.requests, body)
+
+	status := transport.statuses[0]
+	if len(transport.statuses) > 1 {
+		transport.statuses = transport.statuses[1:]
+	}
+	if status == 0 {
+		return nil, errors.New("connection reset")
+	}
+
+	resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("{}")), Request: req}
+	if transport.retryAfter != "" {
+		resp.Header.Set("Retry-After", transport.retryAfter)
+	}
+
+	return resp, nil
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt int
+		delay   time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{20, time.Second},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 50; i++ {
+			if wait := policy.backoff(test.attempt); wait < test.delay/2 || wait > test.delay {
+				t.Fatalf("backoff(%d) = %v, want %v..%v", test.attempt, wait, test.delay/2, test.delay)
+			}
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		min    time.Duration
+		max    time.Duration
+		ok     bool
+	}{
+		{"missing", "", 0, 0, false},
+		{"seconds", "3", 3 * time.Second, 3 * time.Second, true},
+		{"zero", "0", 0, 0, true},
+		{"negative", "-1", 0, 0, false},
+		{"http date", time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), 8 * time.Second, 10 * time.Second, true},
+		{"past date", "Mon, 02 Jan 2006 15:04:05 GMT", 0, 0, true},
+		{"garbage", "soon", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if test.header != "" {
+				resp.Header.Set("Retry-After", test.header)
+			}
+			wait, ok := retryAfter(resp)
+			if ok != test.ok || wait < test.min || wait > test.max {
+				t.Errorf("retryAfter(%q) = %v %v, want %v..%v %v", test.header, wait, ok, test.min, test.max, test.ok)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		method     string
+		statuses   []int
+		retryAfter string
+		wantStatus int
+		wantErr    bool
+		attempts   int
+	}{
+		{"success", RetryPolicy{}, http.MethodGet, []int{200}, "", 200, false, 1},
+		{"retried until success", RetryPolicy{}, http.MethodGet, []int{503, 502, 200}, "", 200, false, 3},
+		{"attempts exhausted", RetryPolicy{MaxAttempts: 2}, http.MethodGet, []int{503}, "", 503, false, 2},
+		{"network error", RetryPolicy{}, http.MethodDelete, []int{0, 204}, "", 204, false, 2},
+		{"network error exhausted", RetryPolicy{}, http.MethodGet, []int{0}, "", 0, true, 3},
+		{"client error not retried", RetryPolicy{}, http.MethodGet, []int{422, 200}, "", 422, false, 1},
+		{"custom statuses", RetryPolicy{RetryStatuses: []int{500}}, http.MethodGet, []int{503, 200}, "", 503, false, 1},
+		{"put retried with body", RetryPolicy{}, http.MethodPut, []int{429, 200}, "", 200, false, 2},
+		{"post not retried", RetryPolicy{}, http.MethodPost, []int{503, 201}, "", 503, false, 1},
+		{"post retried on request", RetryPolicy{RetryPOST: true}, http.MethodPost, []int{503, 201}, "", 201, false, 2},
+		{"retry after honoured", RetryPolicy{}, http.MethodGet, []int{429, 200}, "0", 200, false, 2},
+		{"retry after too long", RetryPolicy{MaxRetryAfter: time.Second}, http.MethodGet, []int{429, 200}, "120", 429, false, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := test.policy
+			policy.MinBackoff, policy.MaxBackoff = time.Millisecond, time.Millisecond
+			attempts := []RetryAttempt{}
+			policy.OnAttempt = func(attempt RetryAttempt) { attempts = append(attempts, attempt) }
+			transport := &scriptedTransport{statuses: test.statuses, retryAfter: test.retryAfter}
+
+			req, _ := http.NewRequest(test.method, "http://redmine.test/issues.json", strings.NewReader(`{"issue":{}}`))
+			resp, err := policy.roundTrip(transport, req)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error = %v, want error %v", err, test.wantErr)
+			}
+			if resp != nil && resp.StatusCode != test.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+
+			if len(transport.requests) != test.attempts || len(attempts) != test.attempts {
+				t.Fatalf("%d requests, %d OnAttempt calls, want %d", len(transport.requests), len(attempts), test.attempts)
+			}
+			for i, body := range transport.requests {
+				if body != `{"issue":{}}` {
+					t.Errorf("attempt %d body = %q", i+1, body)
+				}
+				if attempts[i].Attempt != i+1 || attempts[i].Method != test.method || attempts[i].Path != "/issues.json" {
+					t.Errorf("OnAttempt %+v", attempts[i])
+				}
+			}
+			if last := attempts[len(attempts)-1]; last.Wait != 0 {
+				t.Errorf("last attempt wait = %v, want 0", last.Wait)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyRoundTripCanceled(t *testing.T) {
+	policy := &RetryPolicy{MinBackoff: time.Hour, MaxBackoff: time.Hour}
+	transport := &scriptedTransport{statuses: []int{503}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://redmine.test/issues.json", nil)
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	if _, err := policy.roundTrip(transport, req); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if len(transport.requests) != 1 {
+		t.Errorf("%d requests, want 1", len(transport.requests))
+	}
+}
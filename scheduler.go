@@ -0,0 +1,288 @@
+package redmineclient
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorkCalendar календарь рабочих дней для планирования
+type WorkCalendar interface {
+	IsWorkingDay(day time.Time) bool
+}
+
+// WeekdayCalendar рабочие дни недели с праздниками
+type WeekdayCalendar struct {
+	// NonWorkingDays выходные дни недели, по умолчанию суббота и воскресенье
+	NonWorkingDays []time.Weekday
+	// Holidays праздничные дни в формате DateFormat
+	Holidays map[string]bool
+}
+
+func (calendar *WeekdayCalendar) IsWorkingDay(day time.Time) bool {
+	if calendar.Holidays[day.Format(DateFormat)] {
+		return false
+	}
+
+	nonWorkingDays := calendar.NonWorkingDays
+	if nonWorkingDays == nil {
+		nonWorkingDays = []time.Weekday{time.Saturday, time.Sunday}
+	}
+	for _, weekday := range nonWorkingDays {
+		if day.Weekday() == weekday {
+			return false
+		}
+	}
+
+	return true
+}
+
+type allDaysCalendar struct{}
+
+func (allDaysCalendar) IsWorkingDay(day time.Time) bool {
+	return true
+}
+
+// nextWorkingDate ближайший рабочий день начиная с date
+func nextWorkingDate(calendar WorkCalendar, date time.Time) time.Time {
+	for i := 0; i < 366 && !calendar.IsWorkingDay(date); i++ {
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return date
+}
+
+// addWorkingDays дата через days рабочих дней после date
+func addWorkingDays(calendar WorkCalendar, date time.Time, days int) time.Time {
+	date = nextWorkingDate(calendar, date)
+	for ; days > 0; days-- {
+		date = nextWorkingDate(calendar, date.AddDate(0, 0, 1))
+	}
+
+	return date
+}
+
+// workingDuration количество рабочих дней после start до due включительно
+func workingDuration(calendar WorkCalendar, start, due time.Time) int {
+	days := 0
+	for date := start.AddDate(0, 0, 1); !date.After(due); date = date.AddDate(0, 0, 1) {
+		if calendar.IsWorkingDay(date) {
+			days++
+		}
+	}
+
+	return days
+}
+
+// RescheduleOptions параметры перепланирования
+type RescheduleOptions struct {
+	// Calendar календарь рабочих дней, по умолчанию все дни рабочие
+	Calendar WorkCalendar
+	// PullEarlier переносить задачи на более ранние даты, если предшественники завершаются раньше
+	PullEarlier bool
+	// Graph ограничения обхода связей
+	Graph *IssueGraphOptions
+}
+
+func (options *RescheduleOptions) calendar() WorkCalendar {
+	if options == nil || options.Calendar == nil {
+		return allDaysCalendar{}
+	}
+
+	return options.Calendar
+}
+
+// ScheduleChange новые даты задачи
+type ScheduleChange struct {
+	IssueID      int
+	Subject      string
+	OldStartDate time.Time
+	OldDueDate   time.Time
+	StartDate    time.Time
+	DueDate      time.Time
+	// Cause задача-предшественник, определившая новую дату начала
+	Cause int
+}
+
+// ScheduleConflict задача, которую нельзя перенести автоматически
+type ScheduleConflict struct {
+	IssueID int
+	Subject string
+	// StartDate дата, раньше которой задача не может начаться
+	StartDate time.Time
+	DueDate   time.Time
+	Cause     int
+	Reason    string
+}
+
+// SchedulePlan план перепланирования цепочки precedes/follows
+type SchedulePlan struct {
+	IssueID int
+	Changes []ScheduleChange
+	// Conflicts задачи, даты которых нужно исправить вручную, ApplySchedulePlan их не меняет
+	Conflicts []ScheduleConflict
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return "-"
+	}
+
+	return date.Format(DateFormat)
+}
+
+// String план в текстовом виде для проверки перед применением
+func (plan *SchedulePlan) String() string {
+	if len(plan.Changes) == 0 && len(plan.Conflicts) == 0 {
+		return fmt.Sprintf("#%d: nothing to reschedule\n", plan.IssueID)
+	}
+
+	builder := &strings.Builder{}
+	for _, change := range plan.Changes {
+		fmt.Fprintf(builder, "#%d %v: %v..%v -> %v..%v (after #%d)\n",
+			change.IssueID, change.Subject,
+			formatDate(change.OldStartDate), formatDate(change.OldDueDate),
+			formatDate(change.StartDate), formatDate(change.DueDate),
+			change.Cause,
+		)
+	}
+	for _, conflict := range plan.Conflicts {
+		fmt.Fprintf(builder, "#%d %v: not moved, %v (start %v, due %v, after #%d)\n",
+			conflict.IssueID, conflict.Subject, conflict.Reason,
+			formatDate(conflict.StartDate), formatDate(conflict.DueDate), conflict.Cause,
+		)
+	}
+
+	return builder.String()
+}
+
+// PlanReschedule расчёт новых дат задач, следующих за issueID по связям precedes/follows
+func (arc *ApiRedmineClient) PlanReschedule(issueID int, options *RescheduleOptions) (*SchedulePlan, error) {
+	graphOptions := &IssueGraphOptions{}
+	if options != nil && options.Graph != nil {
+		*graphOptions = *options.Graph
+	}
+	graphOptions.RelationTypes = []RdRelationType{RelationPrecedes}
+
+	graph, err := arc.GetIssueGraph([]int{issueID}, graphOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return graph.PlanReschedule(issueID, options)
+}
+
+// PlanReschedule расчёт новых дат задач графа, следующих за issueID
+func (graph *IssueGraph) PlanReschedule(issueID int, options *RescheduleOptions) (*SchedulePlan, error) {
+	order, err := graph.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := options.calendar()
+	pullEarlier := options != nil && options.PullEarlier
+	predecessors := map[int][]IssueGraphEdge{}
+	downstream := map[int]bool{issueID: true}
+	for _, edge := range graph.Edges {
+		if edge.Type == RelationPrecedes {
+			predecessors[edge.To] = append(predecessors[edge.To], edge)
+		}
+	}
+
+	startDates := map[int]time.Time{}
+	dueDates := map[int]time.Time{}
+	for id, issue := range graph.Issues {
+		startDates[id] = issue.GetStartDate()
+		dueDates[id] = issue.GetDueDate()
+	}
+
+	plan := &SchedulePlan{IssueID: issueID}
+	for _, id := range order {
+		var soonestStart time.Time
+		cause := 0
+		for _, edge := range predecessors[id] {
+			if !downstream[edge.From] {
+				continue
+			}
+			downstream[id] = true
+
+			date := dueDates[edge.From]
+			if date.IsZero() {
+				date = startDates[edge.From]
+			}
+			if date.IsZero() {
+				continue
+			}
+			date = addWorkingDays(calendar, date, 1+edge.Delay)
+			if date.After(soonestStart) {
+				soonestStart, cause = date, edge.From
+			}
+		}
+
+		startDate := startDates[id]
+		if id == issueID || soonestStart.IsZero() || startDate.Equal(soonestStart) {
+			continue
+		}
+		if !startDate.IsZero() && startDate.After(soonestStart) && !pullEarlier {
+			continue
+		}
+
+		change := ScheduleChange{
+			IssueID:      id,
+			Subject:      graph.Issues[id].Subject,
+			OldStartDate: startDate,
+			OldDueDate:   dueDates[id],
+			StartDate:    nextWorkingDate(calendar, soonestStart),
+			Cause:        cause,
+		}
+		switch {
+		case change.OldDueDate.IsZero():
+		case startDate.IsZero():
+			// длительность неизвестна: срок сохраняется, если он не раньше новой даты начала,
+			// иначе задача попадает в конфликты, а последующие задачи считаются от новой даты начала
+			if change.OldDueDate.Before(change.StartDate) {
+				plan.Conflicts = append(plan.Conflicts, ScheduleConflict{
+					IssueID:   id,
+					Subject:   change.Subject,
+					StartDate: change.StartDate,
+					DueDate:   change.OldDueDate,
+					Cause:     cause,
+					Reason:    "due date is before the earliest start date",
+				})
+				startDates[id] = change.StartDate
+				dueDates[id] = change.StartDate
+				continue
+			}
+			change.DueDate = change.OldDueDate
+		default:
+			duration := workingDuration(calendar, startDate, change.OldDueDate)
+			change.DueDate = addWorkingDays(calendar, change.StartDate, duration)
+		}
+
+		startDates[id] = change.StartDate
+		dueDates[id] = change.DueDate
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	return plan, nil
+}
+
+// ApplySchedulePlan применить план, задачи обновляются в порядке следования.
+// На первой ошибке применение останавливается: возвращаются уже обновлённые задачи и ошибка,
+// остальные изменения плана не выполняются. Конфликты плана не применяются
+func (arc *ApiRedmineClient) ApplySchedulePlan(plan *SchedulePlan) ([]RdIssueData, error) {
+	issues := make([]RdIssueData, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		issue, err := arc.updateIssue(&RdIssue{
+			ID:        change.IssueID,
+			StartDate: change.StartDate,
+			DueDate:   change.DueDate,
+		})
+		if err != nil {
+			return issues, fmt.Errorf("redmineclient: reschedule #%d: %w", change.IssueID, err)
+		}
+		issues = append(issues, *issue)
+	}
+
+	return issues, nil
+}
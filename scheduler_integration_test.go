@@ -0,0 +1,98 @@
+package redmineclient_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+)
+
+func TestPlanAndApplyReschedule(t *testing.T) {
+	_, client, project := newClient(t)
+	ids := createIssues(client, project,
+		[2]string{"2024-03-04", "2024-03-08"},
+		[2]string{"2024-03-07", "2024-03-11"},
+		[2]string{"2024-03-12", "2024-03-12"},
+		[2]string{"2024-03-01", "2024-03-05"},
+	)
+	relate(client, ids[0], redmineclient.RelationPrecedes, ids[1], 0)
+	relate(client, ids[2], redmineclient.RelationFollows, ids[1], 0)
+	// blocks не влияет на даты
+	relate(client, ids[0], redmineclient.RelationBlocks, ids[3], 0)
+
+	options := &redmineclient.RescheduleOptions{Calendar: &redmineclient.WeekdayCalendar{Holidays: map[string]bool{"2024-03-13": true}}}
+	plan, err := client.PlanReschedule(ids[0], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		id         int
+		start, due string
+		cause      int
+	}{
+		{ids[1], "2024-03-11", "2024-03-14", ids[0]},
+		{ids[2], "2024-03-15", "2024-03-15", ids[1]},
+	}
+	if len(plan.Changes) != len(want) {
+		t.Fatalf("plan:\n%v", plan)
+	}
+	for i, change := range plan.Changes {
+		if change.IssueID != want[i].id || !change.StartDate.Equal(date(want[i].start)) || !change.DueDate.Equal(date(want[i].due)) || change.Cause != want[i].cause {
+			t.Errorf("change %d = %+v, want %+v", i, change, want[i])
+		}
+	}
+
+	updated, err := client.ApplySchedulePlan(plan)
+	if err != nil || len(updated) != 2 {
+		t.Fatalf("ApplySchedulePlan() = %d issues, %v", len(updated), err)
+	}
+	for _, expected := range want {
+		issue := client.GetIssue(expected.id)
+		if issue.StartDate != expected.start || issue.DueDate != expected.due {
+			t.Errorf("#%d dates %v..%v, want %v..%v", expected.id, issue.StartDate, issue.DueDate, expected.start, expected.due)
+		}
+	}
+
+	// после применения перепланировать нечего
+	if plan, err := client.PlanReschedule(ids[0], options); err != nil || len(plan.Changes) != 0 {
+		t.Errorf("second plan = %v, %v", plan, err)
+	}
+}
+
+func TestApplySchedulePlanStopsOnError(t *testing.T) {
+	srv, _, project := newClient(t)
+	// ошибка обновления ожидается, клиент без обработчика ошибок теста
+	client := srv.Client()
+	ids := createIssues(client, project, [2]string{"2024-03-01", "2024-03-02"})
+
+	plan := &redmineclient.SchedulePlan{IssueID: ids[0], Changes: []redmineclient.ScheduleChange{
+		{IssueID: ids[0], StartDate: date("2024-03-05"), DueDate: date("2024-03-06")},
+		{IssueID: 999, StartDate: date("2024-03-05")},
+		{IssueID: ids[0], StartDate: date("2024-03-10"), DueDate: date("2024-03-11")},
+	}}
+	updated, err := client.ApplySchedulePlan(plan)
+
+	var apiErr *redmineclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || !strings.Contains(err.Error(), "#999") {
+		t.Errorf("error = %v, want 404 for #999", err)
+	}
+	if len(updated) != 1 || client.GetIssue(ids[0]).StartDate != "2024-03-05" {
+		t.Errorf("updated %d issues, start %v", len(updated), client.GetIssue(ids[0]).StartDate)
+	}
+}
+
+func TestPlanRescheduleCrawlError(t *testing.T) {
+	srv, client, project := newClient(t)
+	ids := createIssues(client, project, [2]string{"2024-03-01", "2024-03-02"})
+
+	failing := srv.Client(redmineclient.WithTransport(&failingTransport{}))
+	plan, err := failing.PlanReschedule(ids[0], nil)
+
+	var apiErr *redmineclient.APIError
+	if !errors.As(err, &apiErr) || plan != nil {
+		t.Errorf("PlanReschedule() = %v, %v, want no plan and *APIError", plan, err)
+	}
+}
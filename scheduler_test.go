@@ -0,0 +1,277 @@
+package redmineclient
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDate(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	date, _ := time.Parse(DateFormat, value)
+
+	return date
+}
+
+func TestWeekdayCalendar(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar *WeekdayCalendar
+		day      string
+		want     bool
+	}{
+		{"monday", &WeekdayCalendar{}, "2024-03-04", true},
+		{"saturday", &WeekdayCalendar{}, "2024-03-09", false},
+		{"sunday", &WeekdayCalendar{}, "2024-03-10", false},
+		{"holiday", &WeekdayCalendar{Holidays: map[string]bool{"2024-03-08": true}}, "2024-03-08", false},
+		{"custom weekend friday", &WeekdayCalendar{NonWorkingDays: []time.Weekday{time.Friday}}, "2024-03-08", false},
+		{"custom weekend saturday", &WeekdayCalendar{NonWorkingDays: []time.Weekday{time.Friday}}, "2024-03-09", true},
+		{"no weekend", &WeekdayCalendar{NonWorkingDays: []time.Weekday{}}, "2024-03-10", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.calendar.IsWorkingDay(testDate(test.day)); got != test.want {
+				t.Errorf("IsWorkingDay(%v) = %v, want %v", test.day, got, test.want)
+			}
+		})
+	}
+}
+
+func TestAddWorkingDays(t *testing.T) {
+	weekdays := &WeekdayCalendar{Holidays: map[string]bool{"2024-03-11": true}}
+	tests := []struct {
+		name     string
+		calendar WorkCalendar
+		date     string
+		days     int
+		want     string
+	}{
+		{"all days", allDaysCalendar{}, "2024-03-08", 3, "2024-03-11"},
+		{"zero days", weekdays, "2024-03-06", 0, "2024-03-06"},
+		{"zero days from weekend", weekdays, "2024-03-09", 0, "2024-03-12"},
+		{"over weekend and holiday", weekdays, "2024-03-08", 1, "2024-03-12"},
+		{"within week", weekdays, "2024-03-04", 4, "2024-03-08"},
+		{"from weekend", weekdays, "2024-03-10", 2, "2024-03-14"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := addWorkingDays(test.calendar, testDate(test.date), test.days); !got.Equal(testDate(test.want)) {
+				t.Errorf("addWorkingDays(%v, %d) = %v, want %v", test.date, test.days, formatDate(got), test.want)
+			}
+		})
+	}
+}
+
+func TestWorkingDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		calendar WorkCalendar
+		start    string
+		due      string
+		want     int
+	}{
+		{"same day", &WeekdayCalendar{}, "2024-03-04", "2024-03-04", 0},
+		{"week", &WeekdayCalendar{}, "2024-03-04", "2024-03-08", 4},
+		{"over weekend", &WeekdayCalendar{}, "2024-03-08", "2024-03-11", 1},
+		{"all days", allDaysCalendar{}, "2024-03-08", "2024-03-11", 3},
+		{"due before start", allDaysCalendar{}, "2024-03-08", "2024-03-01", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := workingDuration(test.calendar, testDate(test.start), testDate(test.due)); got != test.want {
+				t.Errorf("workingDuration(%v, %v) = %d, want %d", test.start, test.due, got, test.want)
+			}
+		})
+	}
+}
+
+func TestIssueGraphPlanReschedule(t *testing.T) {
+	// change ожидаемое изменение: задача, новые даты и предшественник
+	type change struct {
+		id    int
+		start string
+		due   string
+		cause int
+	}
+
+	tests := []struct {
+		name    string
+		graph   *IssueGraph
+		options *RescheduleOptions
+		want    []change
+	}{
+		{
+			"moves successor keeping duration",
+			testGraph([]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "2024-03-05", "2024-03-07"}}, precedes(1, 2, 0)),
+			nil,
+			[]change{{2, "2024-03-11", "2024-03-13", 1}},
+		},
+		{
+			"delay",
+			testGraph([]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "2024-03-05", "2024-03-07"}}, precedes(1, 2, 2)),
+			nil,
+			[]change{{2, "2024-03-13", "2024-03-15", 1}},
+		},
+		{
+			"already in place",
+			testGraph([]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "2024-03-11", "2024-03-12"}}, precedes(1, 2, 0)),
+			nil,
+			nil,
+		},
+		{
+			"later successor kept",
+			testGraph([]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "2024-03-20", "2024-03-21"}}, precedes(1, 2, 0)),
+			nil,
+			nil,
+		},
+		{
+			"later successor pulled earlier",
+			testGraph([]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "2024-03-20", "2024-03-21"}}, precedes(1, 2, 0)),
+			&RescheduleOptions{PullEarlier: true},
+			[]change{{2, "2024-03-11", "2024-03-12", 1}},
+		},
+		{
+			"working days",
+			testGraph([]testIssue{{1, "2024-03-04", "2024-03-08"}, {2, "2024-03-07", "2024-03-11"}}, precedes(1, 2, 0)),
+			&RescheduleOptions{Calendar: &WeekdayCalendar{}},
+			[]change{{2, "2024-03-11", "2024-03-13", 1}},
+		},
+		{
+			"chain",
+			testGraph(
+				[]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "2024-03-05", "2024-03-06"}, {3, "2024-03-07", "2024-03-07"}},
+				precedes(1, 2, 0), precedes(2, 3, 0),
+			),
+			nil,
+			[]change{{2, "2024-03-11", "2024-03-12", 1}, {3, "2024-03-13", "2024-03-13", 2}},
+		},
+		{
+			"latest predecessor wins",
+			testGraph(
+				[]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "2024-03-01", "2024-03-05"}, {3, "2024-03-01", "2024-03-01"}},
+				precedes(1, 3, 0), precedes(1, 2, 0), precedes(2, 3, 0),
+			),
+			nil,
+			[]change{{2, "2024-03-11", "2024-03-15", 1}, {3, "2024-03-16", "2024-03-16", 2}},
+		},
+		{
+			"predecessor outside chain ignored",
+			testGraph(
+				[]testIssue{{1, "2024-03-01", "2024-03-02"}, {2, "2024-03-01", "2024-03-01"}, {5, "2024-03-01", "2024-03-20"}},
+				precedes(1, 2, 0), precedes(5, 1, 0),
+			),
+			nil,
+			[]change{{2, "2024-03-03", "2024-03-03", 1}},
+		},
+		{
+			"successor without start keeps due date",
+			testGraph([]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "", "2024-03-20"}}, precedes(1, 2, 0)),
+			nil,
+			[]change{{2, "2024-03-11", "2024-03-20", 1}},
+		},
+		{
+			"successor without dates",
+			testGraph([]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "", ""}}, precedes(1, 2, 0)),
+			nil,
+			[]change{{2, "2024-03-11", "", 1}},
+		},
+		{
+			"predecessor without due uses start",
+			testGraph([]testIssue{{1, "2024-03-01", ""}, {2, "", ""}}, precedes(1, 2, 0)),
+			nil,
+			[]change{{2, "2024-03-02", "", 1}},
+		},
+		{
+			"blocks ignored",
+			testGraph([]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "2024-03-05", "2024-03-07"}}, blocks(1, 2)),
+			nil,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan, err := test.graph.PlanReschedule(1, test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []change
+			for _, planned := range plan.Changes {
+				got = append(got, change{planned.IssueID, formatDate(planned.StartDate), formatDate(planned.DueDate), planned.Cause})
+			}
+			var want []change
+			for _, expected := range test.want {
+				if expected.due == "" {
+					expected.due = "-"
+				}
+				want = append(want, expected)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("plan = %+v, want %+v\n%v", got, want, plan)
+			}
+		})
+	}
+}
+
+func TestIssueGraphPlanRescheduleConflict(t *testing.T) {
+	// у задачи 2 нет даты начала, а срок раньше окончания предшественника
+	graph := testGraph(
+		[]testIssue{{1, "2024-03-01", "2024-03-10"}, {2, "", "2024-03-05"}, {3, "2024-03-06", "2024-03-07"}},
+		precedes(1, 2, 0), precedes(2, 3, 0),
+	)
+
+	plan, err := graph.PlanReschedule(1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Conflicts) != 1 {
+		t.Fatalf("conflicts = %+v, want one", plan.Conflicts)
+	}
+	conflict := plan.Conflicts[0]
+	if conflict.IssueID != 2 || conflict.Cause != 1 || formatDate(conflict.StartDate) != "2024-03-11" || formatDate(conflict.DueDate) != "2024-03-05" {
+		t.Errorf("conflict = %+v", conflict)
+	}
+	// последующая задача переносится от самой ранней даты начала конфликтной задачи
+	if len(plan.Changes) != 1 || plan.Changes[0].IssueID != 3 || formatDate(plan.Changes[0].StartDate) != "2024-03-12" {
+		t.Errorf("changes = %+v", plan.Changes)
+	}
+	if want := "#2 : not moved, due date is before the earliest start date (start 2024-03-11, due 2024-03-05, after #1)\n"; !strings.HasSuffix(plan.String(), want) {
+		t.Errorf("String() = %q, want suffix %q", plan.String(), want)
+	}
+}
+
+func TestIssueGraphPlanRescheduleCycle(t *testing.T) {
+	graph := testGraph(ids(1, 2), precedes(1, 2, 0), precedes(2, 1, 0))
+
+	var cycleErr *CycleError
+	if _, err := graph.PlanReschedule(1, nil); !errors.As(err, &cycleErr) {
+		t.Errorf("error = %v, want *CycleError", err)
+	}
+}
+
+func TestSchedulePlanString(t *testing.T) {
+	plan := &SchedulePlan{IssueID: 1}
+	if got := plan.String(); got != "#1: nothing to reschedule\n" {
+		t.Errorf("String() = %q", got)
+	}
+
+	plan.Changes = []ScheduleChange{{
+		IssueID:    2,
+		Subject:    "build",
+		OldDueDate: testDate("2024-03-07"),
+		StartDate:  testDate("2024-03-11"),
+		DueDate:    testDate("2024-03-13"),
+		Cause:      1,
+	}}
+	if got, want := plan.String(), "#2 build: -..2024-03-07 -> 2024-03-11..2024-03-13 (after #1)\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+package redmineclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const switchUserHeader = "X-Redmine-Switch-User"
+
+// requestScope параметры запросов копии клиента
+type requestScope struct {
+	ctx           context.Context
+	switchUser    string
+	headers       http.Header
+	query         url.Values
+	include       []string
+	authenticator Authenticator
+	// authLogin логин владельца authenticator для журнала изменений, общий для копий с тем же Auth
+	authLogin *cachedLogin
+	err       *error
+	undoBatch string
+	skipUndo  bool
+
+	expectUpdatedOn time.Time
+	expectVersion   int
+	versions        *versionTracker
+}
+
+func (scope requestScope) clone() requestScope {
+	scope.headers = scope.headers.Clone()
+	if scope.query != nil {
+		query := url.Values{}
+		for name, values := range scope.query {
+			query[name] = append([]string{}, values...)
+		}
+		scope.query = query
+	}
+	scope.include = append([]string{}, scope.include...)
+
+	return scope
+}
+
+// captureError сохранение первой ошибки запросов копии клиента
+func (scope *requestScope) captureError(err error) {
+	if scope.err != nil && *scope.err == nil {
+		*scope.err = err
+	}
+}
+
+type requestScopeKey struct{}
+
+// scopeFromContext параметры копии клиента, выполняющей запрос
+func scopeFromContext(ctx context.Context) *requestScope {
+	scope, _ := ctx.Value(requestScopeKey{}).(*requestScope)
+	return scope
+}
+
+// apply копия запроса с заголовками и параметрами копии клиента
+func (scope *requestScope) apply(req *http.Request) *http.Request {
+	req = req.Clone(req.Context())
+	for name, values := range scope.headers {
+		req.Header[name] = values
+	}
+	if scope.switchUser != "" {
+		req.Header.Set(switchUserHeader, scope.switchUser)
+	}
+	scope.applyQuery(req.URL)
+
+	return req
+}
+
+// applyQuery добавление параметров копии клиента к адресу запроса
+func (scope *requestScope) applyQuery(requestURL *url.URL) {
+	if len(scope.query) == 0 && len(scope.include) == 0 {
+		return
+	}
+
+	query := requestURL.Query()
+	for name, values := range scope.query {
+		query[name] = values
+	}
+	if len(scope.include) > 0 {
+		include := scope.include
+		if current := query.Get("include"); current != "" {
+			include = append(strings.Split(current, ","), include...)
+		}
+		query.Set("include", strings.Join(include, ","))
+	}
+	requestURL.RawQuery = query.Encode()
+}
+
+// withScope копия клиента со своими параметрами запросов. Копия использует http клиент
+// и транспорт исходного клиента, параметры передаются транспорту в контексте запроса
+func (arc *ApiRedmineClient) withScope(update func(scope *requestScope)) *ApiRedmineClient {
+	scoped := *arc
+	scoped.scope = arc.scope.clone()
+	update(&scoped.scope)
+
+	return &scoped
+}
+
+// RequestOption параметр запросов копии клиента
+type RequestOption func(scope *requestScope)
+
+// With копия клиента с параметрами запросов options, подходит для любых методов клиента:
+//
+//	client.With(redmineclient.Include("relations"), redmineclient.Locale("ru")).GetIssue(id)
+//
+// Исходный клиент не изменяется, поэтому копию безопасно создавать в параллельных горутинах.
+// Параметры применяются к методам клиента, но не к запросам встроенного ApiHTTPClient
+func (arc *ApiRedmineClient) With(options ...RequestOption) *ApiRedmineClient {
+	return arc.withScope(func(scope *requestScope) {
+		for _, option := range options {
+			option(scope)
+		}
+	})
+}
+
+// WithContext копия клиента, запросы которой выполняются с контекстом ctx.
+// Отмена контекста прерывает запрос, ожидание лимитов и паузы между повторами
+func (arc *ApiRedmineClient) WithContext(ctx context.Context) *ApiRedmineClient {
+	return arc.With(Context(ctx))
+}
+
+// As копия клиента, выполняющая запросы от имени пользователя login (X-Redmine-Switch-User).
+// Копия использует общий пул соединений, заголовок добавляется только к её запросам.
+// Требует ключа администратора, при отказе redmine запрос завершается с *ImpersonationError
+func (arc *ApiRedmineClient) As(login string) *ApiRedmineClient {
+	return arc.With(SwitchUser(login))
+}
+
+// Context контекст запросов
+func Context(ctx context.Context) RequestOption {
+	return func(scope *requestScope) {
+		scope.ctx = ctx
+	}
+}
+
+// SwitchUser запросы от имени пользователя login
+func SwitchUser(login string) RequestOption {
+	return func(scope *requestScope) {
+		scope.switchUser = login
+	}
+}
+
+// Header заголовок запросов
+func Header(name, value string) RequestOption {
+	return func(scope *requestScope) {
+		if scope.headers == nil {
+			scope.headers = http.Header{}
+		}
+		scope.headers.Set(name, value)
+	}
+}
+
+// Locale язык ответов redmine
+func Locale(locale string) RequestOption {
+	return Header("Accept-Language", locale)
+}
+
+// Query параметр запросов, заменяет одноимённый параметр метода
+func Query(name, value string) RequestOption {
+	return func(scope *requestScope) {
+		if scope.query == nil {
+			scope.query = url.Values{}
+		}
+		scope.query.Set(name, value)
+	}
+}
+
+// Include дополнительные данные ответа (include=relations,children,...),
+// добавляются к запрашиваемым методом
+func Include(values ...string) RequestOption {
+	return func(scope *requestScope) {
+		scope.include = append(scope.include, values...)
+	}
+}
+
+// Auth аутентификация запросов вместо заданной при создании клиента
+func Auth(authenticator Authenticator) RequestOption {
+	return func(scope *requestScope) {
+		scope.authenticator = authenticator
+		scope.authLogin = &cachedLogin{}
+	}
+}
+
+// Key аутентификация запросов ключом API в параметре key
+func Key(key string) RequestOption {
+	return Auth(APIKeyQuery(key))
+}
+
+// CaptureError сохраняет в err первую ошибку запросов копии клиента (*APIError, сетевые ошибки).
+// Копию с CaptureError не следует использовать из нескольких горутин
+func CaptureError(err *error) RequestOption {
+	return func(scope *requestScope) {
+		scope.err = err
+	}
+}
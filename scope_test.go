@@ -0,0 +1,113 @@
+package redmineclient_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+func TestAs(t *testing.T) {
+	srv, client, _ := newClient(t)
+	srv.AddUser("jsmith", false)
+
+	if user := client.As("jsmith").GetCurrentUser(); user.Login != "jsmith" {
+		t.Errorf("As(jsmith) current user %q", user.Login)
+	}
+	if user := client.GetCurrentUser(); user.Login != redminetest.AdminLogin {
+		t.Errorf("parent client current user %q, want %q", user.Login, redminetest.AdminLogin)
+	}
+}
+
+func TestAsRejected(t *testing.T) {
+	srv := redminetest.NewServer()
+	defer srv.Close()
+
+	var handled error
+	client := srv.Client(redmineclient.WithErrorHandler(func(err error) { handled = err }))
+
+	var err error
+	user := client.As("nobody").With(redmineclient.CaptureError(&err)).GetCurrentUser()
+	var impersonationErr *redmineclient.ImpersonationError
+	if !errors.As(err, &impersonationErr) || impersonationErr.Login != "nobody" {
+		t.Fatalf("error = %v, want *ImpersonationError for nobody", err)
+	}
+	if !errors.As(handled, &impersonationErr) {
+		t.Errorf("error handler got %v, want *ImpersonationError", handled)
+	}
+	if user.ID != 0 {
+		t.Errorf("user = %+v, want empty", user)
+	}
+}
+
+func TestAsWithoutAdminKey(t *testing.T) {
+	srv := redminetest.NewServer()
+	defer srv.Close()
+	_, key := srv.AddUser("jsmith", false)
+	srv.AddUser("jdoe", false)
+
+	// redmine игнорирует X-Redmine-Switch-User для ключа не администратора
+	client := redmineclient.NewApiRedmineClient(key, srv.URL)
+	if user := client.As("jdoe").GetCurrentUser(); user.Login != "jsmith" {
+		t.Errorf("current user %q, want jsmith", user.Login)
+	}
+}
+
+func TestWithRequestOptions(t *testing.T) {
+	var requests []*http.Request
+	record := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			return next.RoundTrip(req)
+		})
+	}
+	_, client, project := newClient(t, redmineclient.WithSendMiddleware(record))
+	issue := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "scoped"})
+
+	tests := []struct {
+		name    string
+		options []redmineclient.RequestOption
+		check   func(req *http.Request) bool
+	}{
+		{"header", []redmineclient.RequestOption{redmineclient.Header("X-Request-Id", "42")}, func(req *http.Request) bool {
+			return req.Header.Get("X-Request-Id") == "42"
+		}},
+		{"locale", []redmineclient.RequestOption{redmineclient.Locale("ru")}, func(req *http.Request) bool {
+			return req.Header.Get("Accept-Language") == "ru"
+		}},
+		{"switch user", []redmineclient.RequestOption{redmineclient.SwitchUser(redminetest.AdminLogin)}, func(req *http.Request) bool {
+			return req.Header.Get("X-Redmine-Switch-User") == redminetest.AdminLogin
+		}},
+		{"query", []redmineclient.RequestOption{redmineclient.Query("status_id", "*")}, func(req *http.Request) bool {
+			return req.URL.Query().Get("status_id") == "*"
+		}},
+		// GetIssue запрашивает include=journals,attachments, Include дополняет список
+		{"include", []redmineclient.RequestOption{redmineclient.Include("relations"), redmineclient.Include("watchers")}, func(req *http.Request) bool {
+			return req.URL.Query().Get("include") == "journals,attachments,relations,watchers"
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests = nil
+			scoped := client.With(test.options...)
+			if got := scoped.GetIssue(issue.ID); got.ID != issue.ID {
+				t.Fatalf("GetIssue(%d) = %+v", issue.ID, got)
+			}
+			if len(requests) != 1 || !test.check(requests[0]) {
+				t.Fatalf("requests %+v", requests)
+			}
+
+			requests = nil
+			client.GetIssue(issue.ID)
+			if len(requests) != 1 || test.check(requests[0]) {
+				t.Errorf("options applied to the parent client: %v %v", requests[0].URL, requests[0].Header)
+			}
+			if scoped.ApiHTTPClient != client.ApiHTTPClient {
+				t.Error("scoped client does not reuse the parent http client")
+			}
+		})
+	}
+}
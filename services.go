@@ -0,0 +1,134 @@
+package redmineclient
+
+// UserService пользователи
+type UserService interface {
+	GetCurrentUser() *RdUser
+	GetUser(id int) *RdUser
+	CreateUser(user *RdUser) *RdUser
+	UpdateUser(user *RdUser) *RdUser
+	DeleteUser(id int)
+	GetUserList(filter ...string) []RdUserData
+}
+
+// IssueService задачи
+type IssueService interface {
+	GetIssue(id int) *RdIssueData
+	CreateIssue(issue *RdIssue) *RdIssue
+	CreateIssueIdempotent(issue *RdIssue, key string, options *DedupeOptions) (*RdIssue, bool, error)
+	UpdateIssue(issue *RdIssue) *RdIssueData
+	DeleteIssue(id int)
+	GetListIssue(filter ...string) []RdIssueData
+	GetListIssueByProject(projectID, statusID int) []RdIssueData
+	GetMyListIssueByProject(projectID, statusID int) []RdIssueData
+}
+
+// IssueRelationService связи между задачами
+type IssueRelationService interface {
+	GetIssueRelation(id int) *RdIssueRelation
+	CreateIssueRelation(relation *RdIssueRelation) *RdIssueRelation
+	UpdateIssueRelation(relation *RdIssueRelation) *RdIssueRelation
+	DeleteIssueRelation(id int)
+	GetIssueRelationList(id int) []RdIssueRelationData
+}
+
+// ProjectService проекты
+type ProjectService interface {
+	GetProject(id int) *RdProject
+	GetProjectByCode(code string) *RdProject
+	CreateProject(project *RdProject) *RdProject
+	UpdateProject(project *RdProject) *RdProject
+	DeleteProject(id int)
+	GetProjectList(filter ...string) []RdProjectData
+}
+
+// MembershipService участники проектов
+type MembershipService interface {
+	GetMembership(id int) *RdMembership
+	CreateMembership(membership *RdMembership) *RdMembership
+	UpdateMembership(membership *RdMembership) *RdMembership
+	DeleteMembership(id int)
+	GetMembershipList(projectID int) []RdMembershipData
+	GetMembershipListByCode(projectCode string) []RdMembershipData
+}
+
+// VersionService версии проектов
+type VersionService interface {
+	GetVersion(id int) *RdVersion
+	CreateVersion(version *RdVersion) *RdVersion
+	UpdateVersion(version *RdVersion) *RdVersion
+	DeleteVersion(id int)
+	GetVersionList(projectID int) []RdVersionData
+	GetVersionByProjectList(projectCode string) []RdVersionData
+}
+
+// IssueCategoryService категории задач
+type IssueCategoryService interface {
+	GetIssueCategory(id int) *RdIssueCategoryData
+	CreateIssueCategory(issueCategory *RdIssueCategory) *RdIssueCategory
+	UpdateIssueCategory(issueCategory *RdIssueCategory) *RdIssueCategoryData
+	DeleteIssueCategory(id int)
+	GetListIssueCategory(projectID int) []RdIssueCategoryData
+	GetListIssueCategoryByProjectCode(projectCode string) []RdIssueCategoryData
+}
+
+// TimeEntryService трудозатраты
+type TimeEntryService interface {
+	GetTimeEntrie(id int) *RdTimeEntrie
+	CreateTimeEntrie(timeEntrie *RdTimeEntrie) *RdTimeEntrie
+	UpdateTimeEntrie(timeEntrie *RdTimeEntrie) *RdTimeEntrie
+	DeleteTimeEntrie(id int)
+	GetListTimeEntrie(filter ...string) []RdTimeEntrieData
+	GetListTimeEntrieByProject(projectID int, filter ...string) []RdTimeEntrieData
+	GetListTimeEntrieByProjectCode(projectCode string, filter ...string) []RdTimeEntrieData
+}
+
+// WikiService страницы wiki
+type WikiService interface {
+	GetWikiPage(url string) *RdWikiPage
+	CreateWikiPage(wikiPage *RdWikiPage, url string) *RdWikiPage
+	UpdateWikiPage(wikiPage *RdWikiPage, url string) *RdWikiPage
+	DeleteWikiPage(project interface{}, title string)
+}
+
+// FileService вложения и файлы проектов
+type FileService interface {
+	GetAttachment(id int) *RdAttachment
+	GetListFile(projectID int) []RdFileData
+	GetListFileByProjectCode(projectCode string) []RdFileData
+}
+
+// SearchService поиск
+type SearchService interface {
+	Search(query string, filter ...string) []RdSearchResult
+	SearchByProject(projectID int, query string, filter ...string) []RdSearchResult
+	SearchByProjectCode(projectCode string, query string, filter ...string) []RdSearchResult
+}
+
+// ReferenceService справочники: статусы, трекеры, перечисления, роли, настраиваемые поля, запросы
+type ReferenceService interface {
+	GetListStatusIssue() []RdIssueStatus
+	GetListTracker() []RdTracker
+	GetListEnumeration(listName string) []RdEnumeration
+	GetRole(id int) *RdRole
+	GetListRole() []RdRole
+	GetListCustomField() []RdCustomField
+	GetListQueries() []RdQuery
+}
+
+// Client все ресурсы redmine, реализуется ApiRedmineClient
+type Client interface {
+	UserService
+	IssueService
+	IssueRelationService
+	ProjectService
+	MembershipService
+	VersionService
+	IssueCategoryService
+	TimeEntryService
+	WikiService
+	FileService
+	SearchService
+	ReferenceService
+}
+
+var _ Client = (*ApiRedmineClient)(nil)
@@ -0,0 +1,161 @@
+package redmineclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tracer создание спанов, совместим по смыслу с trace.Tracer из OpenTelemetry
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span спан вызова API
+type Span interface {
+	SetAttributes(attributes ...SpanAttribute)
+	RecordError(err error)
+	End()
+}
+
+// SpanAttribute атрибут спана
+type SpanAttribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Propagator передача контекста трассировки в заголовках запроса (например traceparent).
+// Если Tracer реализует Propagator, заголовки добавляются к каждому запросу
+type Propagator interface {
+	Inject(ctx context.Context, header http.Header)
+}
+
+// NoopTracer трассировщик, который ничего не делает
+type NoopTracer struct{}
+
+func (NoopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attributes ...SpanAttribute) {}
+func (noopSpan) RecordError(err error)                     {}
+func (noopSpan) End()                                      {}
+
+// requestStats попытки, код ответа и время сервера (заголовок Date) запроса, собираются транспортом
+type requestStats struct {
+	attempts   int
+	statusCode int
+	date       time.Time
+}
+
+type requestStatsKey struct{}
+
+func statsFromContext(ctx context.Context) *requestStats {
+	stats, _ := ctx.Value(requestStatsKey{}).(*requestStats)
+	return stats
+}
+
+// callStats статистика запроса вызова, при отсутствии добавляется в контекст вызова
+func callStats(call *Call) *requestStats {
+	stats := statsFromContext(call.Context)
+	if stats == nil {
+		stats = &requestStats{}
+		call.Context = context.WithValue(call.Context, requestStatsKey{}, stats)
+	}
+
+	return stats
+}
+
+// WithTracer спан на каждый вызов API с контекстом из WithContext/Context.
+// Без трассировщика обёртка не добавляется
+func WithTracer(tracer Tracer) Option {
+	return func(arc *ApiRedmineClient) error {
+		if tracer == nil {
+			return nil
+		}
+		arc.transport.callMiddleware = append(arc.transport.callMiddleware, tracingMiddleware(tracer))
+		if propagator, ok := tracer.(Propagator); ok {
+			arc.transport.sendMiddleware = append(arc.transport.sendMiddleware, propagationMiddleware(propagator))
+		}
+		return nil
+	}
+}
+
+func tracingMiddleware(tracer Tracer) CallMiddleware {
+	return func(next CallHandler) CallHandler {
+		return func(call *Call) {
+			path := call.Path
+			if parsedURL, err := url.Parse(call.Path); err == nil {
+				path = parsedURL.Path
+			}
+			route := RouteTemplate(path)
+
+			ctx, span := tracer.Start(call.Context, "redmine "+call.Method+" "+route)
+			stats := &requestStats{}
+			call.Context = context.WithValue(ctx, requestStatsKey{}, stats)
+			next(call)
+
+			attributes := []SpanAttribute{
+				{Key: "http.request.method", Value: call.Method},
+				{Key: "http.route", Value: route},
+				{Key: "http.response.status_code", Value: stats.statusCode},
+				{Key: "redmine.retry.count", Value: retryCount(stats.attempts)},
+			}
+			attributes = append(attributes, entityAttributes(path, call.Body)...)
+			if call.Err != nil {
+				attributes = append(attributes, SpanAttribute{Key: "error.type", Value: errorKind(call.Err)})
+			}
+			span.SetAttributes(attributes...)
+			if call.Err != nil {
+				span.RecordError(call.Err)
+			}
+			span.End()
+		}
+	}
+}
+
+func retryCount(attempts int) int {
+	if attempts > 1 {
+		return attempts - 1
+	}
+
+	return 0
+}
+
+// entityAttributes идентификаторы задачи и проекта по пути и телу запроса
+func entityAttributes(path string, body interface{}) []SpanAttribute {
+	attributes := []SpanAttribute{}
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		id := strings.TrimSuffix(segments[i], ".json")
+		switch segments[i-1] {
+		case "issues":
+			if issueID, err := strconv.Atoi(id); err == nil {
+				attributes = append(attributes, SpanAttribute{Key: "redmine.issue.id", Value: issueID})
+			}
+		case "projects":
+			attributes = append(attributes, SpanAttribute{Key: "redmine.project.id", Value: id})
+		}
+	}
+
+	if issue, ok := body.(*RdIssue); ok && issue.Project != 0 && !strings.Contains(path, "/projects/") {
+		attributes = append(attributes, SpanAttribute{Key: "redmine.project.id", Value: strconv.Itoa(issue.Project)})
+	}
+
+	return attributes
+}
+
+func propagationMiddleware(propagator Propagator) SendMiddleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			propagator.Inject(req.Context(), req.Header)
+			return next.RoundTrip(req)
+		})
+	}
+}
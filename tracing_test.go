@@ -0,0 +1,139 @@
+package redmineclient_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+type traceKey struct{}
+
+type testSpan struct {
+	name       string
+	parent     string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (span *testSpan) SetAttributes(attributes ...redmineclient.SpanAttribute) {
+	for _, attribute := range attributes {
+		span.attributes[attribute.Key] = attribute.Value
+	}
+}
+
+func (span *testSpan) RecordError(err error) { span.err = err }
+func (span *testSpan) End()                  { span.ended = true }
+
+// testTracer записывает спаны и передаёт имя спана в заголовке traceparent
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (tracer *testTracer) Start(ctx context.Context, name string) (context.Context, redmineclient.Span) {
+	parent, _ := ctx.Value(traceKey{}).(string)
+	span := &testSpan{name: name, parent: parent, attributes: map[string]interface{}{}}
+	tracer.spans = append(tracer.spans, span)
+
+	return context.WithValue(ctx, traceKey{}, name), span
+}
+
+func (tracer *testTracer) Inject(ctx context.Context, header http.Header) {
+	if name, ok := ctx.Value(traceKey{}).(string); ok {
+		header.Set("traceparent", name)
+	}
+}
+
+func TestTracerSpanAttributes(t *testing.T) {
+	srv := redminetest.NewServer()
+	defer srv.Close()
+	tracer := &testTracer{}
+	client := srv.Client(redmineclient.WithTracer(tracer))
+	project := client.CreateProject(&redmineclient.RdProject{Name: "Demo", Identifier: "demo"})
+	issue := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "traced"})
+	client.GetIssue(issue.ID)
+	client.GetIssue(999)
+
+	tests := []struct {
+		name       string
+		attributes map[string]interface{}
+		err        bool
+	}{
+		{"redmine POST /projects.json", map[string]interface{}{"http.request.method": "POST", "http.route": "/projects.json", "http.response.status_code": 201}, false},
+		{"redmine POST /issues.json", map[string]interface{}{"http.route": "/issues.json", "http.response.status_code": 201, "redmine.project.id": "1", "redmine.retry.count": 0}, false},
+		{"redmine GET /issues/{id}.json", map[string]interface{}{"http.request.method": "GET", "http.response.status_code": 200, "redmine.issue.id": issue.ID}, false},
+		{"redmine GET /issues/{id}.json", map[string]interface{}{"http.response.status_code": 404, "redmine.issue.id": 999, "error.type": "http"}, true},
+	}
+	if len(tracer.spans) != len(tests) {
+		t.Fatalf("%d spans, want %d", len(tracer.spans), len(tests))
+	}
+	for i, test := range tests {
+		span := tracer.spans[i]
+		if span.name != test.name || !span.ended || (span.err != nil) != test.err {
+			t.Errorf("span %d: %q ended %v error %v, want %q", i, span.name, span.ended, span.err, test.name)
+		}
+		for key, want := range test.attributes {
+			if got := span.attributes[key]; got != want {
+				t.Errorf("span %d %q: %v = %#v, want %#v", i, span.name, key, got, want)
+			}
+		}
+	}
+}
+
+func TestTracerPropagation(t *testing.T) {
+	var traceparents []string
+	record := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			traceparents = append(traceparents, req.Header.Get("traceparent"))
+			return next.RoundTrip(req)
+		})
+	}
+	tracer := &testTracer{}
+	_, client, project := newClient(t, redmineclient.WithTracer(tracer), redmineclient.WithSendMiddleware(record))
+
+	tracer.spans, traceparents = nil, nil
+	ctx := context.WithValue(context.Background(), traceKey{}, "request handler")
+	client.WithContext(ctx).GetProject(project.ID)
+
+	if len(tracer.spans) != 1 || tracer.spans[0].parent != "request handler" {
+		t.Fatalf("spans %+v, want one child of request handler", tracer.spans)
+	}
+	if len(traceparents) != 1 || traceparents[0] != "redmine GET /projects/{id}.json" {
+		t.Errorf("traceparent headers %q", traceparents)
+	}
+}
+
+func TestTracerRetryCount(t *testing.T) {
+	var attempts int32
+	srv := redminetest.NewServer()
+	defer srv.Close()
+	flaky := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	tracer := &testTracer{}
+	client := srv.Client(
+		redmineclient.WithTransport(flaky),
+		redmineclient.WithRetryPolicy(&redmineclient.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}),
+		redmineclient.WithTracer(tracer),
+	)
+
+	if user := client.GetCurrentUser(); user.Login != redminetest.AdminLogin {
+		t.Fatalf("current user %q", user.Login)
+	}
+	if len(tracer.spans) != 1 {
+		t.Fatalf("%d spans, want 1", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.attributes["redmine.retry.count"] != 2 || span.attributes["http.response.status_code"] != 200 || !strings.HasSuffix(span.name, "/users/current.json") {
+		t.Errorf("span %q attributes %v", span.name, span.attributes)
+	}
+}
@@ -0,0 +1,147 @@
+package redmineclient
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// redmineTransport транспорт клиента, через который проходят все запросы к redmine
+type redmineTransport struct {
+	base          http.RoundTripper
+	host          string
+	authenticator Authenticator
+	retry         *RetryPolicy
+	rateLimiter   *rateLimiter
+	inFlight      inFlightLimiter
+	errorHandler  func(err error)
+	dryRun        *Plan
+	audit         *auditLog
+	undo          *UndoJournal
+
+	callMiddleware []CallMiddleware
+	sendMiddleware []SendMiddleware
+}
+
+func newRedmineTransport() *redmineTransport {
+	return &redmineTransport{base: http.DefaultTransport}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func (transport *redmineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	scope := scopeFromContext(req.Context())
+	authenticator := transport.authenticator
+	if scope != nil {
+		req = scope.apply(req)
+		if scope.authenticator != nil {
+			authenticator = scope.authenticator
+		}
+	}
+	// учётные данные отправляются только на сервер redmine, но не при редиректе на другой хост
+	if authenticator != nil && (transport.host == "" || req.URL.Host == transport.host) {
+		req = req.Clone(req.Context())
+		authenticator.Authenticate(req)
+	}
+
+	var next http.RoundTripper = roundTripperFunc(transport.sendWithRetry)
+	for i := len(transport.sendMiddleware) - 1; i >= 0; i-- {
+		next = transport.sendMiddleware[i](next)
+	}
+	resp, err := next.RoundTrip(req)
+
+	return transport.checkResponse(scope, req, resp, err)
+}
+
+func (transport *redmineTransport) sendWithRetry(req *http.Request) (*http.Response, error) {
+	if transport.retry == nil {
+		return transport.send(req)
+	}
+
+	return transport.retry.roundTrip(roundTripperFunc(transport.send), req)
+}
+
+// checkResponse преобразование отказа в смене пользователя в ошибку
+// и передача ошибок обработчику клиента и копии клиента
+func (transport *redmineTransport) checkResponse(scope *requestScope, req *http.Request, resp *http.Response, err error) (*http.Response, error) {
+	login := req.Header.Get(switchUserHeader)
+	if err == nil && resp.StatusCode == http.StatusPreconditionFailed && login != "" {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		resp, err = nil, &ImpersonationError{Login: login}
+	}
+
+	requestErr := err
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		requestErr = newAPIError(req, resp)
+	}
+	if requestErr == nil {
+		return resp, err
+	}
+
+	if transport.errorHandler != nil {
+		transport.errorHandler(requestErr)
+	}
+	if scope != nil {
+		scope.captureError(requestErr)
+	}
+
+	return resp, err
+}
+
+// send одна попытка запроса с учётом ограничений частоты и количества одновременных запросов
+func (transport *redmineTransport) send(req *http.Request) (*http.Response, error) {
+	resp, err := transport.sendAttempt(req)
+	if stats := statsFromContext(req.Context()); stats != nil {
+		stats.attempts++
+		stats.statusCode, stats.date = 0, time.Time{}
+		if err == nil {
+			stats.statusCode = resp.StatusCode
+			stats.date, _ = http.ParseTime(resp.Header.Get("Date"))
+		}
+	}
+
+	return resp, err
+}
+
+func (transport *redmineTransport) sendAttempt(req *http.Request) (*http.Response, error) {
+	if transport.rateLimiter != nil {
+		if err := transport.rateLimiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+	if transport.inFlight == nil {
+		return transport.base.RoundTrip(req)
+	}
+
+	if err := transport.inFlight.Acquire(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := transport.base.RoundTrip(req)
+	if err != nil {
+		transport.inFlight.Release()
+		return nil, err
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: transport.inFlight.Release}
+
+	return resp, nil
+}
+
+// releaseBody освобождает слот запроса после закрытия тела ответа
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (body *releaseBody) Close() error {
+	err := body.ReadCloser.Close()
+	body.once.Do(body.release)
+
+	return err
+}
@@ -0,0 +1,176 @@
+package redmineclient
+
+import (
+	"strconv"
+	"time"
+)
+
+// IssueTree задача с подзадачами
+type IssueTree struct {
+	Issue    RdIssueData
+	Children []*IssueTree
+	// Closed статус задачи закрыт, GetIssueTree заполняет по справочнику статусов
+	Closed bool
+}
+
+// GetIssueTree задача со всеми подзадачами. Подзадачи всех уровней читаются одним списком
+// (parent_id=~id, по 100 на страницу), дерево строится по родителю каждой задачи.
+// Подзадачи, родитель которых недоступен пользователю, прикрепляются к корню.
+// Возвращает ошибку, если не удалось прочитать задачу или подзадачи
+func (arc *ApiRedmineClient) GetIssueTree(id int) (*IssueTree, error) {
+	root, err := issueData.get(arc, id, []string{"include=journals,attachments"})
+	if err != nil {
+		return nil, err
+	}
+	descendants, err := issueData.all(arc, []string{"parent_id=~" + strconv.Itoa(id), "status_id=*", "sort=id"})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := issueStatuses.list(arc, nil)
+	if err != nil {
+		return nil, err
+	}
+	closed := map[int]bool{}
+	for _, status := range statuses.Items {
+		closed[status.ID] = status.IsClosed
+	}
+
+	tree := &IssueTree{Issue: *root, Closed: closed[root.Status.ID]}
+	nodes := map[int]*IssueTree{id: tree}
+	for _, descendant := range descendants {
+		nodes[descendant.ID] = &IssueTree{Issue: descendant, Closed: closed[descendant.Status.ID]}
+	}
+	for _, descendant := range descendants {
+		if descendant.ID == id {
+			continue
+		}
+		parent, ok := nodes[descendant.Parent.ID]
+		if !ok {
+			parent = tree
+		}
+		parent.Children = append(parent.Children, nodes[descendant.ID])
+	}
+
+	return tree, nil
+}
+
+// Walk обход дерева, начиная с корня
+func (tree *IssueTree) Walk(fn func(node *IssueTree)) {
+	fn(tree)
+	for _, child := range tree.Children {
+		child.Walk(fn)
+	}
+}
+
+// Find узел дерева по id задачи
+func (tree *IssueTree) Find(id int) *IssueTree {
+	var found *IssueTree
+	tree.Walk(func(node *IssueTree) {
+		if found == nil && node.Issue.ID == id {
+			found = node
+		}
+	})
+
+	return found
+}
+
+// Leaves задачи без подзадач
+func (tree *IssueTree) Leaves() []*IssueTree {
+	leaves := []*IssueTree{}
+	tree.Walk(func(node *IssueTree) {
+		if len(node.Children) == 0 {
+			leaves = append(leaves, node)
+		}
+	})
+
+	return leaves
+}
+
+// TotalEstimatedHours оценка трудозатрат задачи и всех подзадач
+func (tree *IssueTree) TotalEstimatedHours() float64 {
+	total := 0.0
+	tree.Walk(func(node *IssueTree) {
+		if node.Issue.EstimatedHours != nil {
+			total += *node.Issue.EstimatedHours
+		}
+	})
+
+	return total
+}
+
+// TotalSpentHours затраченное время по задаче и всем подзадачам
+func (tree *IssueTree) TotalSpentHours() float64 {
+	total := 0.0
+	tree.Walk(func(node *IssueTree) {
+		total += node.Issue.SpentHours
+	})
+
+	return total
+}
+
+// DoneRatio готовность задачи, вычисленная по подзадачам, как в redmine (done_ratio из подзадач):
+// готовность каждой подзадачи берётся рекурсивно и взвешивается по её общей оценке трудозатрат
+// (total_estimated_hours), подзадачи без оценки учитываются со средней оценкой остальных подзадач
+// того же уровня, закрытые подзадачи считаются выполненными на 100%
+func (tree *IssueTree) DoneRatio() float64 {
+	if len(tree.Children) == 0 {
+		if tree.Issue.DoneRatio == nil {
+			return 0
+		}
+		return float64(*tree.Issue.DoneRatio)
+	}
+
+	estimated, total := 0, 0.0
+	for _, child := range tree.Children {
+		if hours := child.TotalEstimatedHours(); hours > 0 {
+			estimated++
+			total += hours
+		}
+	}
+	averageWeight := 1.0
+	if estimated > 0 {
+		averageWeight = total / float64(estimated)
+	}
+
+	done := 0.0
+	for _, child := range tree.Children {
+		weight := child.TotalEstimatedHours()
+		if weight <= 0 {
+			weight = averageWeight
+		}
+		ratio := 100.0
+		if !child.Closed {
+			ratio = child.DoneRatio()
+		}
+		done += weight * ratio
+	}
+
+	return done / (averageWeight * float64(len(tree.Children)))
+}
+
+// EarliestStartDate самая ранняя дата начала среди задачи и подзадач
+func (tree *IssueTree) EarliestStartDate() time.Time {
+	var earliest time.Time
+	tree.Walk(func(node *IssueTree) {
+		date := node.Issue.GetStartDate()
+		if !date.IsZero() && (earliest.IsZero() || date.Before(earliest)) {
+			earliest = date
+		}
+	})
+
+	return earliest
+}
+
+// LatestDueDate самый поздний срок среди задачи и подзадач
+func (tree *IssueTree) LatestDueDate() time.Time {
+	var latest time.Time
+	tree.Walk(func(node *IssueTree) {
+		date := node.Issue.GetDueDate()
+		if date.After(latest) {
+			latest = date
+		}
+	})
+
+	return latest
+}
@@ -0,0 +1,81 @@
+package redmineclient_test
+
+import (
+	"math"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+func TestGetIssueTree(t *testing.T) {
+	_, client, project := newClient(t)
+	root := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "root"})
+	child := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "child", Parent: root.ID})
+	grandchild := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "grandchild", Parent: child.ID})
+	closed := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "closed", Parent: root.ID, Status: redminetest.StatusClosed, EstimatedHours: redmineclient.Float(2)})
+	client.UpdateIssue(&redmineclient.RdIssue{ID: grandchild.ID, EstimatedHours: redmineclient.Float(4), DoneRatio: redmineclient.Int(50)})
+	client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "other"})
+
+	tree, err := client.GetIssueTree(root.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Issue.ID != root.ID || len(tree.Children) != 2 {
+		t.Fatalf("tree #%d with %d children", tree.Issue.ID, len(tree.Children))
+	}
+	if tree.Children[0].Issue.ID != child.ID || tree.Children[1].Issue.ID != closed.ID {
+		t.Errorf("children #%d #%d, want #%d #%d", tree.Children[0].Issue.ID, tree.Children[1].Issue.ID, child.ID, closed.ID)
+	}
+	if grandchildren := tree.Children[0].Children; len(grandchildren) != 1 || grandchildren[0].Issue.ID != grandchild.ID {
+		t.Errorf("grandchildren of #%d = %+v", child.ID, grandchildren)
+	}
+	if tree.Closed || tree.Children[0].Closed || !tree.Children[1].Closed {
+		t.Errorf("closed flags %v %v %v, want only #%d closed", tree.Closed, tree.Children[0].Closed, tree.Children[1].Closed, closed.ID)
+	}
+	// закрытая подзадача с оценкой 2ч считается выполненной, ветка child весит 4ч и готова на 50%
+	if ratio := tree.DoneRatio(); math.Abs(ratio-400.0/6) > 0.001 {
+		t.Errorf("DoneRatio() = %v, want %v", ratio, 400.0/6)
+	}
+}
+
+// node узел дерева с готовностью и собственной оценкой, нулевая оценка передаётся как null
+func node(doneRatio int, estimatedHours float64, closed bool, children ...*redmineclient.IssueTree) *redmineclient.IssueTree {
+	var hours *float64
+	if estimatedHours > 0 {
+		hours = redmineclient.Float(estimatedHours)
+	}
+	return &redmineclient.IssueTree{
+		Issue:    redmineclient.RdIssueData{DoneRatio: redmineclient.Int(doneRatio), EstimatedHours: hours},
+		Closed:   closed,
+		Children: children,
+	}
+}
+
+func TestIssueTreeDoneRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		tree *redmineclient.IssueTree
+		want float64
+	}{
+		{"leaf", node(30, 0, false), 30},
+		{"children without estimates", node(0, 0, false, node(20, 0, false), node(60, 0, false)), 40},
+		{"weighted by estimate", node(0, 0, false, node(100, 1, false), node(0, 3, false)), 25},
+		{"closed child is done", node(0, 0, false, node(0, 0, true), node(50, 0, false)), 75},
+		{"missing estimate uses average", node(0, 0, false, node(100, 2, false), node(0, 0, false)), 50},
+		{
+			"own estimate of a parent child counts",
+			node(0, 0, false, node(0, 6, false, node(100, 2, false)), node(0, 8, false)),
+			50,
+		},
+		{"nested", node(0, 0, false, node(0, 0, false, node(100, 0, false), node(0, 0, false)), node(100, 0, false)), 75},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if ratio := test.tree.DoneRatio(); math.Abs(ratio-test.want) > 0.001 {
+				t.Errorf("DoneRatio() = %v, want %v", ratio, test.want)
+			}
+		})
+	}
+}
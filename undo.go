@@ -0,0 +1,403 @@
+package redmineclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// undoResources ключ тела запроса по ресурсу, изменения которого записываются в журнал отмены
+var undoResources = map[string]string{"issues": "issue", "projects": "project", "versions": "version"}
+
+// undoIncludes связанные данные, нужные для восстановления полей ресурса
+var undoIncludes = map[string][]string{"project": {"trackers", "enabled_modules", "issue_custom_fields"}}
+
+// undoSkipFields поля, которые нельзя вернуть повторным обновлением
+var undoSkipFields = map[string]bool{"id": true, "notes": true, "private_notes": true, "uploads": true, "watcher_user_ids": true}
+
+// undoReadFields поля ответа, соответствующие полям запроса, если имя не выводится из суффикса _id/_ids
+var undoReadFields = map[string]string{
+	"parent_issue_id":        "parent",
+	"parent_id":              "parent",
+	"tracker_ids":            "trackers",
+	"issue_custom_field_ids": "issue_custom_fields",
+	"enabled_module_names":   "enabled_modules",
+	"effective_date":         "due_date",
+}
+
+// UndoOperation обновление сущности, записанное в журнал отмены
+type UndoOperation struct {
+	ID    int       `json:"id"`
+	Batch string    `json:"batch,omitempty"`
+	Time  time.Time `json:"time"`
+	// Resource issue, project или version
+	Resource string `json:"resource"`
+	// Path путь запроса обновления
+	Path string `json:"path"`
+	// Before значения изменённых полей до обновления в формате запроса
+	Before map[string]json.RawMessage `json:"before"`
+	// After значения тех же полей после обновления, для обнаружения конфликтов
+	After map[string]json.RawMessage `json:"after"`
+	// AfterError ошибка чтения сущности после обновления: After не заполнен,
+	// конфликты проверить нельзя, и откат операции не выполняется
+	AfterError string `json:"after_error,omitempty"`
+	Undone     bool   `json:"undone,omitempty"`
+}
+
+// UndoJournal журнал отмены обновлений задач, проектов и версий
+type UndoJournal struct {
+	mu         sync.Mutex
+	operations []*UndoOperation
+}
+
+// LoadUndoJournal журнал, сохранённый SaveFile
+func LoadUndoJournal(path string) (*UndoJournal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("redmineclient: read undo journal: %w", err)
+	}
+	journal := &UndoJournal{}
+	if err := json.Unmarshal(data, &journal.operations); err != nil {
+		return nil, fmt.Errorf("redmineclient: parse undo journal %v: %w", path, err)
+	}
+
+	return journal, nil
+}
+
+// SaveFile сохранение журнала в файл
+func (journal *UndoJournal) SaveFile(path string) error {
+	journal.mu.Lock()
+	data, err := json.MarshalIndent(journal.operations, "", "  ")
+	journal.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("redmineclient: write undo journal: %w", err)
+	}
+
+	return nil
+}
+
+// Operations операции журнала в порядке выполнения
+func (journal *UndoJournal) Operations() []UndoOperation {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+
+	operations := make([]UndoOperation, 0, len(journal.operations))
+	for _, operation := range journal.operations {
+		operations = append(operations, *operation)
+	}
+
+	return operations
+}
+
+func (journal *UndoJournal) add(operation *UndoOperation) {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+
+	operation.ID = len(journal.operations) + 1
+	journal.operations = append(journal.operations, operation)
+}
+
+// pending неотменённые операции, отобранные match, от последней к первой
+func (journal *UndoJournal) pending(match func(operation *UndoOperation) bool) []*UndoOperation {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+
+	operations := []*UndoOperation{}
+	for i := len(journal.operations) - 1; i >= 0; i-- {
+		if operation := journal.operations[i]; !operation.Undone && match(operation) {
+			operations = append(operations, operation)
+		}
+	}
+
+	return operations
+}
+
+func (journal *UndoJournal) markUndone(operation *UndoOperation) {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+
+	operation.Undone = true
+}
+
+// UndoConflictError сущность изменилась после операции, откат не выполнен
+type UndoConflictError struct {
+	Operation int
+	Path      string
+	Fields    []string
+}
+
+func (err *UndoConflictError) Error() string {
+	return fmt.Sprintf("redmineclient: undo operation %d: %v changed since update: %v", err.Operation, err.Path, strings.Join(err.Fields, ", "))
+}
+
+// WithUndoJournal запись прежних значений полей перед UpdateIssue, UpdateProject и UpdateVersion.
+// Для каждой операции выполняются дополнительные запросы сущности до и после обновления
+func WithUndoJournal(journal *UndoJournal) Option {
+	return func(arc *ApiRedmineClient) error {
+		arc.transport.undo = journal
+		return nil
+	}
+}
+
+// UndoBatch пакет, к которому относятся операции журнала отмены копии клиента
+func UndoBatch(batch string) RequestOption {
+	return func(scope *requestScope) {
+		scope.undoBatch = batch
+	}
+}
+
+// RollbackOperation откат операции журнала, при изменении сущности после неё *UndoConflictError.
+// Возвращает отменённые операции
+func (arc *ApiRedmineClient) RollbackOperation(journal *UndoJournal, id int) ([]UndoOperation, error) {
+	operations := journal.pending(func(operation *UndoOperation) bool {
+		return operation.ID == id
+	})
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("redmineclient: undo operation %d not found or already undone", id)
+	}
+
+	return arc.rollback(journal, operations)
+}
+
+// RollbackBatch откат операций пакета в обратном порядке. Если хотя бы одна сущность
+// изменилась после операции, ничего не откатывается и возвращается *UndoConflictError.
+// Откат не атомарен: при ошибке запроса посреди пакета уже откатившиеся операции остаются
+// отменёнными, остальные нет. Возвращаются отменённые операции, в том числе вместе с ошибкой,
+// повторный вызов откатывает оставшиеся
+func (arc *ApiRedmineClient) RollbackBatch(journal *UndoJournal, batch string) ([]UndoOperation, error) {
+	operations := journal.pending(func(operation *UndoOperation) bool {
+		return operation.Batch == batch
+	})
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("redmineclient: undo batch %q has no operations to roll back", batch)
+	}
+
+	return arc.rollback(journal, operations)
+}
+
+func (arc *ApiRedmineClient) rollback(journal *UndoJournal, operations []*UndoOperation) ([]UndoOperation, error) {
+	// значения, которые вернёт откат более поздних операций той же сущности
+	restored := map[string]map[string]json.RawMessage{}
+	for _, operation := range operations {
+		if operation.AfterError != "" {
+			return nil, fmt.Errorf("redmineclient: undo operation %d: %v state after update is unknown (%v), roll back manually",
+				operation.ID, operation.Path, operation.AfterError)
+		}
+		entity, err := arc.undoEntity(operation.Resource, operation.Path)
+		if err != nil {
+			return nil, err
+		}
+		if restored[operation.Path] == nil {
+			restored[operation.Path] = map[string]json.RawMessage{}
+		}
+
+		conflict := &UndoConflictError{Operation: operation.ID, Path: operation.Path}
+		for field, value := range operation.After {
+			current, ok := restored[operation.Path][field]
+			if !ok {
+				current = undoValue(entity, field, value)
+			}
+			if !jsonEqual(current, value) {
+				conflict.Fields = append(conflict.Fields, field)
+			}
+		}
+		if len(conflict.Fields) > 0 {
+			sort.Strings(conflict.Fields)
+			return nil, conflict
+		}
+		for field, value := range operation.Before {
+			restored[operation.Path][field] = value
+		}
+	}
+
+	client := arc.withScope(func(scope *requestScope) {
+		scope.skipUndo = true
+	})
+	undone := []UndoOperation{}
+	for _, operation := range operations {
+		body := map[string]map[string]json.RawMessage{operation.Resource: operation.Before}
+		if err := client.put(operation.Path, body, nil); err != nil {
+			return undone, err
+		}
+		journal.markUndone(operation)
+		undone = append(undone, *operation)
+	}
+
+	return undone, nil
+}
+
+// undoResource ресурс по пути обновления вида /issues/1.json
+func undoResource(path string) string {
+	segments := strings.Split(strings.Trim(strings.SplitN(path, "?", 2)[0], "/"), "/")
+	if len(segments) != 2 || !strings.HasSuffix(segments[1], ".json") {
+		return ""
+	}
+
+	return undoResources[segments[0]]
+}
+
+// undoCall обновление с записью прежних значений изменяемых полей в журнал
+func (arc *ApiRedmineClient) undoCall(call *Call, resource string, next CallHandler) {
+	data, err := json.Marshal(call.Body)
+	if err != nil {
+		call.Err = err
+		return
+	}
+	written := map[string]map[string]json.RawMessage{}
+	json.Unmarshal(data, &written)
+
+	path := strings.SplitN(call.Path, "?", 2)[0]
+	before, err := arc.undoEntity(resource, path)
+	if err != nil {
+		call.Err = err
+		return
+	}
+	operation := &UndoOperation{
+		Batch:    arc.scope.undoBatch,
+		Resource: resource,
+		Path:     path,
+		Before:   map[string]json.RawMessage{},
+		After:    map[string]json.RawMessage{},
+	}
+	for field, value := range written[resource] {
+		if !undoSkipFields[field] {
+			operation.Before[field] = undoValue(before, field, value)
+		}
+	}
+
+	next(call)
+	if call.Err != nil {
+		return
+	}
+
+	operation.Time = time.Now()
+	after, err := arc.undoEntity(resource, path)
+	if err != nil {
+		operation.AfterError = err.Error()
+		arc.transport.undo.add(operation)
+		return
+	}
+	for field, value := range written[resource] {
+		if !undoSkipFields[field] {
+			operation.After[field] = undoValue(after, field, value)
+		}
+	}
+	arc.transport.undo.add(operation)
+}
+
+// undoEntity сущность в том виде, в котором её возвращает redmine
+func (arc *ApiRedmineClient) undoEntity(resource, path string) (map[string]json.RawMessage, error) {
+	var err error
+	result := map[string]map[string]json.RawMessage{}
+	client := arc.withScope(func(scope *requestScope) {
+		scope.err = &err
+		scope.include = append(scope.include, undoIncludes[resource]...)
+	})
+	client.get(path, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result[resource] == nil {
+		return nil, fmt.Errorf("redmineclient: undo: unexpected response for %v", path)
+	}
+
+	return result[resource], nil
+}
+
+// undoValue значение поля запроса field по сущности из ответа redmine,
+// written записанное значение, по нему отбираются настраиваемые поля
+func undoValue(entity map[string]json.RawMessage, field string, written json.RawMessage) json.RawMessage {
+	readField := undoReadFields[field]
+	if readField == "" {
+		readField = field
+		if _, ok := entity[field]; !ok {
+			if strings.HasSuffix(field, "_ids") {
+				readField = strings.TrimSuffix(field, "_ids") + "s"
+			} else {
+				readField = strings.TrimSuffix(field, "_id")
+			}
+		}
+	}
+	value, ok := entity[readField]
+	if !ok {
+		return json.RawMessage("null")
+	}
+
+	switch {
+	case field == "custom_fields":
+		type customFieldValue struct {
+			ID    int             `json:"id"`
+			Value json.RawMessage `json:"value"`
+		}
+		writtenValues := []customFieldValue{}
+		json.Unmarshal(written, &writtenValues)
+		current := []customFieldValue{}
+		json.Unmarshal(value, &current)
+		values := []customFieldValue{}
+		for _, item := range writtenValues {
+			restored := customFieldValue{ID: item.ID, Value: json.RawMessage(`""`)}
+			for _, customField := range current {
+				if customField.ID == item.ID && customField.Value != nil {
+					restored.Value = customField.Value
+				}
+			}
+			values = append(values, restored)
+		}
+		return marshalRaw(values)
+	case field == "enabled_module_names":
+		modules := []struct {
+			Name string `json:"name"`
+		}{}
+		json.Unmarshal(value, &modules)
+		names := []string{}
+		for _, module := range modules {
+			names = append(names, module.Name)
+		}
+		return marshalRaw(names)
+	case strings.HasSuffix(field, "_ids"):
+		items := []struct {
+			ID int `json:"id"`
+		}{}
+		json.Unmarshal(value, &items)
+		ids := []int{}
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		return marshalRaw(ids)
+	case strings.HasSuffix(field, "_id") && readField != field:
+		item := struct {
+			ID *int `json:"id"`
+		}{}
+		json.Unmarshal(value, &item)
+		return marshalRaw(item.ID)
+	}
+
+	return value
+}
+
+func marshalRaw(value interface{}) json.RawMessage {
+	data, _ := json.Marshal(value)
+	return data
+}
+
+// jsonEqual равенство значений JSON без учёта форматирования и порядка ключей
+func jsonEqual(a, b json.RawMessage) bool {
+	var valueA, valueB interface{}
+	if json.Unmarshal(a, &valueA) != nil || json.Unmarshal(b, &valueB) != nil {
+		return string(a) == string(b)
+	}
+
+	return string(marshalRaw(valueA)) == string(marshalRaw(valueB))
+}
+
+func isUndoable(call *Call) bool {
+	return call.Method == http.MethodPut && undoResource(call.Path) != ""
+}
@@ -0,0 +1,69 @@
+package redmineclient_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	redmineclient "github.com/alex19pov31/redmine-client"
+	"github.com/alex19pov31/redmine-client/redminetest"
+)
+
+func TestUndoRollback(t *testing.T) {
+	journal := &redmineclient.UndoJournal{}
+	srv, client, project := newClient(t, redmineclient.WithUndoJournal(journal))
+	first := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "first", DoneRatio: redmineclient.Int(10)})
+	second := client.CreateIssue(&redmineclient.RdIssue{Project: project.ID, Subject: "second"})
+
+	client.UpdateProject(&redmineclient.RdProject{ID: project.ID, Description: "changed"})
+	batch := client.With(redmineclient.UndoBatch("bulk"))
+	batch.UpdateIssue(&redmineclient.RdIssue{ID: first.ID, Subject: "first!", DoneRatio: redmineclient.Int(50), Status: redminetest.StatusInProgress})
+	batch.UpdateIssue(&redmineclient.RdIssue{ID: second.ID, Subject: "second!", Parent: first.ID})
+	batch.UpdateIssue(&redmineclient.RdIssue{ID: first.ID, Subject: "first!!"})
+
+	path := filepath.Join(t.TempDir(), "undo.json")
+	if err := journal.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := redmineclient.LoadUndoJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operations := loaded.Operations(); len(operations) != 4 || operations[1].Batch != "bulk" || operations[0].Batch != "" {
+		t.Fatalf("journal = %+v", operations)
+	}
+
+	// задачу изменил кто-то другой: откат пакета останавливается на конфликте
+	other := srv.Client()
+	other.UpdateIssue(&redmineclient.RdIssue{ID: second.ID, Subject: "manual"})
+	var conflict *redmineclient.UndoConflictError
+	if _, err := client.RollbackBatch(loaded, "bulk"); !errors.As(err, &conflict) {
+		t.Fatalf("RollbackBatch() error = %v, want *UndoConflictError", err)
+	}
+	if subject := client.GetIssue(first.ID).Subject; subject != "first!!" {
+		t.Errorf("subject after conflict = %q, want unchanged", subject)
+	}
+
+	other.UpdateIssue(&redmineclient.RdIssue{ID: second.ID, Subject: "second!"})
+	rolledBack, err := client.RollbackBatch(loaded, "bulk")
+	if err != nil || len(rolledBack) != 3 {
+		t.Fatalf("RollbackBatch() = %d operations, %v", len(rolledBack), err)
+	}
+	firstIssue, secondIssue := client.GetIssue(first.ID), client.GetIssue(second.ID)
+	if firstIssue.Subject != "first" || *firstIssue.DoneRatio != 10 || firstIssue.Status.ID != redminetest.StatusNew {
+		t.Errorf("first = %q %d%% status %d", firstIssue.Subject, *firstIssue.DoneRatio, firstIssue.Status.ID)
+	}
+	if secondIssue.Subject != "second" || secondIssue.Parent.ID != 0 {
+		t.Errorf("second = %q parent #%d", secondIssue.Subject, secondIssue.Parent.ID)
+	}
+
+	if _, err := client.RollbackOperation(loaded, 1); err != nil {
+		t.Fatal(err)
+	}
+	if description := client.GetProject(project.ID).Description; description != "" {
+		t.Errorf("project description = %q, want restored", description)
+	}
+	if len(journal.Operations()) != 4 {
+		t.Errorf("rollback was journaled: %d operations", len(journal.Operations()))
+	}
+}